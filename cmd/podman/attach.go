@@ -3,8 +3,8 @@ package main
 import (
 	"os"
 
-	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/adapter"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
@@ -39,7 +39,6 @@ var (
 
 func attachCmd(c *cli.Context) error {
 	args := c.Args()
-	var ctr *libpod.Container
 	if err := validateFlags(c, attachFlags); err != nil {
 		return err
 	}
@@ -47,25 +46,31 @@ func attachCmd(c *cli.Context) error {
 		return errors.Errorf("attach requires the name or id of one running container or the latest flag")
 	}
 
-	runtime, err := libpodruntime.GetRuntime(c)
+	runtime, err := adapter.GetRuntime(c)
 	if err != nil {
 		return errors.Wrapf(err, "error creating libpod runtime")
 	}
 	defer runtime.Shutdown(false)
 
+	var name string
 	if c.Bool("latest") {
-		ctr, err = runtime.GetLatestContainer()
+		latestCtr, err := runtime.GetLatestContainer()
+		if err != nil {
+			return errors.Wrapf(err, "unable to get latest container")
+		}
+		name = latestCtr.ID()
 	} else {
-		ctr, err = runtime.LookupContainer(args[0])
+		name = args[0]
 	}
 
+	ctr, err := runtime.LookupContainer(name)
 	if err != nil {
-		return errors.Wrapf(err, "unable to exec into %s", args[0])
+		return errors.Wrapf(err, "unable to exec into %s", name)
 	}
 
 	conState, err := ctr.State()
 	if err != nil {
-		return errors.Wrapf(err, "unable to determine state of %s", args[0])
+		return errors.Wrapf(err, "unable to determine state of %s", name)
 	}
 	if conState != libpod.ContainerStateRunning {
 		return errors.Errorf("you can only attach to running containers")
@@ -76,7 +81,7 @@ func attachCmd(c *cli.Context) error {
 		inputStream = nil
 	}
 
-	if err := startAttachCtr(ctr, os.Stdout, os.Stderr, inputStream, c.String("detach-keys"), c.BoolT("sig-proxy"), false); err != nil {
+	if err := runtime.Attach(getContext(), ctr, os.Stdout, os.Stderr, inputStream, c.String("detach-keys"), c.BoolT("sig-proxy"), false); err != nil {
 		return errors.Wrapf(err, "error attaching to container %s", ctr.ID())
 	}
 
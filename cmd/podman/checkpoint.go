@@ -35,6 +35,10 @@ var (
 			Name:  "all, a",
 			Usage: "Checkpoint all running containers",
 		},
+		cli.StringFlag{
+			Name:  "export, e",
+			Usage: "Export the checkpoint to a tar.gz archive, so it can be restored on another host",
+		},
 		LatestFlag,
 	}
 	checkpointCommand = cli.Command{
@@ -62,11 +66,15 @@ func checkpointCmd(c *cli.Context) error {
 		Keep:           c.Bool("keep"),
 		KeepRunning:    c.Bool("leave-running"),
 		TCPEstablished: c.Bool("tcp-established"),
+		TargetFile:     c.String("export"),
 	}
 
 	if err := checkAllAndLatest(c); err != nil {
 		return err
 	}
+	if options.TargetFile != "" && (c.Bool("all") || len(c.Args()) > 1) {
+		return errors.Errorf("--export can only be used with a single container")
+	}
 
 	containers, lastError := getAllOrLatestContainers(c, runtime, libpod.ContainerStateRunning, "running")
 
@@ -20,7 +20,7 @@ var (
 	cleanupDescription = `
    podman container cleanup
 
-   Cleans up mount points and network stacks on one or more containers from the host. The container name or ID can be used. This command is used internally when running containers, but can also be used if container cleanup has failed when a container exits.
+   Cleans up mount points and network stacks on one or more containers from the host. The container name or ID can be used. This command is used internally when running containers, but can also be used if container cleanup has failed when a container exits. Containers whose restart policy applies are restarted instead of being cleaned up.
 `
 	cleanupCommand = cli.Command{
 		Name:         "cleanup",
@@ -6,12 +6,12 @@ import "github.com/urfave/cli"
 
 func getAppCommands() []cli.Command {
 	return []cli.Command{
-		attachCommand,
 		commitCommand,
 		buildCommand,
 		createCommand,
 		diffCommand,
-		execCommand,
+		eventsCommand,
+		healthcheckCommand,
 		killCommand,
 		kubeCommand,
 		loadCommand,
@@ -25,18 +25,18 @@ func getAppCommands() []cli.Command {
 		portCommand,
 		pushCommand,
 		playCommand,
+		renameCommand,
 		restartCommand,
 		rmCommand,
 		runCommand,
 		saveCommand,
 		searchCommand,
-		startCommand,
 		statsCommand,
 		stopCommand,
 		topCommand,
 		umountCommand,
 		unpauseCommand,
-		volumeCommand,
+		updateCommand,
 		waitCommand,
 	}
 }
@@ -53,20 +53,31 @@ func getImageSubCommands() []cli.Command {
 	}
 }
 
+func getVolumeSubCommands() []cli.Command {
+	return []cli.Command{
+		volumeCreateCommand,
+		volumeInspectCommand,
+		volumeLsCommand,
+		volumePruneCommand,
+		volumeRmCommand,
+	}
+}
+
 func getSystemSubCommands() []cli.Command {
-	return []cli.Command{infoCommand}
+	return []cli.Command{
+		infoCommand,
+		dfSystemCommand,
+	}
 }
 
 func getContainerSubCommands() []cli.Command {
 	return []cli.Command{
-		attachCommand,
 		checkpointCommand,
 		cleanupCommand,
 		containerExistsCommand,
 		commitCommand,
 		createCommand,
 		diffCommand,
-		execCommand,
 		exportCommand,
 		killCommand,
 		logsCommand,
@@ -76,18 +87,18 @@ func getContainerSubCommands() []cli.Command {
 		portCommand,
 		pruneContainersCommand,
 		refreshCommand,
+		renameCommand,
 		restartCommand,
 		restoreCommand,
 		rmCommand,
 		runCommand,
 		runlabelCommand,
-		startCommand,
 		statsCommand,
 		stopCommand,
 		topCommand,
 		umountCommand,
 		unpauseCommand,
-		//		updateCommand,
+		updateCommand,
 		waitCommand,
 	}
 }
@@ -110,6 +121,11 @@ func getMainAppFlags() []cli.Flag {
 			Usage:  "Path to default mounts file",
 			Hidden: true,
 		},
+		cli.StringFlag{
+			Name:   "volume-plugin-dir",
+			Usage:  "Path to the directory holding the sockets of volume plugins",
+			Hidden: true,
+		},
 		cli.StringSliceFlag{
 			Name:  "hooks-dir",
 			Usage: "Set the OCI hooks directory path (may be set multiple times)",
@@ -5,7 +5,9 @@ package main
 import "github.com/urfave/cli"
 
 func getAppCommands() []cli.Command {
-	return []cli.Command{}
+	return []cli.Command{
+		remoteRunCommand,
+	}
 }
 
 func getImageSubCommands() []cli.Command {
@@ -13,6 +15,12 @@ func getImageSubCommands() []cli.Command {
 }
 
 func getContainerSubCommands() []cli.Command {
+	return []cli.Command{
+		remoteRunCommand,
+	}
+}
+
+func getVolumeSubCommands() []cli.Command {
 	return []cli.Command{}
 }
 
@@ -311,6 +311,30 @@ var createFlags = []cli.Flag{
 		Name:  "group-add",
 		Usage: "Add additional groups to join (default [])",
 	},
+	cli.StringFlag{
+		Name:  "health-cmd",
+		Usage: "set a healthcheck command for the container ('none' disables the existing healthcheck)",
+	},
+	cli.StringFlag{
+		Name:  "health-interval",
+		Value: "30s",
+		Usage: "set an interval for the healthchecks",
+	},
+	cli.UintFlag{
+		Name:  "health-retries",
+		Value: 3,
+		Usage: "the number of retries allowed before a healthcheck is considered to be unhealthy",
+	},
+	cli.StringFlag{
+		Name:  "health-start-period",
+		Value: "0s",
+		Usage: "the initialization time needed for a container to bootstrap",
+	},
+	cli.StringFlag{
+		Name:  "health-timeout",
+		Value: "30s",
+		Usage: "the maximum time allowed to complete the healthcheck before an interval is considered failed",
+	},
 	cli.BoolFlag{
 		Name:   "help",
 		Hidden: true,
@@ -337,9 +361,13 @@ var createFlags = []cli.Flag{
 		Name:  "interactive, i",
 		Usage: "Keep STDIN open even if not attached",
 	},
-	cli.StringFlag{
+	cli.StringSliceFlag{
 		Name:  "ip",
-		Usage: "Specify a static IPv4 address for the container",
+		Usage: "Specify a static IPv4 address for the container, optionally prefixed with a network (`[network:]address`) (default [])",
+	},
+	cli.StringSliceFlag{
+		Name:  "ip6",
+		Usage: "Specify a static IPv6 address for the container, optionally prefixed with a network (`[network:]address`) (default [])",
 	},
 	cli.StringFlag{
 		Name:  "ipc",
@@ -359,7 +387,7 @@ var createFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:  "log-driver",
-		Usage: "Logging driver for the container",
+		Usage: "Logging driver for the container: k8s-file, journald or none (default \"k8s-file\")",
 	},
 	cli.StringSliceFlag{
 		Name:  "log-opt",
@@ -367,7 +395,7 @@ var createFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:  "mac-address",
-		Usage: "Container MAC address (e.g. 92:d0:c6:0a:29:33), not currently supported",
+		Usage: "Container MAC address (e.g. 92:d0:c6:0a:29:33)",
 	},
 	cli.StringFlag{
 		Name:  "memory, m",
@@ -395,6 +423,10 @@ var createFlags = []cli.Flag{
 		Usage: "Connect a container to a network",
 		Value: getDefaultNetwork(),
 	},
+	cli.BoolFlag{
+		Name:  "no-healthcheck",
+		Usage: "Disable healthchecks on container",
+	},
 	cli.BoolFlag{
 		Name:  "oom-kill-disable",
 		Usage: "Disable OOM Killer",
@@ -415,6 +447,14 @@ var createFlags = []cli.Flag{
 		Name:  "pod",
 		Usage: "Run container in an existing pod",
 	},
+	cli.StringFlag{
+		Name:  "port-forwarder",
+		Usage: "Forwarder of the published ports of a rootless container: slirp4netns or rootlessport (default \"slirp4netns\")",
+	},
+	cli.StringFlag{
+		Name:  "port-peer-address",
+		Usage: "Expose the address of peers to a rootless container with the rootlessport forwarder: none, proxy-protocol or socket (default \"none\")",
+	},
 	cli.BoolFlag{
 		Name:  "privileged",
 		Usage: "Give extended privileges to container",
@@ -437,7 +477,7 @@ var createFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:  "restart",
-		Usage: "Restart is not supported.  Please use a systemd unit file for restart",
+		Usage: "Restart policy to apply when a container exits (no, on-failure[:max_retries], always, unless-stopped)",
 	},
 	cli.BoolFlag{
 		Name:  "rm",
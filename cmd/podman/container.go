@@ -8,8 +8,12 @@ import (
 
 var (
 	containerSubCommands = []cli.Command{
+		attachCommand,
+		cpCommand,
+		execCommand,
 		exportCommand,
 		inspectCommand,
+		startCommand,
 	}
 	containerDescription = "Manage containers"
 	containerCommand     = cli.Command{
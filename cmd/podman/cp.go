@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	cpFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "pause",
+			Usage: "Pause the container while copying",
+		},
+	}
+	cpDescription = `Copy files/folders between a container and the local filesystem.
+
+   Use '-' as the source to read a tar archive from stdin and extract it
+   into a directory in the container, or as the destination to write a tar
+   archive of the source in the container to stdout.`
+	cpCommand = cli.Command{
+		Name:         "cp",
+		Usage:        "Copy files/folders between a container and the local filesystem",
+		Description:  cpDescription,
+		Flags:        sortFlags(cpFlags),
+		Action:       cpCmd,
+		ArgsUsage:    "[CONTAINER:]SRC_PATH [CONTAINER:]DEST_PATH",
+		OnUsageError: usageErrorHandler,
+	}
+)
+
+// cpCmd copies files between a container and the host
+func cpCmd(c *cli.Context) error {
+	if err := validateFlags(c, cpFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.Errorf("you must provide a source path and a destination path")
+	}
+	if os.Geteuid() != 0 {
+		rootless.SetSkipStorageSetup(true)
+	}
+
+	srcCtr, srcPath := parseCopyPath(args[0])
+	destCtr, destPath := parseCopyPath(args[1])
+	if srcCtr != "" && destCtr != "" {
+		return errors.Errorf("copying between containers is not supported")
+	}
+	if srcCtr == "" && destCtr == "" {
+		return errors.Errorf("invalid arguments %s, %s you must specify a container", args[0], args[1])
+	}
+	if srcPath == "" || destPath == "" {
+		return errors.Errorf("source and destination paths must not be empty")
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	if destCtr != "" {
+		return runtime.CopyToContainer(srcPath, destCtr, destPath, c.Bool("pause"))
+	}
+	if destPath == "-" && logrus.IsTerminal(os.Stdout) {
+		return errors.Errorf("refusing to write archive to terminal. Redirect stdout")
+	}
+	return runtime.CopyFromContainer(srcCtr, srcPath, destPath, c.Bool("pause"))
+}
+
+// parseCopyPath splits an argument of the form CONTAINER:PATH into the
+// container and the path. Arguments starting with a path are taken to be
+// local paths.
+func parseCopyPath(arg string) (string, string) {
+	if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, ".") {
+		return "", arg
+	}
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) == 1 {
+		return "", arg
+	}
+	return parts[0], parts[1]
+}
@@ -11,7 +11,9 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/containers/image/manifest"
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/cmd/podman/shared"
 	"github.com/containers/libpod/libpod"
@@ -127,6 +129,7 @@ func createContainer(c *cli.Context, runtime *libpod.Runtime) (*libpod.Container
 
 	imageName := ""
 	var data *inspect.ImageData = nil
+	var healthCheck *manifest.Schema2HealthConfig
 
 	if rootfs == "" && !rootless.SkipStorageSetup() {
 		var writer io.Writer
@@ -145,11 +148,22 @@ func createContainer(c *cli.Context, runtime *libpod.Runtime) (*libpod.Container
 		} else {
 			imageName = newImage.ID()
 		}
+
+		// The healthcheck is only present in Docker format images and
+		// is not part of the inspect data
+		healthCheck, err = newImage.GetHealthCheck(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "unable to get healthcheck for %s", c.Args()[0])
+		}
 	}
 	createConfig, err := parseCreateOpts(ctx, c, runtime, imageName, data)
 	if err != nil {
 		return nil, nil, err
 	}
+	createConfig.HealthCheck, err = makeHealthCheckFromCli(c, healthCheck)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	ctr, err := createContainerFromCreateConfig(runtime, createConfig, ctx, nil)
 	if err != nil {
@@ -167,6 +181,77 @@ func createContainer(c *cli.Context, runtime *libpod.Runtime) (*libpod.Container
 	return ctr, createConfig, nil
 }
 
+// makeHealthCheckFromCli merges the healthcheck of the image with the
+// --health-* options given on the command line. A nil healthcheck is
+// returned if the container should not have one.
+func makeHealthCheckFromCli(c *cli.Context, imageHealthCheck *manifest.Schema2HealthConfig) (*manifest.Schema2HealthConfig, error) {
+	healthFlags := []string{"health-cmd", "health-interval", "health-retries", "health-start-period", "health-timeout"}
+	if c.Bool("no-healthcheck") {
+		for _, flag := range healthFlags {
+			if c.IsSet(flag) {
+				return nil, errors.Errorf("--no-healthcheck conflicts with --%s", flag)
+			}
+		}
+		return nil, nil
+	}
+
+	hc := new(manifest.Schema2HealthConfig)
+	if imageHealthCheck != nil {
+		*hc = *imageHealthCheck
+	}
+	if c.IsSet("health-cmd") {
+		cmd := c.String("health-cmd")
+		if cmd == "" || strings.ToLower(cmd) == "none" {
+			return nil, nil
+		}
+		hc.Test = []string{"CMD-SHELL", cmd}
+	}
+	if len(hc.Test) == 0 || hc.Test[0] == "NONE" {
+		return nil, nil
+	}
+
+	// Values not set by the image or on the command line get the defaults
+	// of the corresponding flag
+	if c.IsSet("health-interval") || hc.Interval == 0 {
+		interval, err := time.ParseDuration(c.String("health-interval"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid healthcheck interval %q", c.String("health-interval"))
+		}
+		if interval < time.Second {
+			return nil, errors.Errorf("healthcheck interval must be at least 1 second")
+		}
+		hc.Interval = interval
+	}
+	if c.IsSet("health-retries") || hc.Retries == 0 {
+		if c.Uint("health-retries") < 1 {
+			return nil, errors.Errorf("healthcheck retries must be greater than 0")
+		}
+		hc.Retries = int(c.Uint("health-retries"))
+	}
+	if c.IsSet("health-timeout") || hc.Timeout == 0 {
+		timeout, err := time.ParseDuration(c.String("health-timeout"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid healthcheck timeout %q", c.String("health-timeout"))
+		}
+		if timeout < time.Second {
+			return nil, errors.Errorf("healthcheck timeout must be at least 1 second")
+		}
+		hc.Timeout = timeout
+	}
+	if c.IsSet("health-start-period") {
+		startPeriod, err := time.ParseDuration(c.String("health-start-period"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid healthcheck start-period %q", c.String("health-start-period"))
+		}
+		if startPeriod < 0 {
+			return nil, errors.Errorf("healthcheck start-period must be 0 seconds or greater")
+		}
+		hc.StartPeriod = startPeriod
+	}
+
+	return hc, nil
+}
+
 func parseSecurityOpt(config *cc.CreateConfig, securityOpts []string) error {
 	var (
 		labelOpts []string
@@ -320,19 +405,11 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		blkioWeight                                              uint16
 		namespaces                                               map[string]string
 	)
-	if c.IsSet("restart") {
-		return nil, errors.Errorf("--restart option is not supported.\nUse systemd unit files for restarting containers")
-	}
-
 	idmappings, err := util.ParseIDMapping(c.StringSlice("uidmap"), c.StringSlice("gidmap"), c.String("subuidname"), c.String("subgidname"))
 	if err != nil {
 		return nil, err
 	}
 
-	if c.String("mac-address") != "" {
-		return nil, errors.Errorf("--mac-address option not currently supported")
-	}
-
 	imageID := ""
 
 	inputCommand = c.Args()[1:]
@@ -399,6 +476,9 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 	if c.Bool("detach") && c.Bool("rm") {
 		return nil, errors.Errorf("--rm and --detach cannot be specified together")
 	}
+	if c.Bool("rm") && c.String("restart") != "" && c.String("restart") != libpod.RestartPolicyNo {
+		return nil, errors.Errorf("the --rm option conflicts with --restart")
+	}
 	if c.Int64("cpu-period") != 0 && c.Float64("cpus") > 0 {
 		return nil, errors.Errorf("--cpu-period and --cpus cannot be set together")
 	}
@@ -682,34 +762,37 @@ func parseCreateOpts(ctx context.Context, c *cli.Context, runtime *libpod.Runtim
 		Entrypoint:        entrypoint,
 		Env:               env,
 		//ExposedPorts:   ports,
-		GroupAdd:       c.StringSlice("group-add"),
-		Hostname:       c.String("hostname"),
-		HostAdd:        c.StringSlice("add-host"),
-		IDMappings:     idmappings,
-		Image:          imageName,
-		ImageID:        imageID,
-		Interactive:    c.Bool("interactive"),
-		IP6Address:     c.String("ipv6"),
-		IPAddress:      c.String("ip"),
-		Labels:         labels,
-		LinkLocalIP:    c.StringSlice("link-local-ip"),
-		LogDriver:      c.String("log-driver"),
-		LogDriverOpt:   c.StringSlice("log-opt"),
-		MacAddress:     c.String("mac-address"),
-		Name:           c.String("name"),
-		Network:        c.String("network"),
-		NetworkAlias:   c.StringSlice("network-alias"),
-		IpcMode:        ipcMode,
-		NetMode:        netMode,
-		UtsMode:        utsMode,
-		PidMode:        pidMode,
-		Pod:            podName,
-		Privileged:     c.Bool("privileged"),
-		Publish:        c.StringSlice("publish"),
-		PublishAll:     c.Bool("publish-all"),
-		PortBindings:   portBindings,
-		Quiet:          c.Bool("quiet"),
-		ReadOnlyRootfs: c.Bool("read-only"),
+		GroupAdd:        c.StringSlice("group-add"),
+		Hostname:        c.String("hostname"),
+		HostAdd:         c.StringSlice("add-host"),
+		IDMappings:      idmappings,
+		Image:           imageName,
+		ImageID:         imageID,
+		Interactive:     c.Bool("interactive"),
+		IP6Address:      c.StringSlice("ip6"),
+		IPAddress:       c.StringSlice("ip"),
+		Labels:          labels,
+		LinkLocalIP:     c.StringSlice("link-local-ip"),
+		LogDriver:       c.String("log-driver"),
+		LogDriverOpt:    c.StringSlice("log-opt"),
+		MacAddress:      c.String("mac-address"),
+		Name:            c.String("name"),
+		Network:         c.String("network"),
+		NetworkAlias:    c.StringSlice("network-alias"),
+		IpcMode:         ipcMode,
+		NetMode:         netMode,
+		UtsMode:         utsMode,
+		PidMode:         pidMode,
+		Pod:             podName,
+		Privileged:      c.Bool("privileged"),
+		Publish:         c.StringSlice("publish"),
+		PublishAll:      c.Bool("publish-all"),
+		PortBindings:    portBindings,
+		PortForwarder:   c.String("port-forwarder"),
+		PortPeerAddress: c.String("port-peer-address"),
+		Quiet:           c.Bool("quiet"),
+		ReadOnlyRootfs:  c.Bool("read-only"),
+		RestartPolicy:   c.String("restart"),
 		Resources: cc.CreateResourceConfig{
 			BlkioWeight:       blkioWeight,
 			BlkioWeightDevice: c.StringSlice("blkio-weight-device"),
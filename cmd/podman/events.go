@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod/events"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	eventsFlags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "filter",
+			Usage: "filter output",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "format the output using a Go template or 'json'",
+		},
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "show all events created since timestamp",
+		},
+		cli.BoolTFlag{
+			Name:  "stream",
+			Usage: "stream new events; for testing only",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "show all events until timestamp",
+		},
+	}
+	eventsDescription = "Monitor podman events"
+	eventsCommand     = cli.Command{
+		Name:                   "events",
+		Usage:                  "show podman events",
+		Description:            eventsDescription,
+		Flags:                  sortFlags(eventsFlags),
+		Action:                 eventsCmd,
+		ArgsUsage:              "",
+		UseShortOptionHandling: true,
+		OnUsageError:           usageErrorHandler,
+	}
+)
+
+func eventsCmd(c *cli.Context) error {
+	if err := validateFlags(c, eventsFlags); err != nil {
+		return err
+	}
+	if len(c.Args()) > 0 {
+		return errors.Errorf("'podman events' does not take any arguments")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	format := c.String("format")
+	var tmpl *template.Template
+	if format != "" && format != formats.JSONString {
+		// Allow the format to be given without a trailing newline, as with
+		// the other podman commands that accept a Go template
+		if !strings.HasSuffix(format, "\n") {
+			format += "\n"
+		}
+		tmpl, err = template.New("events").Parse(format)
+		if err != nil {
+			return errors.Wrapf(err, "invalid format %q", c.String("format"))
+		}
+	}
+
+	eventChannel := make(chan *events.Event)
+	errChannel := make(chan error)
+	readOpts := events.ReadOptions{
+		EventChannel: eventChannel,
+		Filters:      c.StringSlice("filter"),
+		Since:        c.String("since"),
+		Stream:       c.BoolT("stream"),
+		Until:        c.String("until"),
+	}
+	go func() {
+		errChannel <- runtime.Events(readOpts)
+	}()
+
+	for event := range eventChannel {
+		switch {
+		case tmpl != nil:
+			if err := tmpl.Execute(os.Stdout, event); err != nil {
+				return err
+			}
+		case format == formats.JSONString:
+			jsonStr, err := event.ToJSONString()
+			if err != nil {
+				return errors.Wrapf(err, "unable to format json")
+			}
+			fmt.Println(jsonStr)
+		default:
+			fmt.Println(event.ToHumanReadable())
+		}
+	}
+	return <-errChannel
+}
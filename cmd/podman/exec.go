@@ -2,10 +2,9 @@ package main
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/adapter"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
@@ -57,8 +56,6 @@ var (
 
 func execCmd(c *cli.Context) error {
 	args := c.Args()
-	var ctr *libpod.Container
-	var err error
 	argStart := 1
 	if len(args) < 1 && !c.Bool("latest") {
 		return errors.Errorf("you must provide one container name or id")
@@ -71,31 +68,26 @@ func execCmd(c *cli.Context) error {
 	}
 	rootless.SetSkipStorageSetup(true)
 	cmd := args[argStart:]
-	runtime, err := libpodruntime.GetRuntime(c)
+	runtime, err := adapter.GetRuntime(c)
 	if err != nil {
 		return errors.Wrapf(err, "error creating libpod runtime")
 	}
 	defer runtime.Shutdown(false)
 
+	var name string
 	if c.Bool("latest") {
-		ctr, err = runtime.GetLatestContainer()
+		latestCtr, err := runtime.GetLatestContainer()
+		if err != nil {
+			return errors.Wrapf(err, "unable to get latest container")
+		}
+		name = latestCtr.ID()
 	} else {
-		ctr, err = runtime.LookupContainer(args[0])
-	}
-	if err != nil {
-		return errors.Wrapf(err, "unable to exec into %s", args[0])
+		name = args[0]
 	}
 
-	pid, err := ctr.PID()
-	if err != nil {
-		return err
-	}
-	became, ret, err := rootless.JoinNS(uint(pid))
+	ctr, err := runtime.LookupContainer(name)
 	if err != nil {
-		return err
-	}
-	if became {
-		os.Exit(ret)
+		return errors.Wrapf(err, "unable to exec into %s", name)
 	}
 
 	// ENVIRONMENT VARIABLES
@@ -109,5 +101,14 @@ func execCmd(c *cli.Context) error {
 		envs = append(envs, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	return ctr.Exec(c.Bool("tty"), c.Bool("privileged"), envs, cmd, c.String("user"), c.String("workdir"))
+	config := &libpod.ExecConfig{
+		Command:    cmd,
+		Env:        envs,
+		Terminal:   c.Bool("tty"),
+		Privileged: c.Bool("privileged"),
+		User:       c.String("user"),
+		WorkDir:    c.String("workdir"),
+	}
+	exitCode, err = runtime.Exec(getContext(), ctr, config)
+	return err
 }
@@ -7,6 +7,7 @@ import (
 var (
 	generateSubCommands = []cli.Command{
 		containerKubeCommand,
+		generateSystemdCommand,
 	}
 
 	generateDescription = "Generate structured data based for a containers and pods"
@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
@@ -19,6 +22,10 @@ var (
 			Name:  "service, s",
 			Usage: "Generate YAML for kubernetes service object",
 		},
+		cli.StringFlag{
+			Name:  "output-dir",
+			Usage: "Write the YAML to files in `DIRECTORY`, including PersistentVolumeClaims for named volumes",
+		},
 	}
 	containerKubeDescription = "Generate Kubernetes Pod YAML"
 	containerKubeCommand     = cli.Command{
@@ -43,6 +50,7 @@ func generateKubeYAMLCmd(c *cli.Context) error {
 		marshalledPod     []byte
 		marshalledService []byte
 		servicePorts      []v1.ServicePort
+		claims            []*v1.PersistentVolumeClaim
 	)
 
 	if rootless.IsRootless() {
@@ -67,11 +75,17 @@ func generateKubeYAMLCmd(c *cli.Context) error {
 			return err
 		}
 		podYAML, servicePorts, err = pod.GenerateForKube()
+		if err == nil && c.IsSet("output-dir") {
+			claims, err = pod.GenerateKubePersistentVolumeClaims()
+		}
 	} else {
 		if len(container.Dependencies()) > 0 {
 			return errors.Wrapf(libpod.ErrNotImplemented, "containers with dependencies")
 		}
 		podYAML, err = container.GenerateForKube()
+		if err == nil && c.IsSet("output-dir") {
+			claims, err = container.GenerateKubePersistentVolumeClaims()
+		}
 	}
 	if err != nil {
 		return err
@@ -98,6 +112,9 @@ func generateKubeYAMLCmd(c *cli.Context) error {
 # Created with podman-%s
 `
 	output = append(output, []byte(fmt.Sprintf(header, podmanVersion.Version))...)
+	if c.IsSet("output-dir") {
+		return writeKubeYAMLFiles(c.String("output-dir"), output, podYAML, marshalledPod, marshalledService, claims)
+	}
 	output = append(output, marshalledPod...)
 	if c.Bool("service") {
 		output = append(output, []byte("---\n")...)
@@ -108,3 +125,39 @@ func generateKubeYAMLCmd(c *cli.Context) error {
 
 	return nil
 }
+
+// writeKubeYAMLFiles writes the pod, the service and the
+// PersistentVolumeClaims to separate files in dir, and prints their paths
+func writeKubeYAMLFiles(dir string, header []byte, podYAML *v1.Pod, marshalledPod, marshalledService []byte, claims []*v1.PersistentVolumeClaim) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "error creating output directory %s", dir)
+	}
+	writeFile := func(name string, content []byte) error {
+		path := filepath.Join(dir, name)
+		data := append(append([]byte{}, header...), content...)
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			return errors.Wrapf(err, "error writing %s", path)
+		}
+		fmt.Println(path)
+		return nil
+	}
+
+	for _, claim := range claims {
+		marshalledClaim, err := yaml.Marshal(claim)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(fmt.Sprintf("%s-pvc.yaml", claim.Name), marshalledClaim); err != nil {
+			return err
+		}
+	}
+	if err := writeFile(fmt.Sprintf("%s-pod.yaml", podYAML.Name), marshalledPod); err != nil {
+		return err
+	}
+	if len(marshalledService) > 0 {
+		if err := writeFile(fmt.Sprintf("%s-service.yaml", podYAML.Name), marshalledService); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/systemdgen"
+	podmanVersion "github.com/containers/libpod/version"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	generateSystemdFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "files, f",
+			Usage: "Write the unit files to the current working directory instead of stdout",
+		},
+		cli.BoolFlag{
+			Name:  "name, n",
+			Usage: "Use container and pod names instead of IDs",
+		},
+		cli.StringFlag{
+			Name:  "restart-policy",
+			Usage: "Restart policy of the units",
+			Value: "on-failure",
+		},
+		cli.UintFlag{
+			Name:  "timeout, t",
+			Usage: "Stop timeout override of the containers, in seconds",
+		},
+	}
+	generateSystemdDescription = `Generate systemd unit files that start, stop and clean up a container, or
+   all the containers of a pod.`
+	generateSystemdCommand = cli.Command{
+		Name:                   "systemd",
+		Usage:                  "Generate systemd unit files for a container or pod",
+		Description:            generateSystemdDescription,
+		Flags:                  sortFlags(generateSystemdFlags),
+		Action:                 generateSystemdCmd,
+		ArgsUsage:              "CONTAINER|POD-NAME",
+		UseShortOptionHandling: true,
+		OnUsageError:           usageErrorHandler,
+	}
+)
+
+func generateSystemdCmd(c *cli.Context) error {
+	var units []*systemdgen.ServiceInfo
+
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.Errorf("you must provide one container|pod ID or name")
+	}
+	if err := validateFlags(c, generateSystemdFlags); err != nil {
+		return err
+	}
+	if err := systemdgen.ValidateRestartPolicy(c.String("restart-policy")); err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrapf(err, "unable to determine the path of podman")
+	}
+
+	ctr, err := runtime.LookupContainer(args[0])
+	if err == nil {
+		if ctr.PodID() != "" {
+			return errors.Wrapf(libpod.ErrInvalidArg, "container %s is part of pod %s, generate units for the pod instead", ctr.ID(), ctr.PodID())
+		}
+		unit, err := containerServiceInfo(c, ctr, executable)
+		if err != nil {
+			return err
+		}
+		for _, dep := range ctr.Dependencies() {
+			depCtr, err := runtime.GetContainer(dep)
+			if err != nil {
+				return errors.Wrapf(err, "error looking up dependency %s of container %s", dep, ctr.ID())
+			}
+			unit.Requires = append(unit.Requires, containerServiceName(c, depCtr))
+		}
+		units = append(units, unit)
+	} else {
+		pod, err := runtime.LookupPod(args[0])
+		if err != nil {
+			return errors.Wrapf(err, "%s is not a container or pod", args[0])
+		}
+		units, err = podServiceInfos(c, pod, executable)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, unit := range units {
+		content, err := systemdgen.CreateSystemdUnitAsString(unit)
+		if err != nil {
+			return err
+		}
+		if !c.Bool("files") {
+			fmt.Println(content)
+			continue
+		}
+		path, err := filepath.Abs(unit.UnitFileName())
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			return errors.Wrapf(err, "error writing unit file %s", path)
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
+
+// podServiceInfos returns a unit for the infra container of the pod, which
+// represents the pod, followed by units for all other containers of the pod.
+// The ordering between the units follows the dependency graph of the pod.
+func podServiceInfos(c *cli.Context, pod *libpod.Pod, executable string) ([]*systemdgen.ServiceInfo, error) {
+	infraID, err := pod.InfraContainerID()
+	if err != nil {
+		return nil, err
+	}
+	if infraID == "" {
+		return nil, errors.Wrapf(libpod.ErrInvalidArg, "pod %s has no infra container", pod.ID())
+	}
+	deps, err := pod.ContainerDependencies()
+	if err != nil {
+		return nil, err
+	}
+	ctrs, err := pod.AllContainers()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ctrs, func(i, j int) bool {
+		return ctrs[i].Name() < ctrs[j].Name()
+	})
+
+	podServiceName := "pod-" + pod.ID()
+	if c.Bool("name") {
+		podServiceName = "pod-" + pod.Name()
+	}
+
+	serviceNames := make(map[string]string, len(ctrs))
+	for _, ctr := range ctrs {
+		if ctr.ID() == infraID {
+			serviceNames[ctr.ID()] = podServiceName
+		} else {
+			serviceNames[ctr.ID()] = containerServiceName(c, ctr)
+		}
+	}
+
+	var (
+		podUnit  *systemdgen.ServiceInfo
+		ctrUnits []*systemdgen.ServiceInfo
+	)
+	for _, ctr := range ctrs {
+		unit, err := containerServiceInfo(c, ctr, executable)
+		if err != nil {
+			return nil, err
+		}
+		unit.ServiceName = serviceNames[ctr.ID()]
+		if ctr.ID() == infraID {
+			podUnit = unit
+			continue
+		}
+		for _, dep := range deps[ctr.ID()] {
+			unit.Requires = append(unit.Requires, serviceNames[dep])
+		}
+		ctrUnits = append(ctrUnits, unit)
+	}
+	if podUnit == nil {
+		return nil, errors.Wrapf(libpod.ErrNoSuchCtr, "infra container %s of pod %s not found", infraID, pod.ID())
+	}
+	for _, unit := range ctrUnits {
+		podUnit.Wants = append(podUnit.Wants, unit.ServiceName)
+	}
+	return append([]*systemdgen.ServiceInfo{podUnit}, ctrUnits...), nil
+}
+
+// containerServiceInfo returns the unit for a single container
+func containerServiceInfo(c *cli.Context, ctr *libpod.Container, executable string) (*systemdgen.ServiceInfo, error) {
+	pidFile := ctr.Config().ConmonPidFile
+	if pidFile == "" {
+		return nil, errors.Errorf("container %s has no conmon PID file, recreate it with --conmon-pidfile to generate a unit for it", ctr.ID())
+	}
+	timeout := ctr.StopTimeout()
+	if c.IsSet("timeout") {
+		timeout = c.Uint("timeout")
+	}
+	container := ctr.ID()
+	if c.Bool("name") {
+		container = ctr.Name()
+	}
+	return &systemdgen.ServiceInfo{
+		ServiceName:   containerServiceName(c, ctr),
+		Container:     container,
+		Executable:    executable,
+		PIDFile:       pidFile,
+		RestartPolicy: c.String("restart-policy"),
+		StopTimeout:   timeout,
+		PodmanVersion: podmanVersion.Version,
+	}, nil
+}
+
+// containerServiceName returns the name of the unit of a container
+func containerServiceName(c *cli.Context, ctr *libpod.Container) string {
+	if c.Bool("name") {
+		return "container-" + ctr.Name()
+	}
+	return "container-" + ctr.ID()
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	healthcheckDescription = `Manage container healthchecks.
+
+Healthchecks are defined by the image of a container or with the --health-*
+options of podman create and podman run.`
+
+	healthcheckSubCommands = []cli.Command{
+		healthcheckRunCommand,
+	}
+	healthcheckCommand = cli.Command{
+		Name:                   "healthcheck",
+		Usage:                  "Manage container healthchecks",
+		Description:            healthcheckDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            healthcheckSubCommands,
+	}
+)
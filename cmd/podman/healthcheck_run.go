@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	healthcheckRunDescription = `
+	podman healthcheck run
+
+	Run the healthcheck of a container once and record the result. The exit
+	code is 0 if the container is healthy and 1 otherwise.
+`
+
+	healthcheckRunCommand = cli.Command{
+		Name:         "run",
+		Usage:        "Run the healthcheck of a container",
+		Description:  healthcheckRunDescription,
+		Action:       healthcheckRunCmd,
+		ArgsUsage:    "CONTAINER-NAME|CONTAINER-ID",
+		OnUsageError: usageErrorHandler,
+	}
+)
+
+func healthcheckRunCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) < 1 {
+		return errors.Errorf("container name or ID required")
+	}
+	if len(args) > 1 {
+		return errors.Errorf("healthcheck run accepts only one container")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	status, err := runtime.HealthCheck(args[0])
+	if err != nil {
+		return err
+	}
+	if status != libpod.HealthCheckSuccess {
+		// An unhealthy container is not a failure of podman itself
+		exitCode = 1
+		fmt.Println("unhealthy")
+		return nil
+	}
+	fmt.Println("healthy")
+	return nil
+}
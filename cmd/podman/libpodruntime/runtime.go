@@ -79,6 +79,9 @@ func GetRuntime(c *cli.Context) (*libpod.Runtime, error) {
 	if c.GlobalIsSet("default-mounts-file") {
 		options = append(options, libpod.WithDefaultMountsFile(c.GlobalString("default-mounts-file")))
 	}
+	if c.GlobalIsSet("volume-plugin-dir") {
+		options = append(options, libpod.WithVolumePluginDir(c.GlobalString("volume-plugin-dir")))
+	}
 	if c.GlobalIsSet("hooks-dir") {
 		options = append(options, libpod.WithHooksDir(c.GlobalStringSlice("hooks-dir")...))
 	}
@@ -29,6 +29,7 @@ var (
 var cmdsNotRequiringRootless = map[string]bool{
 	"help":    true,
 	"version": true,
+	"cp":      true,
 	"create":  true,
 	"exec":    true,
 	"export":  true,
@@ -87,7 +88,10 @@ func main() {
 	app.Version = version.Version
 
 	app.Commands = []cli.Command{
+		attachCommand,
 		containerCommand,
+		cpCommand,
+		execCommand,
 		exportCommand,
 		historyCommand,
 		imageCommand,
@@ -95,11 +99,14 @@ func main() {
 		importCommand,
 		infoCommand,
 		inspectCommand,
+		networkCommand,
 		pullCommand,
 		rmiCommand,
+		startCommand,
 		systemCommand,
 		tagCommand,
 		versionCommand,
+		volumeCommand,
 	}
 
 	app.Commands = append(app.Commands, getAppCommands()...)
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var (
+	networkDescription = `Manage CNI networks.
+
+Networks are described by CNI configuration files in the CNI configuration
+directory, and containers are attached to them with --network or with
+podman network connect.`
+
+	networkSubCommands = []cli.Command{
+		networkConnectCommand,
+		networkCreateCommand,
+		networkDisconnectCommand,
+		networkInspectCommand,
+		networkLsCommand,
+		networkRmCommand,
+	}
+	networkCommand = cli.Command{
+		Name:                   "network",
+		Usage:                  "Manage CNI networks",
+		Description:            networkDescription,
+		UseShortOptionHandling: true,
+		Subcommands:            networkSubCommands,
+	}
+)
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net"
+
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var networkConnectDescription = `
+podman network connect
+
+Connect a container to a CNI network. A running container is added to the
+network immediately, a stopped container joins it when it is started. The
+network is kept when the container is restarted.
+`
+
+var networkConnectFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "ip",
+		Usage: "Request a static IP address from the network for a running container",
+	},
+}
+
+var networkConnectCommand = cli.Command{
+	Name:                   "connect",
+	Usage:                  "Connect a container to a CNI network",
+	Description:            networkConnectDescription,
+	Flags:                  networkConnectFlags,
+	Action:                 networkConnectCmd,
+	SkipArgReorder:         true,
+	ArgsUsage:              "NETWORK-NAME CONTAINER",
+	UseShortOptionHandling: true,
+	OnUsageError:           usageErrorHandler,
+}
+
+func networkConnectCmd(c *cli.Context) error {
+	if err := validateFlags(c, networkConnectFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.Errorf("network name and container must be specified")
+	}
+
+	var ip net.IP
+	if c.IsSet("ip") {
+		if ip = net.ParseIP(c.String("ip")); ip == nil {
+			return errors.Errorf("invalid IP address %q", c.String("ip"))
+		}
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	if err := runtime.ConnectNetwork(args[0], args[1], ip); err != nil {
+		return errors.Wrapf(err, "failed to connect container %s to network %s", args[1], args[0])
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/containers/libpod/pkg/network"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var networkCreateDescription = `
+podman network create
+
+Creates a CNI network, by writing its configuration to the CNI configuration
+directory. Unless a subnet is given, a subnet that does not overlap with other
+networks or host interfaces is chosen.
+`
+
+var networkCreateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "driver, d",
+		Usage: "Driver of the network, bridge or macvlan",
+		Value: network.BridgeDriver,
+	},
+	cli.StringFlag{
+		Name:  "gateway",
+		Usage: "IPv4 or IPv6 gateway of the subnet, default is the first address of the subnet",
+	},
+	cli.BoolFlag{
+		Name:  "internal",
+		Usage: "Restrict external access to and from the network",
+	},
+	cli.StringFlag{
+		Name:  "ip-range",
+		Usage: "Allocate container addresses from a range within the subnet, in CIDR notation",
+	},
+	cli.StringSliceFlag{
+		Name:  "label, l",
+		Usage: "Set metadata for a network (default [])",
+	},
+	cli.StringSliceFlag{
+		Name:  "opt, o",
+		Usage: "Set driver specific options, mtu and for macvlan parent and mode (default [])",
+	},
+	cli.StringFlag{
+		Name:  "subnet",
+		Usage: "Subnet of the network in CIDR notation",
+	},
+}
+
+var networkCreateCommand = cli.Command{
+	Name:                   "create",
+	Usage:                  "Create a CNI network",
+	Description:            networkCreateDescription,
+	Flags:                  networkCreateFlags,
+	Action:                 networkCreateCmd,
+	SkipArgReorder:         true,
+	ArgsUsage:              "NETWORK-NAME",
+	UseShortOptionHandling: true,
+	OnUsageError:           usageErrorHandler,
+}
+
+func networkCreateCmd(c *cli.Context) error {
+	var err error
+
+	if err = validateFlags(c, networkCreateFlags); err != nil {
+		return err
+	}
+
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("network name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.Errorf("too many arguments, create takes 1 argument")
+	}
+
+	options := network.CreateOptions{
+		Name:     args[0],
+		Driver:   c.String("driver"),
+		Internal: c.Bool("internal"),
+	}
+	if c.IsSet("subnet") {
+		if options.Subnet, err = network.ParseSubnet(c.String("subnet")); err != nil {
+			return err
+		}
+	}
+	if c.IsSet("ip-range") {
+		if options.IPRange, err = network.ParseSubnet(c.String("ip-range")); err != nil {
+			return errors.Wrapf(err, "invalid IP range")
+		}
+	}
+	if c.IsSet("gateway") {
+		if options.Gateway = net.ParseIP(c.String("gateway")); options.Gateway == nil {
+			return errors.Errorf("invalid gateway %q", c.String("gateway"))
+		}
+	}
+	if options.Labels, err = getAllLabels([]string{}, c.StringSlice("label")); err != nil {
+		return errors.Wrapf(err, "unable to process labels")
+	}
+	if options.Options, err = getAllLabels([]string{}, c.StringSlice("opt")); err != nil {
+		return errors.Wrapf(err, "unable to process options")
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	created, err := runtime.CreateNetwork(options)
+	if err != nil {
+		return err
+	}
+	fmt.Println(created.Name)
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var networkDisconnectDescription = `
+podman network disconnect
+
+Disconnect a container from a CNI network. The interface of the network is
+removed from a running container immediately. A container cannot be
+disconnected from its only network.
+`
+
+var networkDisconnectCommand = cli.Command{
+	Name:           "disconnect",
+	Usage:          "Disconnect a container from a CNI network",
+	Description:    networkDisconnectDescription,
+	Action:         networkDisconnectCmd,
+	SkipArgReorder: true,
+	ArgsUsage:      "NETWORK-NAME CONTAINER",
+	OnUsageError:   usageErrorHandler,
+}
+
+func networkDisconnectCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.Errorf("network name and container must be specified")
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	if err := runtime.DisconnectNetwork(args[0], args[1]); err != nil {
+		return errors.Wrapf(err, "failed to disconnect container %s from network %s", args[1], args[0])
+	}
+	return nil
+}
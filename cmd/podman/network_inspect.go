@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var networkInspectDescription = `
+podman network inspect
+
+Display the CNI configuration of one or more networks. The configuration is
+displayed in JSON format by default, or using a Go template with --format.
+`
+
+var networkInspectFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format, f",
+		Usage: "Format network output using Go template, e.g. '{{.cniVersion}}'",
+	},
+}
+
+var networkInspectCommand = cli.Command{
+	Name:                   "inspect",
+	Usage:                  "Display the configuration of CNI networks",
+	Description:            networkInspectDescription,
+	Flags:                  networkInspectFlags,
+	Action:                 networkInspectCmd,
+	SkipArgReorder:         true,
+	ArgsUsage:              "NETWORK-NAME [NETWORK-NAME ...]",
+	UseShortOptionHandling: true,
+	OnUsageError:           usageErrorHandler,
+}
+
+func networkInspectCmd(c *cli.Context) error {
+	if err := validateFlags(c, networkInspectFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("network name must be specified")
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var (
+		configs   []interface{}
+		lastError error
+	)
+	for _, name := range args {
+		n, err := runtime.GetNetwork(name)
+		if err != nil {
+			if lastError != nil {
+				logrus.Errorf("%q", lastError)
+			}
+			lastError = errors.Wrapf(err, "error inspecting network %s", name)
+			continue
+		}
+		config := make(map[string]interface{})
+		if err := json.Unmarshal(n.Config, &config); err != nil {
+			return errors.Wrapf(err, "error decoding configuration of network %s", name)
+		}
+		configs = append(configs, config)
+	}
+
+	if len(configs) > 0 {
+		var out formats.Writer = formats.JSONStructArray{Output: configs}
+		if c.IsSet("format") && c.String("format") != formats.JSONString {
+			out = formats.StdoutTemplateArray{Output: configs, Template: c.String("format")}
+		}
+		if err := formats.Writer(out).Out(); err != nil {
+			return err
+		}
+	}
+	return lastError
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/containers/libpod/pkg/network"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// networkLsTemplateParams is the template parameters to list the networks
+type networkLsTemplateParams struct {
+	Name    string
+	Version string
+	Plugins string
+	Subnets string
+	Labels  string
+}
+
+var networkLsDescription = `
+podman network ls
+
+List all CNI networks configured in the CNI configuration directory. The
+output format can be changed to JSON or a user specified Go template.
+`
+
+var networkLsFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "Format network output using Go template",
+		Value: "table {{.Name}}\t{{.Version}}\t{{.Plugins}}",
+	},
+	cli.BoolFlag{
+		Name:  "quiet, q",
+		Usage: "Print network output in quiet mode",
+	},
+}
+
+var networkLsCommand = cli.Command{
+	Name:                   "ls",
+	Aliases:                []string{"list"},
+	Usage:                  "List CNI networks",
+	Description:            networkLsDescription,
+	Flags:                  networkLsFlags,
+	Action:                 networkLsCmd,
+	SkipArgReorder:         true,
+	UseShortOptionHandling: true,
+	OnUsageError:           usageErrorHandler,
+}
+
+func networkLsCmd(c *cli.Context) error {
+	if err := validateFlags(c, networkLsFlags); err != nil {
+		return err
+	}
+	if len(c.Args()) > 0 {
+		return errors.Errorf("too many arguments, ls takes no arguments")
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	networks, err := runtime.Networks()
+	if err != nil {
+		return err
+	}
+
+	format := strings.Replace(c.String("format"), `\t`, "\t", -1)
+	if c.Bool("quiet") {
+		format = "{{.Name}}"
+	}
+	return generateNetworkLsOutput(networks, format)
+}
+
+// generateNetworkLsOutput prints the networks in JSON format or using a Go
+// template
+func generateNetworkLsOutput(networks []*network.Network, format string) error {
+	var out formats.Writer
+
+	switch format {
+	case formats.JSONString:
+		output := make([]interface{}, 0, len(networks))
+		for _, n := range networks {
+			output = append(output, n)
+		}
+		out = formats.JSONStructArray{Output: output}
+	default:
+		if len(networks) == 0 {
+			return nil
+		}
+		output := make([]interface{}, 0, len(networks))
+		for _, n := range networks {
+			output = append(output, networkLsTemplateParams{
+				Name:    n.Name,
+				Version: n.CNIVersion,
+				Plugins: strings.Join(n.Plugins, ","),
+				Subnets: strings.Join(n.Subnets, ","),
+				Labels:  formatLabels(n.Labels),
+			})
+		}
+		out = formats.StdoutTemplateArray{Output: output, Template: format, Fields: networkLsHeaderMap()}
+	}
+	return formats.Writer(out).Out()
+}
+
+// networkLsHeaderMap returns the headers of the template parameters
+func networkLsHeaderMap() map[string]string {
+	v := reflect.TypeOf(networkLsTemplateParams{})
+	values := make(map[string]string)
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Field(i).Name
+		value := key
+		if value == "Name" {
+			value = "Network" + value
+		}
+		values[key] = strings.ToUpper(splitCamelCase(value))
+	}
+	return values
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var networkRmDescription = `
+podman network rm
+
+Remove one or more CNI networks by removing their configuration files. Networks
+used by containers are only removed with --force, which removes the containers
+as well.
+`
+
+var networkRmFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "force, f",
+		Usage: "Remove the containers using the network, and the network",
+	},
+}
+
+var networkRmCommand = cli.Command{
+	Name:                   "rm",
+	Aliases:                []string{"remove"},
+	Usage:                  "Remove one or more CNI networks",
+	Description:            networkRmDescription,
+	Flags:                  networkRmFlags,
+	Action:                 networkRmCmd,
+	SkipArgReorder:         true,
+	ArgsUsage:              "NETWORK-NAME [NETWORK-NAME ...]",
+	UseShortOptionHandling: true,
+	OnUsageError:           usageErrorHandler,
+}
+
+func networkRmCmd(c *cli.Context) error {
+	if err := validateFlags(c, networkRmFlags); err != nil {
+		return err
+	}
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("network name must be specified")
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	var lastError error
+	for _, name := range args {
+		if err := runtime.RemoveNetwork(getContext(), name, c.Bool("force")); err != nil {
+			if lastError != nil {
+				logrus.Errorf("%q", lastError)
+			}
+			lastError = errors.Wrapf(err, "failed to remove network %q", name)
+			continue
+		}
+		fmt.Println(name)
+	}
+	return lastError
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/containers/image/types"
@@ -22,6 +26,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 )
 
 var (
@@ -34,10 +41,18 @@ var (
 			Name:  "cert-dir",
 			Usage: "`Pathname` of a directory containing TLS certificates and keys",
 		},
+		cli.StringSliceFlag{
+			Name:  "configmap",
+			Usage: "`Pathname` of a YAML file containing a Kubernetes ConfigMap used by the pod (can be used multiple times)",
+		},
 		cli.StringFlag{
 			Name:  "creds",
 			Usage: "`Credentials` (USERNAME:PASSWORD) to use for authenticating to a registry",
 		},
+		cli.BoolFlag{
+			Name:  "down",
+			Usage: "Stop and remove the pods and volumes created by playing the file before",
+		},
 		cli.BoolFlag{
 			Name:  "quiet, q",
 			Usage: "Suppress output information when pulling images",
@@ -64,12 +79,25 @@ var (
 	}
 )
 
+// playKubeFileLabel is the label of the pods and volumes created by play kube,
+// holding the absolute path of the YAML file they were created from
+const playKubeFileLabel = "io.podman.play.kube.file"
+
+// kubeDeployment is the part of an apps/v1 Deployment used by play kube
+type kubeDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Replicas *int32             `json:"replicas,omitempty"`
+		Template v1.PodTemplateSpec `json:"template"`
+	} `json:"spec"`
+}
+
 func playKubeYAMLCmd(c *cli.Context) error {
 	var (
-		podOptions    []libpod.PodCreateOption
-		podYAML       v1.Pod
+		pods          []v1.Pod
+		services      []v1.Service
 		registryCreds *types.DockerAuthConfig
-		containers    []*libpod.Container
 		writer        io.Writer
 	)
 
@@ -85,44 +113,194 @@ func playKubeYAMLCmd(c *cli.Context) error {
 		return errors.New("you must supply at least one file")
 	}
 
+	kubeFile, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+
 	runtime, err := libpodruntime.GetRuntime(c)
 	if err != nil {
 		return errors.Wrapf(err, "could not get runtime")
 	}
 	defer runtime.Shutdown(false)
 
+	if c.Bool("down") {
+		return playKubeDown(ctx, runtime, kubeFile)
+	}
+
 	content, err := ioutil.ReadFile(args[0])
 	if err != nil {
 		return err
 	}
 
-	if err := yaml.Unmarshal(content, &podYAML); err != nil {
+	configMaps, err := readConfigMaps(c.StringSlice("configmap"))
+	if err != nil {
+		return err
+	}
+
+	documents, err := splitKubeYAML(content)
+	if err != nil {
 		return errors.Wrapf(err, "unable to read %s as YAML", args[0])
 	}
+	for _, document := range documents {
+		// Skip documents holding only comments
+		var fields map[string]interface{}
+		if err := yaml.Unmarshal(document, &fields); err != nil {
+			return errors.Wrapf(err, "unable to read %s as YAML", args[0])
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		var typeMeta metav1.TypeMeta
+		if err := yaml.Unmarshal(document, &typeMeta); err != nil {
+			return errors.Wrapf(err, "unable to read %s as YAML", args[0])
+		}
+		switch typeMeta.Kind {
+		// Files without a kind are read as a pod, like before other
+		// kinds were supported
+		case "Pod", "":
+			var podYAML v1.Pod
+			if err := yaml.Unmarshal(document, &podYAML); err != nil {
+				return errors.Wrapf(err, "unable to read pod in %s", args[0])
+			}
+			pods = append(pods, podYAML)
+		case "Deployment":
+			var deployment kubeDeployment
+			if err := yaml.Unmarshal(document, &deployment); err != nil {
+				return errors.Wrapf(err, "unable to read deployment in %s", args[0])
+			}
+			deploymentPods, err := deploymentToPods(deployment)
+			if err != nil {
+				return err
+			}
+			pods = append(pods, deploymentPods...)
+		case "Service":
+			var service v1.Service
+			if err := yaml.Unmarshal(document, &service); err != nil {
+				return errors.Wrapf(err, "unable to read service in %s", args[0])
+			}
+			services = append(services, service)
+		case "ConfigMap":
+			var configMap v1.ConfigMap
+			if err := yaml.Unmarshal(document, &configMap); err != nil {
+				return errors.Wrapf(err, "unable to read ConfigMap in %s", args[0])
+			}
+			configMaps[configMap.Name] = configMap
+		default:
+			return errors.Errorf("kind %s in %s is not supported, only Pod, Deployment, Service and ConfigMap are", typeMeta.Kind, args[0])
+		}
+	}
+	if len(pods) == 0 {
+		return errors.Errorf("no pods or deployments found in %s", args[0])
+	}
+
+	if !c.Bool("quiet") {
+		writer = os.Stderr
+	}
+
+	dockerRegistryOptions := image2.DockerRegistryOptions{
+		DockerRegistryCreds: registryCreds,
+		DockerCertPath:      c.String("cert-dir"),
+	}
+	if c.IsSet("tls-verify") {
+		dockerRegistryOptions.DockerInsecureSkipTLSVerify = types.NewOptionalBool(!c.BoolT("tls-verify"))
+	}
+
+	// Volumes of the file that exist before are kept if a pod fails
+	existingVolumes := make(map[string]bool)
+	volumes, err := playKubeVolumes(runtime, kubeFile)
+	if err != nil {
+		return err
+	}
+	for _, volume := range volumes {
+		existingVolumes[volume.Name()] = true
+	}
+
+	var createdPods []*libpod.Pod
+	publishedPorts := make(map[int32]bool)
+	for _, podYAML := range pods {
+		podPorts := append(getPodPorts(podYAML.Spec.Containers), getServicePorts(services, podYAML, publishedPorts)...)
+		pod, err := playKubePod(ctx, c, runtime, podYAML, podPorts, configMaps, kubeFile, &dockerRegistryOptions, writer)
+		if pod != nil {
+			createdPods = append(createdPods, pod)
+		}
+		if err != nil {
+			playKubeCleanup(ctx, runtime, kubeFile, createdPods, existingVolumes)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// playKubeCleanup removes the pods created from a file, and the volumes
+// created for them, after a pod of the file failed
+func playKubeCleanup(ctx context.Context, runtime *libpod.Runtime, kubeFile string, pods []*libpod.Pod, existingVolumes map[string]bool) {
+	for _, pod := range pods {
+		if err := runtime.RemovePod(ctx, pod, true, true); err != nil {
+			logrus.Errorf("Error removing pod %s: %v", pod.ID(), err)
+		}
+	}
+
+	volumes, err := playKubeVolumes(runtime, kubeFile)
+	if err != nil {
+		logrus.Errorf("Error listing volumes created from %s: %v", kubeFile, err)
+		return
+	}
+	for _, volume := range volumes {
+		if existingVolumes[volume.Name()] {
+			continue
+		}
+		if err := runtime.RemoveVolume(ctx, volume, false, false); err != nil {
+			logrus.Errorf("Error removing volume %s: %v", volume.Name(), err)
+		}
+	}
+}
+
+// playKubePod creates a pod and its containers from a kube pod, and starts
+// them. The pod and the volumes created for it are labeled with the file they
+// were created from. The pod is returned once created, even if creating or
+// starting its containers fails.
+func playKubePod(ctx context.Context, c *cli.Context, runtime *libpod.Runtime, podYAML v1.Pod, podPorts []ocicni.PortMapping, configMaps map[string]v1.ConfigMap, kubeFile string, dockerRegistryOptions *image2.DockerRegistryOptions, writer io.Writer) (*libpod.Pod, error) {
+	var (
+		podOptions []libpod.PodCreateOption
+		containers []*libpod.Container
+	)
+
+	labels := make(map[string]string)
+	for key, value := range podYAML.Labels {
+		labels[key] = value
+	}
+	labels[playKubeFileLabel] = kubeFile
 
 	podOptions = append(podOptions, libpod.WithInfraContainer())
 	podOptions = append(podOptions, libpod.WithPodName(podYAML.ObjectMeta.Name))
+	podOptions = append(podOptions, libpod.WithPodLabels(labels))
 	// TODO for now we just used the default kernel namespaces; we need to add/subtract this from yaml
 
 	nsOptions, err := shared.GetNamespaceOptions(strings.Split(DefaultKernelNamespaces, ","))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	podOptions = append(podOptions, nsOptions...)
-	podPorts := getPodPorts(podYAML.Spec.Containers)
 	podOptions = append(podOptions, libpod.WithInfraContainerPorts(podPorts))
 
 	// Create the Pod
 	pod, err := runtime.NewPod(ctx, podOptions...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Print the Pod's ID
 	fmt.Println(pod.ID())
 
+	volumes, err := kubeVolumesToHostPaths(ctx, runtime, pod, podYAML.Spec.Volumes, configMaps, map[string]string{playKubeFileLabel: kubeFile})
+	if err != nil {
+		return pod, err
+	}
+
 	podInfraID, err := pod.InfraContainerID()
 	if err != nil {
-		return err
+		return pod, err
 	}
 
 	namespaces := map[string]string{
@@ -133,30 +311,19 @@ func playKubeYAMLCmd(c *cli.Context) error {
 		"ipc":  fmt.Sprintf("container:%s", podInfraID),
 		"uts":  fmt.Sprintf("container:%s", podInfraID),
 	}
-	if !c.Bool("quiet") {
-		writer = os.Stderr
-	}
-
-	dockerRegistryOptions := image2.DockerRegistryOptions{
-		DockerRegistryCreds: registryCreds,
-		DockerCertPath:      c.String("cert-dir"),
-	}
-	if c.IsSet("tls-verify") {
-		dockerRegistryOptions.DockerInsecureSkipTLSVerify = types.NewOptionalBool(!c.BoolT("tls-verify"))
-	}
 
 	for _, container := range podYAML.Spec.Containers {
-		newImage, err := runtime.ImageRuntime().New(ctx, container.Image, c.String("signature-policy"), c.String("authfile"), writer, &dockerRegistryOptions, image2.SigningOptions{}, false, nil)
+		newImage, err := runtime.ImageRuntime().New(ctx, container.Image, c.String("signature-policy"), c.String("authfile"), writer, dockerRegistryOptions, image2.SigningOptions{}, false, nil)
 		if err != nil {
-			return err
+			return pod, err
 		}
-		createConfig := kubeContainerToCreateConfig(container, runtime, newImage, namespaces)
+		createConfig, err := kubeContainerToCreateConfig(container, runtime, newImage, namespaces, volumes, configMaps)
 		if err != nil {
-			return err
+			return pod, err
 		}
 		ctr, err := createContainerFromCreateConfig(runtime, createConfig, ctx, pod)
 		if err != nil {
-			return err
+			return pod, err
 		}
 		containers = append(containers, ctr)
 	}
@@ -166,14 +333,180 @@ func playKubeYAMLCmd(c *cli.Context) error {
 		if err := ctr.Start(ctx); err != nil {
 			// Making this a hard failure here to avoid a mess
 			// the other containers are in created status
-			return err
+			return pod, err
 		}
 		fmt.Println(ctr.ID())
 	}
 
+	return pod, nil
+}
+
+// playKubeDown stops and removes the pods and volumes created by play kube
+// from the given file
+func playKubeDown(ctx context.Context, runtime *libpod.Runtime, kubeFile string) error {
+	pods, err := runtime.Pods(func(pod *libpod.Pod) bool {
+		return pod.Labels()[playKubeFileLabel] == kubeFile
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if err := runtime.RemovePod(ctx, pod, true, true); err != nil {
+			return errors.Wrapf(err, "error removing pod %s", pod.ID())
+		}
+		fmt.Println(pod.ID())
+	}
+
+	volumes, err := playKubeVolumes(runtime, kubeFile)
+	if err != nil {
+		return err
+	}
+	// Volumes still used by other containers are kept
+	for _, volume := range volumes {
+		if err := runtime.RemoveVolume(ctx, volume, false, false); err != nil {
+			if errors.Cause(err) == libpod.ErrVolumeBeingUsed {
+				logrus.Warnf("Not removing volume %s: %v", volume.Name(), err)
+				continue
+			}
+			return errors.Wrapf(err, "error removing volume %s", volume.Name())
+		}
+		fmt.Println(volume.Name())
+	}
+
 	return nil
 }
 
+// playKubeVolumes returns the volumes created by play kube from the given file
+func playKubeVolumes(runtime *libpod.Runtime, kubeFile string) ([]*libpod.Volume, error) {
+	return runtime.Volumes(func(volume *libpod.Volume) bool {
+		return volume.Labels()[playKubeFileLabel] == kubeFile
+	})
+}
+
+// splitKubeYAML splits a multi-document YAML file into its non-empty documents
+func splitKubeYAML(content []byte) ([][]byte, error) {
+	var documents [][]byte
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// deploymentToPods returns the pods of a deployment, one per replica. The pods
+// and their containers are named after the deployment, so that replicas do not
+// conflict. Deployments with several replicas cannot use host ports, as the
+// replicas would publish the same ports.
+func deploymentToPods(deployment kubeDeployment) ([]v1.Pod, error) {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	if replicas > 1 {
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.HostPort != 0 {
+					return nil, errors.Errorf("deployment %s has %d replicas but container %s uses host port %d, host ports can only be used with a single replica", deployment.Name, replicas, container.Name, port.HostPort)
+				}
+			}
+		}
+	}
+
+	var pods []v1.Pod
+	for i := int32(0); i < replicas; i++ {
+		var pod v1.Pod
+		deployment.Spec.Template.ObjectMeta.DeepCopyInto(&pod.ObjectMeta)
+		deployment.Spec.Template.Spec.DeepCopyInto(&pod.Spec)
+		pod.Name = fmt.Sprintf("%s-pod-%d", deployment.Name, i)
+		for j := range pod.Spec.Containers {
+			pod.Spec.Containers[j].Name = fmt.Sprintf("%s-%s", pod.Name, pod.Spec.Containers[j].Name)
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// getServicePorts returns the ports to publish for a pod, for the node ports
+// of the NodePort services selecting it. Node ports already published for
+// another pod are skipped.
+func getServicePorts(services []v1.Service, podYAML v1.Pod, publishedPorts map[int32]bool) []ocicni.PortMapping {
+	var ports []ocicni.PortMapping
+	for _, service := range services {
+		if service.Spec.Type != v1.ServiceTypeNodePort || !serviceSelectsPod(service, podYAML) {
+			continue
+		}
+		for _, port := range service.Spec.Ports {
+			if port.NodePort == 0 {
+				continue
+			}
+			if publishedPorts[port.NodePort] {
+				logrus.Debugf("Node port %d of service %s is already published, not publishing it for pod %s", port.NodePort, service.Name, podYAML.Name)
+				continue
+			}
+			containerPort := resolveTargetPort(port, podYAML.Spec.Containers)
+			if containerPort == 0 {
+				logrus.Debugf("Target port %s of service %s not found in pod %s", port.TargetPort.String(), service.Name, podYAML.Name)
+				continue
+			}
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = v1.ProtocolTCP
+			}
+			ports = append(ports, ocicni.PortMapping{
+				HostPort:      port.NodePort,
+				ContainerPort: containerPort,
+				Protocol:      strings.ToLower(string(protocol)),
+			})
+			publishedPorts[port.NodePort] = true
+		}
+	}
+	return ports
+}
+
+// serviceSelectsPod returns whether the selector of a service matches the
+// labels of a pod
+func serviceSelectsPod(service v1.Service, podYAML v1.Pod) bool {
+	if len(service.Spec.Selector) == 0 {
+		return false
+	}
+	for key, value := range service.Spec.Selector {
+		if podYAML.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveTargetPort returns the container port a service port targets, looking
+// up named ports in the containers of the pod. It returns 0 if a named port is
+// not found.
+func resolveTargetPort(port v1.ServicePort, containers []v1.Container) int32 {
+	switch {
+	case port.TargetPort.Type == intstr.String && port.TargetPort.StrVal != "":
+		for _, container := range containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == port.TargetPort.StrVal {
+					return containerPort.ContainerPort
+				}
+			}
+		}
+		return 0
+	case port.TargetPort.IntVal != 0:
+		return port.TargetPort.IntVal
+	}
+	return port.Port
+}
+
 // getPodPorts converts a slice of kube container descriptions to an
 // array of ocicni portmapping descriptions usable in libpod
 func getPodPorts(containers []v1.Container) []ocicni.PortMapping {
@@ -194,12 +527,268 @@ func getPodPorts(containers []v1.Container) []ocicni.PortMapping {
 	return infraPorts
 }
 
+// kubeVolume is a volume of a kube pod, as mounted into its containers
+type kubeVolume struct {
+	// hostPath is the path on the host the volume is mounted from
+	hostPath string
+	// readOnly is whether the volume must be mounted read-only
+	readOnly bool
+}
+
+// readConfigMaps reads the Kubernetes ConfigMaps in the given YAML files, and
+// returns them by name
+func readConfigMaps(paths []string) (map[string]v1.ConfigMap, error) {
+	configMaps := make(map[string]v1.ConfigMap)
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var configMap v1.ConfigMap
+		if err := yaml.Unmarshal(content, &configMap); err != nil {
+			return nil, errors.Wrapf(err, "unable to read %s as YAML", path)
+		}
+		if configMap.Kind != "" && configMap.Kind != "ConfigMap" {
+			return nil, errors.Errorf("%s does not contain a ConfigMap but a %s", path, configMap.Kind)
+		}
+		if configMap.Name == "" {
+			return nil, errors.Errorf("the ConfigMap in %s has no name", path)
+		}
+		configMaps[configMap.Name] = configMap
+	}
+	return configMaps, nil
+}
+
+// kubeVolumesToHostPaths prepares the volumes of a kube pod on the host, and
+// returns them by name. hostPath volumes are bind mounted, emptyDir volumes
+// become new local volumes, persistentVolumeClaims become named volumes that
+// are created if they do not exist, and configMap volumes become new local
+// volumes holding the data of the ConfigMap as files. The volumes created are
+// given the labels. The local volumes of emptyDir and configMap volumes belong
+// to the pod, and are removed along with it.
+func kubeVolumesToHostPaths(ctx context.Context, runtime *libpod.Runtime, pod *libpod.Pod, volumes []v1.Volume, configMaps map[string]v1.ConfigMap, labels map[string]string) (map[string]kubeVolume, error) {
+	kubeVolumes := make(map[string]kubeVolume)
+	for _, volume := range volumes {
+		var (
+			kubeVol kubeVolume
+			err     error
+		)
+		switch {
+		case volume.HostPath != nil:
+			if err := prepareHostPath(volume.HostPath); err != nil {
+				return nil, errors.Wrapf(err, "error preparing hostPath of volume %s", volume.Name)
+			}
+			kubeVol.hostPath = volume.HostPath.Path
+		case volume.EmptyDir != nil:
+			if volume.EmptyDir.Medium == v1.StorageMediumMemory {
+				logrus.Debugf("Memory medium of emptyDir volume %s is not supported, using a local volume", volume.Name)
+			}
+			newVolume, err := runtime.NewVolume(ctx, libpod.WithVolumeLabels(labels), libpod.WithVolumePod(pod))
+			if err != nil {
+				return nil, errors.Wrapf(err, "error creating local volume for emptyDir volume %s", volume.Name)
+			}
+			kubeVol.hostPath = newVolume.MountSource()
+		case volume.PersistentVolumeClaim != nil:
+			kubeVol.hostPath, err = getOrCreateNamedVolume(ctx, runtime, volume.PersistentVolumeClaim.ClaimName, labels)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error getting volume for persistentVolumeClaim volume %s", volume.Name)
+			}
+			kubeVol.readOnly = volume.PersistentVolumeClaim.ReadOnly
+		case volume.ConfigMap != nil:
+			kubeVol.hostPath, err = configMapToVolume(ctx, runtime, pod, volume.ConfigMap, configMaps, labels)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error creating volume for configMap volume %s", volume.Name)
+			}
+			kubeVol.readOnly = true
+		default:
+			return nil, errors.Wrapf(libpod.ErrNotImplemented, "volume %s is of an unsupported type, only hostPath, emptyDir, persistentVolumeClaim and configMap volumes are supported", volume.Name)
+		}
+		kubeVolumes[volume.Name] = kubeVol
+	}
+	return kubeVolumes, nil
+}
+
+// prepareHostPath checks that the path of a hostPath volume matches its type,
+// creating it if the type requires so
+func prepareHostPath(hostPath *v1.HostPathVolumeSource) error {
+	if !filepath.IsAbs(hostPath.Path) {
+		return errors.Errorf("path %q is not absolute", hostPath.Path)
+	}
+	if hostPath.Type == nil || *hostPath.Type == v1.HostPathUnset {
+		return nil
+	}
+
+	info, err := os.Stat(hostPath.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	switch *hostPath.Type {
+	case v1.HostPathDirectoryOrCreate:
+		if os.IsNotExist(err) {
+			return os.MkdirAll(hostPath.Path, 0755)
+		}
+		if !info.IsDir() {
+			return errors.Errorf("path %q is not a directory", hostPath.Path)
+		}
+	case v1.HostPathDirectory:
+		if err != nil || !info.IsDir() {
+			return errors.Errorf("path %q is not a directory", hostPath.Path)
+		}
+	case v1.HostPathFileOrCreate:
+		if os.IsNotExist(err) {
+			file, err := os.OpenFile(hostPath.Path, os.O_RDONLY|os.O_CREATE, 0644)
+			if err != nil {
+				return err
+			}
+			return file.Close()
+		}
+		if !info.Mode().IsRegular() {
+			return errors.Errorf("path %q is not a file", hostPath.Path)
+		}
+	case v1.HostPathFile:
+		if err != nil || !info.Mode().IsRegular() {
+			return errors.Errorf("path %q is not a file", hostPath.Path)
+		}
+	case v1.HostPathSocket, v1.HostPathCharDev, v1.HostPathBlockDev:
+		if err != nil {
+			return errors.Errorf("path %q does not exist", hostPath.Path)
+		}
+	default:
+		return errors.Errorf("unknown hostPath type %q", *hostPath.Type)
+	}
+	return nil
+}
+
+// getOrCreateNamedVolume returns the mount point of the named volume, creating
+// the volume with the labels if it does not exist
+func getOrCreateNamedVolume(ctx context.Context, runtime *libpod.Runtime, name string, labels map[string]string) (string, error) {
+	volume, err := runtime.GetVolume(name)
+	if err == nil {
+		return volume.MountSource(), nil
+	}
+	if errors.Cause(err) != libpod.ErrNoSuchVolume {
+		return "", err
+	}
+	volume, err = runtime.NewVolume(ctx, libpod.WithVolumeName(name), libpod.WithVolumeLabels(labels))
+	if err != nil {
+		return "", err
+	}
+	return volume.MountSource(), nil
+}
+
+// configMapToVolume creates a new local volume of the pod with the labels,
+// holding the data of the ConfigMap referenced by source as files, and returns
+// its mount point
+func configMapToVolume(ctx context.Context, runtime *libpod.Runtime, pod *libpod.Pod, source *v1.ConfigMapVolumeSource, configMaps map[string]v1.ConfigMap, labels map[string]string) (string, error) {
+	configMap, ok := configMaps[source.Name]
+	if !ok && (source.Optional == nil || !*source.Optional) {
+		return "", errors.Errorf("ConfigMap %s not found, it must be passed with --configmap", source.Name)
+	}
+
+	data := make(map[string][]byte)
+	for key, value := range configMap.BinaryData {
+		data[key] = value
+	}
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+
+	mode := os.FileMode(0644)
+	if source.DefaultMode != nil {
+		mode = os.FileMode(*source.DefaultMode)
+	}
+	items := source.Items
+	if len(items) == 0 {
+		for key := range data {
+			items = append(items, v1.KeyToPath{Key: key, Path: key})
+		}
+	}
+
+	volume, err := runtime.NewVolume(ctx, libpod.WithVolumeLabels(labels), libpod.WithVolumePod(pod))
+	if err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		value, ok := data[item.Key]
+		if !ok {
+			if source.Optional != nil && *source.Optional {
+				continue
+			}
+			return "", errors.Errorf("key %s not found in ConfigMap %s", item.Key, source.Name)
+		}
+		path := filepath.Join(volume.MountPoint(), filepath.Clean("/"+item.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", err
+		}
+		itemMode := mode
+		if item.Mode != nil {
+			itemMode = os.FileMode(*item.Mode)
+		}
+		if err := ioutil.WriteFile(path, value, itemMode); err != nil {
+			return "", errors.Wrapf(err, "error writing key %s of ConfigMap %s", item.Key, source.Name)
+		}
+	}
+	return volume.MountSource(), nil
+}
+
+// kubeEnvToEnv returns the environment variables of a kube container, resolving
+// the ones taken from ConfigMaps
+func kubeEnvToEnv(containerYAML v1.Container, configMaps map[string]v1.ConfigMap) (map[string]string, error) {
+	var envs map[string]string
+	if len(containerYAML.Env) > 0 || len(containerYAML.EnvFrom) > 0 {
+		envs = make(map[string]string)
+	}
+
+	for _, envFrom := range containerYAML.EnvFrom {
+		if envFrom.ConfigMapRef == nil {
+			logrus.Debugf("Only environment variables from ConfigMaps are supported, ignoring envFrom of container %s", containerYAML.Name)
+			continue
+		}
+		configMap, ok := configMaps[envFrom.ConfigMapRef.Name]
+		if !ok {
+			if envFrom.ConfigMapRef.Optional != nil && *envFrom.ConfigMapRef.Optional {
+				continue
+			}
+			return nil, errors.Errorf("ConfigMap %s not found, it must be passed with --configmap", envFrom.ConfigMapRef.Name)
+		}
+		for key, value := range configMap.Data {
+			envs[envFrom.Prefix+key] = value
+		}
+	}
+
+	for _, e := range containerYAML.Env {
+		if e.ValueFrom == nil {
+			envs[e.Name] = e.Value
+			continue
+		}
+		keyRef := e.ValueFrom.ConfigMapKeyRef
+		if keyRef == nil {
+			logrus.Debugf("Only environment variables from ConfigMaps are supported, ignoring %s of container %s", e.Name, containerYAML.Name)
+			continue
+		}
+		optional := keyRef.Optional != nil && *keyRef.Optional
+		configMap, ok := configMaps[keyRef.Name]
+		if !ok {
+			if optional {
+				continue
+			}
+			return nil, errors.Errorf("ConfigMap %s not found, it must be passed with --configmap", keyRef.Name)
+		}
+		value, ok := configMap.Data[keyRef.Key]
+		if !ok {
+			if optional {
+				continue
+			}
+			return nil, errors.Errorf("key %s not found in ConfigMap %s", keyRef.Key, keyRef.Name)
+		}
+		envs[e.Name] = value
+	}
+	return envs, nil
+}
+
 // kubeContainerToCreateConfig takes a v1.Container and returns a createconfig describing a container
-func kubeContainerToCreateConfig(containerYAML v1.Container, runtime *libpod.Runtime, newImage *image2.Image, namespaces map[string]string) *createconfig.CreateConfig {
-	var (
-		containerConfig createconfig.CreateConfig
-		envs            map[string]string
-	)
+func kubeContainerToCreateConfig(containerYAML v1.Container, runtime *libpod.Runtime, newImage *image2.Image, namespaces map[string]string, volumes map[string]kubeVolume, configMaps map[string]v1.ConfigMap) (*createconfig.CreateConfig, error) {
+	var containerConfig createconfig.CreateConfig
 
 	containerConfig.Runtime = runtime
 	containerConfig.Image = containerYAML.Image
@@ -207,15 +796,17 @@ func kubeContainerToCreateConfig(containerYAML v1.Container, runtime *libpod.Run
 	containerConfig.Name = containerYAML.Name
 	containerConfig.Tty = containerYAML.TTY
 	containerConfig.WorkDir = containerYAML.WorkingDir
-	if containerYAML.SecurityContext.ReadOnlyRootFilesystem != nil {
-		containerConfig.ReadOnlyRootfs = *containerYAML.SecurityContext.ReadOnlyRootFilesystem
-	}
-	if containerYAML.SecurityContext.Privileged != nil {
-		containerConfig.Privileged = *containerYAML.SecurityContext.Privileged
-	}
+	if containerYAML.SecurityContext != nil {
+		if containerYAML.SecurityContext.ReadOnlyRootFilesystem != nil {
+			containerConfig.ReadOnlyRootfs = *containerYAML.SecurityContext.ReadOnlyRootFilesystem
+		}
+		if containerYAML.SecurityContext.Privileged != nil {
+			containerConfig.Privileged = *containerYAML.SecurityContext.Privileged
+		}
 
-	if containerYAML.SecurityContext.AllowPrivilegeEscalation != nil {
-		containerConfig.NoNewPrivs = !*containerYAML.SecurityContext.AllowPrivilegeEscalation
+		if containerYAML.SecurityContext.AllowPrivilegeEscalation != nil {
+			containerConfig.NoNewPrivs = !*containerYAML.SecurityContext.AllowPrivilegeEscalation
+		}
 	}
 
 	containerConfig.Command = containerYAML.Command
@@ -233,13 +824,33 @@ func kubeContainerToCreateConfig(containerYAML v1.Container, runtime *libpod.Run
 	//containerConfig.PidMode = ns.PidMode(namespaces["pid"])
 	containerConfig.UsernsMode = ns.UsernsMode(namespaces["user"])
 
-	if len(containerYAML.Env) > 0 {
-		envs = make(map[string]string)
-	}
 	// Environment Variables
-	for _, e := range containerYAML.Env {
-		envs[e.Name] = e.Value
+	envs, err := kubeEnvToEnv(containerYAML, configMaps)
+	if err != nil {
+		return nil, err
 	}
 	containerConfig.Env = envs
-	return &containerConfig
+
+	for _, volumeMount := range containerYAML.VolumeMounts {
+		volume, ok := volumes[volumeMount.Name]
+		if !ok {
+			return nil, errors.Errorf("volume mount %s of container %s is not configured in the volumes of the pod", volumeMount.Name, containerYAML.Name)
+		}
+		if !filepath.IsAbs(volumeMount.MountPath) {
+			return nil, errors.Errorf("mount path %q of volume mount %s is not absolute", volumeMount.MountPath, volumeMount.Name)
+		}
+		hostPath := volume.hostPath
+		if volumeMount.SubPath != "" {
+			if filepath.IsAbs(volumeMount.SubPath) || strings.HasPrefix(filepath.Clean(volumeMount.SubPath), "..") {
+				return nil, errors.Errorf("sub path %q of volume mount %s must be a relative path within the volume", volumeMount.SubPath, volumeMount.Name)
+			}
+			hostPath = filepath.Join(hostPath, volumeMount.SubPath)
+		}
+		options := "rw"
+		if volume.readOnly || volumeMount.ReadOnly {
+			options = "ro"
+		}
+		containerConfig.Volumes = append(containerConfig.Volumes, fmt.Sprintf("%s:%s:%s", hostPath, volumeMount.MountPath, options))
+	}
+	return &containerConfig, nil
 }
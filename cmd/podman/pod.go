@@ -10,6 +10,7 @@ var (
 Pods are a group of one or more containers sharing the same network, pid and ipc namespaces.
 `
 	podSubCommands = []cli.Command{
+		podCheckpointCommand,
 		podCreateCommand,
 		podExistsCommand,
 		podInspectCommand,
@@ -17,6 +18,7 @@ Pods are a group of one or more containers sharing the same network, pid and ipc
 		podPauseCommand,
 		podPsCommand,
 		podRestartCommand,
+		podRestoreCommand,
 		podRmCommand,
 		podStartCommand,
 		podStatsCommand,
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	podCheckpointFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "keep, k",
+			Usage: "Keep all temporary checkpoint files",
+		},
+		cli.BoolFlag{
+			Name:  "leave-running, R",
+			Usage: "Leave the containers running after writing checkpoint to disk",
+		},
+		cli.BoolFlag{
+			Name:  "tcp-established",
+			Usage: "Checkpoint containers with established TCP connections",
+		},
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "Checkpoint all running pods",
+		},
+		LatestPodFlag,
+	}
+	podCheckpointDescription = `
+   Checkpoints all running containers of one or more pods, including their infra
+   containers.  The pod name or ID can be used.
+`
+
+	podCheckpointCommand = cli.Command{
+		Name:                   "checkpoint",
+		Usage:                  "Checkpoints one or more pods",
+		Description:            podCheckpointDescription,
+		Flags:                  sortFlags(podCheckpointFlags),
+		Action:                 podCheckpointCmd,
+		ArgsUsage:              "POD-NAME|POD-ID [POD-NAME|POD-ID ...]",
+		UseShortOptionHandling: true,
+		OnUsageError:           usageErrorHandler,
+	}
+)
+
+func podCheckpointCmd(c *cli.Context) error {
+	if err := checkMutuallyExclusiveFlags(c); err != nil {
+		return err
+	}
+	if rootless.IsRootless() {
+		return errors.New("checkpointing a pod requires root")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	options := libpod.ContainerCheckpointOptions{
+		Keep:           c.Bool("keep"),
+		KeepRunning:    c.Bool("leave-running"),
+		TCPEstablished: c.Bool("tcp-established"),
+	}
+
+	// getPodsFromContext returns an error when a requested pod
+	// isn't found. The only fatal error scenerio is when there are no pods
+	// in which case the following loop will be skipped.
+	pods, lastError := getPodsFromContext(c, runtime)
+
+	for _, pod := range pods {
+		ctrErrs, err := pod.Checkpoint(getContext(), options)
+		if ctrErrs != nil {
+			for ctr, err := range ctrErrs {
+				if lastError != nil {
+					logrus.Errorf("%q", lastError)
+				}
+				lastError = errors.Wrapf(err, "unable to checkpoint container %q on pod %q", ctr, pod.ID())
+			}
+			continue
+		}
+		if err != nil {
+			if lastError != nil {
+				logrus.Errorf("%q", lastError)
+			}
+			lastError = errors.Wrapf(err, "unable to checkpoint pod %q", pod.ID())
+			continue
+		}
+		fmt.Println(pod.ID())
+	}
+
+	return lastError
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	podRestoreFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "keep, k",
+			Usage: "Keep all temporary checkpoint files",
+		},
+		cli.BoolFlag{
+			Name:  "tcp-established",
+			Usage: "Restore containers with established TCP connections",
+		},
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "Restore all checkpointed pods",
+		},
+		LatestPodFlag,
+	}
+	podRestoreDescription = `
+   Restores the checkpointed containers of one or more pods, in the order of
+   their dependencies.  The pod name or ID can be used.
+`
+
+	podRestoreCommand = cli.Command{
+		Name:                   "restore",
+		Usage:                  "Restores one or more pods from a checkpoint",
+		Description:            podRestoreDescription,
+		Flags:                  sortFlags(podRestoreFlags),
+		Action:                 podRestoreCmd,
+		ArgsUsage:              "POD-NAME|POD-ID [POD-NAME|POD-ID ...]",
+		UseShortOptionHandling: true,
+		OnUsageError:           usageErrorHandler,
+	}
+)
+
+func podRestoreCmd(c *cli.Context) error {
+	if err := checkMutuallyExclusiveFlags(c); err != nil {
+		return err
+	}
+	if rootless.IsRootless() {
+		return errors.New("restoring a pod requires root")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	options := libpod.ContainerCheckpointOptions{
+		Keep:           c.Bool("keep"),
+		TCPEstablished: c.Bool("tcp-established"),
+	}
+
+	// getPodsFromContext returns an error when a requested pod
+	// isn't found. The only fatal error scenerio is when there are no pods
+	// in which case the following loop will be skipped.
+	pods, lastError := getPodsFromContext(c, runtime)
+
+	for _, pod := range pods {
+		ctrErrs, err := pod.Restore(getContext(), options)
+		if ctrErrs != nil {
+			for ctr, err := range ctrErrs {
+				if lastError != nil {
+					logrus.Errorf("%q", lastError)
+				}
+				lastError = errors.Wrapf(err, "unable to restore container %q on pod %q", ctr, pod.ID())
+			}
+			continue
+		}
+		if err != nil {
+			if lastError != nil {
+				logrus.Errorf("%q", lastError)
+			}
+			lastError = errors.Wrapf(err, "unable to restore pod %q", pod.ID())
+			continue
+		}
+		fmt.Println(pod.ID())
+	}
+
+	return lastError
+}
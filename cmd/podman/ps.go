@@ -456,6 +456,20 @@ func generateContainerFilterFuncs(filter, filterValue string, runtime *libpod.Ru
 			}
 			return state == filterValue
 		}, nil
+	case "health":
+		if !util.StringInSlice(filterValue, []string{libpod.HealthCheckHealthy, libpod.HealthCheckUnhealthy, libpod.HealthCheckStarting, "none"}) {
+			return nil, errors.Errorf("%s is not a valid health status", filterValue)
+		}
+		return func(c *libpod.Container) bool {
+			health, err := c.HealthCheckStatus()
+			if err != nil {
+				return false
+			}
+			if health == "" {
+				health = "none"
+			}
+			return health == filterValue
+		}, nil
 	case "ancestor":
 		// This needs to refine to match docker
 		// - ancestor=(<image-name>[:tag]|<image-id>| ⟨image@digest⟩) - containers created from an image or a descendant.
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var (
+	renameDescription = `
+   podman rename
+
+   Rename a container.  The container name or ID can be used to identify the
+   container.  The new name must not be in use by another container or pod.
+`
+	renameCommand = cli.Command{
+		Name:         "rename",
+		Usage:        "Rename an existing container",
+		Description:  renameDescription,
+		Action:       renameCmd,
+		ArgsUsage:    "CONTAINER NEW-NAME",
+		OnUsageError: usageErrorHandler,
+	}
+)
+
+func renameCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.Errorf("you must provide a container and a new name")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	ctr, err := runtime.LookupContainer(args[0])
+	if err != nil {
+		return errors.Wrapf(err, "unable to find container %s", args[0])
+	}
+
+	return runtime.RenameContainer(ctr, args[1])
+}
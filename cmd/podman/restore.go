@@ -7,8 +7,10 @@ import (
 
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/image"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
 
@@ -34,6 +36,14 @@ var (
 			Name:  "all, a",
 			Usage: "Restore all checkpointed containers",
 		},
+		cli.StringFlag{
+			Name:  "import, i",
+			Usage: "Restore a container from a checkpoint archive created with 'podman container checkpoint --export'",
+		},
+		cli.StringFlag{
+			Name:  "name, n",
+			Usage: "Name of the container restored with --import, instead of the name of the checkpointed container",
+		},
 		LatestFlag,
 	}
 	restoreCommand = cli.Command{
@@ -60,6 +70,17 @@ func restoreCmd(c *cli.Context) error {
 	options := libpod.ContainerCheckpointOptions{
 		Keep:           c.Bool("keep"),
 		TCPEstablished: c.Bool("tcp-established"),
+		TargetFile:     c.String("import"),
+	}
+
+	if options.TargetFile != "" {
+		if c.Bool("all") || c.Bool("latest") || len(c.Args()) > 0 {
+			return errors.Errorf("--import cannot be used with container names, --all or --latest")
+		}
+		return restoreImport(getContext(), runtime, options, c.String("name"))
+	}
+	if c.IsSet("name") {
+		return errors.Errorf("--name can only be used with --import")
 	}
 
 	if err := checkAllAndLatest(c); err != nil {
@@ -80,3 +101,48 @@ func restoreCmd(c *cli.Context) error {
 	}
 	return lastError
 }
+
+// restoreImport creates a new container from a checkpoint archive and
+// restores it, pulling the image it was created from if needed
+func restoreImport(ctx context.Context, runtime *libpod.Runtime, options libpod.ContainerCheckpointOptions, name string) error {
+	config, err := libpod.CheckpointArchiveConfig(options.TargetFile)
+	if err != nil {
+		return err
+	}
+
+	// The root filesystem changes in the archive only apply on top of the
+	// exact image the container was created from
+	if _, err := runtime.ImageRuntime().NewFromLocal(config.RootfsImageID); err != nil {
+		newImage, err := runtime.ImageRuntime().New(ctx, config.RootfsImageName, runtime.GetConfig().SignaturePolicyPath, "", os.Stderr, nil, image.SigningOptions{}, false, nil)
+		if err != nil {
+			return errors.Wrapf(err, "error pulling image %s of checkpointed container", config.RootfsImageName)
+		}
+		if newImage.ID() != config.RootfsImageID {
+			return errors.Errorf("image %s has ID %s, but the container was checkpointed with image ID %s", config.RootfsImageName, newImage.ID(), config.RootfsImageID)
+		}
+	}
+
+	// A container restored under a new name runs next to the checkpointed
+	// container, so it cannot take over its addresses
+	if name != "" {
+		config.Name = name
+		config.ID = ""
+		config.StaticIP = nil
+		config.StaticIPs = nil
+		config.StaticMAC = nil
+		options.IgnoreStaticIP = true
+	}
+
+	ctr, err := runtime.RestoreContainer(ctx, config)
+	if err != nil {
+		return errors.Wrapf(err, "error creating container from checkpoint archive %s", options.TargetFile)
+	}
+	if err := ctr.Restore(ctx, options); err != nil {
+		if err2 := runtime.RemoveContainer(ctx, ctr, true); err2 != nil {
+			logrus.Errorf("error removing container %s: %v", ctr.ID(), err2)
+		}
+		return errors.Wrapf(err, "failed to restore container %v", ctr.ID())
+	}
+	fmt.Println(ctr.ID())
+	return nil
+}
@@ -0,0 +1,221 @@
+// +build remoteclient
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// remoteRunFlags are the run flags supported by the remote client
+var remoteRunFlags = map[string]bool{
+	"add-host":     true,
+	"attach":       true,
+	"cap-add":      true,
+	"cap-drop":     true,
+	"detach":       true,
+	"detach-keys":  true,
+	"dns":          true,
+	"dns-opt":      true,
+	"dns-search":   true,
+	"entrypoint":   true,
+	"env":          true,
+	"env-file":     true,
+	"expose":       true,
+	"hostname":     true,
+	"interactive":  true,
+	"label":        true,
+	"label-file":   true,
+	"memory":       true,
+	"name":         true,
+	"net":          true,
+	"pids-limit":   true,
+	"privileged":   true,
+	"publish":      true,
+	"publish-all":  true,
+	"quiet":        true,
+	"read-only":    true,
+	"restart":      true,
+	"rm":           true,
+	"sig-proxy":    true,
+	"stop-signal":  true,
+	"stop-timeout": true,
+	"sysctl":       true,
+	"tmpfs":        true,
+	"tty":          true,
+	"user":         true,
+	"volume":       true,
+	"workdir":      true,
+}
+
+var remoteRunCommand = cli.Command{
+	Name:                   "run",
+	Usage:                  "Run a command in a new container",
+	Description:            runDescription,
+	Flags:                  sortFlags(runFlags),
+	Action:                 remoteRunCmd,
+	ArgsUsage:              "IMAGE [COMMAND [ARG...]]",
+	HideHelp:               true,
+	SkipArgReorder:         true,
+	UseShortOptionHandling: true,
+	OnUsageError:           usageErrorHandler,
+}
+
+func remoteRunCmd(c *cli.Context) error {
+	if err := createInit(c); err != nil {
+		return err
+	}
+	create, err := remoteCreateConfig(c)
+	if err != nil {
+		return err
+	}
+
+	outputStream := os.Stdout
+	errorStream := os.Stderr
+	inputStream := os.Stdin
+
+	// If -i is not set, clear stdin
+	if !c.Bool("interactive") {
+		inputStream = nil
+	}
+
+	// If attach is set, clear stdin/stdout/stderr and only attach requested
+	if c.IsSet("attach") || c.IsSet("a") {
+		outputStream = nil
+		errorStream = nil
+		if !c.Bool("interactive") {
+			inputStream = nil
+		}
+
+		attachTo := c.StringSlice("attach")
+		for _, stream := range attachTo {
+			switch strings.ToLower(stream) {
+			case "stdout":
+				outputStream = os.Stdout
+			case "stderr":
+				errorStream = os.Stderr
+			case "stdin":
+				inputStream = os.Stdin
+			default:
+				return errors.Wrapf(libpod.ErrInvalidArg, "invalid stream %q for --attach - must be one of stdin, stdout, or stderr", stream)
+			}
+		}
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "error creating libpod runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	exitCode, err = runtime.Run(getContext(), create, outputStream, errorStream, inputStream, c.String("detach-keys"), c.BoolT("sig-proxy"))
+	return err
+}
+
+// remoteCreateConfig translates the run flags into the configuration of the
+// container to create on the varlink service
+func remoteCreateConfig(c *cli.Context) (iopodman.Create, error) {
+	var create iopodman.Create
+
+	for _, flag := range runFlags {
+		names := strings.Split(flag.GetName(), ",")
+		for _, name := range names {
+			if c.IsSet(strings.TrimSpace(name)) && !remoteRunFlags[strings.TrimSpace(names[0])] {
+				return create, errors.Errorf("the --%s flag is not supported by the remote client", strings.TrimSpace(names[0]))
+			}
+		}
+	}
+
+	if c.Bool("detach") && c.IsSet("attach") {
+		return create, errors.Errorf("--detach and --attach cannot be used together")
+	}
+
+	env := make(map[string]string)
+	if err := readKVStrings(env, c.StringSlice("env-file"), c.StringSlice("env")); err != nil {
+		return create, errors.Wrapf(err, "unable to process environment variables")
+	}
+	labels, err := getAllLabels(c.StringSlice("label-file"), c.StringSlice("label"))
+	if err != nil {
+		return create, errors.Wrapf(err, "unable to process labels")
+	}
+	sysctl, err := validateSysctl(c.StringSlice("sysctl"))
+	if err != nil {
+		return create, errors.Wrapf(err, "invalid value for sysctl")
+	}
+
+	// An empty entrypoint clears the one of the image
+	entrypoint := configureEntrypoint(c, nil)
+	if c.IsSet("entrypoint") && len(entrypoint) == 0 {
+		entrypoint = []string{""}
+	}
+
+	var stopSignal int64
+	if c.IsSet("stop-signal") {
+		sig, err := signal.ParseSignal(c.String("stop-signal"))
+		if err != nil {
+			return create, err
+		}
+		stopSignal = int64(sig)
+	}
+
+	var memory int64
+	if c.String("memory") != "" {
+		memory, err = units.RAMInBytes(c.String("memory"))
+		if err != nil {
+			return create, errors.Wrapf(err, "invalid value for memory")
+		}
+	}
+
+	create = iopodman.Create{
+		Image:             c.Args()[0],
+		Command:           c.Args()[1:],
+		Cap_add:           c.StringSlice("cap-add"),
+		Cap_drop:          c.StringSlice("cap-drop"),
+		Detach:            c.Bool("detach"),
+		Dns_opt:           c.StringSlice("dns-opt"),
+		Dns_search:        c.StringSlice("dns-search"),
+		Dns_servers:       c.StringSlice("dns"),
+		Entrypoint:        entrypoint,
+		Env:               env,
+		Exposed_ports:     c.StringSlice("expose"),
+		Host_add:          c.StringSlice("add-host"),
+		Hostname:          c.String("hostname"),
+		Interactive:       c.Bool("interactive"),
+		Ip_addresses:      c.StringSlice("ip"),
+		Ip6_addresses:     c.StringSlice("ip6"),
+		Labels:            labels,
+		Mac_address:       c.String("mac-address"),
+		Name:              c.String("name"),
+		Net_mode:          c.String("network"),
+		Port_forwarder:    c.String("port-forwarder"),
+		Port_peer_address: c.String("port-peer-address"),
+		Privileged:        c.Bool("privileged"),
+		Publish:           c.StringSlice("publish"),
+		Publish_all:       c.Bool("publish-all"),
+		Quiet:             c.Bool("quiet"),
+		Readonly_rootfs:   c.Bool("read-only"),
+		Resources: iopodman.CreateResourceConfig{
+			Memory:     memory,
+			Pids_limit: c.Int64("pids-limit"),
+		},
+		Restart_policy: c.String("restart"),
+		Rm:             c.Bool("rm"),
+		Stop_signal:    stopSignal,
+		Stop_timeout:   int64(c.Int("stop-timeout")),
+		Sys_ctl:        sysctl,
+		Tmpfs:          c.StringSlice("tmpfs"),
+		Tty:            c.Bool("tty"),
+		User:           c.String("user"),
+		Volumes:        c.StringSlice("volume"),
+		Work_dir:       c.String("workdir"),
+	}
+	return create, nil
+}
@@ -125,6 +125,7 @@ func NewBatchContainer(ctr *libpod.Container, opts PsOptions) (PsContainerOutput
 		size      *ContainerSize
 		ns        *Namespace
 		pso       PsContainerOutput
+		health    string
 	)
 	batchErr := ctr.Batch(func(c *libpod.Container) error {
 		if opts.Sync {
@@ -152,6 +153,10 @@ func NewBatchContainer(ctr *libpod.Container, opts PsOptions) (PsContainerOutput
 		if err != nil {
 			logrus.Errorf("error getting exited time for %q: %v", c.ID(), err)
 		}
+		health, err = c.HealthCheckStatus()
+		if err != nil {
+			logrus.Errorf("error getting health status for %q: %v", c.ID(), err)
+		}
 		if opts.Namespace {
 			pid, err = c.PID()
 			if err != nil {
@@ -191,6 +196,9 @@ func NewBatchContainer(ctr *libpod.Container, opts PsOptions) (PsContainerOutput
 		status = fmt.Sprintf("Exited (%d) %s ago", exitCode, exitedSince)
 	case libpod.ContainerStateRunning.String():
 		status = "Up " + units.HumanDuration(time.Since(startedAt)) + " ago"
+		if health != "" {
+			status += " (" + health + ")"
+		}
 	case libpod.ContainerStatePaused.String():
 		status = "Paused"
 	case libpod.ContainerStateCreated.String(), libpod.ContainerStateConfigured.String():
@@ -453,6 +461,10 @@ func GetCtrInspectInfo(config *libpod.ContainerConfig, ctrInspectData *inspect.C
 	memKernel, memReservation, memSwap, memSwappiness, memDisableOOMKiller := getMemoryInfo(spec)
 	pidsLimit := getPidsInfo(spec)
 	cgroup := getCgroup(spec)
+	logDriver := config.LogDriver
+	if logDriver == "" {
+		logDriver = libpod.KubernetesLogging
+	}
 
 	data := &inspect.ContainerData{
 		ctrInspectData,
@@ -502,6 +514,16 @@ func GetCtrInspectInfo(config *libpod.ContainerConfig, ctrInspectData *inspect.C
 			Ulimits:              createArtifact.Resources.Ulimit,
 			SecurityOpt:          createArtifact.SecurityOpts,
 			Tmpfs:                createArtifact.Tmpfs,
+			RestartPolicy: &inspect.RestartPolicy{
+				Name:              config.RestartPolicy,
+				MaximumRetryCount: config.RestartRetries,
+			},
+			LogConfig: &inspect.LogConfig{
+				Type: logDriver,
+				Config: map[string]string{
+					"path": config.LogPath,
+				},
+			},
 		},
 		&inspect.CtrConfig{
 			Hostname:    spec.Hostname,
@@ -511,11 +533,13 @@ func GetCtrInspectInfo(config *libpod.ContainerConfig, ctrInspectData *inspect.C
 			WorkingDir:  spec.Process.Cwd,
 			Labels:      config.Labels,
 			Annotations: spec.Annotations,
+			MacAddress:  config.StaticMAC.String(),
 			Tty:         spec.Process.Terminal,
 			OpenStdin:   config.Stdin,
 			StopSignal:  config.StopSignal,
 			Cmd:         config.Spec.Process.Args,
 			Entrypoint:  strings.Join(createArtifact.Entrypoint, " "),
+			Healthcheck: config.HealthCheckConfig,
 		},
 	}
 	return data, nil
@@ -0,0 +1,269 @@
+package shared
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ImageDiskUsage holds the disk usage of an image. The shared size is the
+// size of the layers of the image that are shared with other images.
+type ImageDiskUsage struct {
+	ID         string    `json:"id"`
+	Names      []string  `json:"names"`
+	Created    time.Time `json:"created"`
+	Size       int64     `json:"size"`
+	SharedSize int64     `json:"sharedSize"`
+	UniqueSize int64     `json:"uniqueSize"`
+	Containers int       `json:"containers"`
+}
+
+// ContainerDiskUsage holds the disk usage of the writable layer of a
+// container
+type ContainerDiskUsage struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Image        string    `json:"image"`
+	Command      string    `json:"command"`
+	LocalVolumes int       `json:"localVolumes"`
+	Size         int64     `json:"size"`
+	Created      time.Time `json:"created"`
+	State        string    `json:"state"`
+}
+
+// VolumeDiskUsage holds the disk usage of a local volume
+type VolumeDiskUsage struct {
+	Name  string `json:"name"`
+	Links int    `json:"links"`
+	Size  int64  `json:"size"`
+}
+
+// DiskUsageSummary holds the disk usage of all objects of a type. Active
+// objects are used by containers, or are running containers. The reclaimable
+// size is the space a prune of the objects would free.
+type DiskUsageSummary struct {
+	Type        string `json:"type"`
+	Total       int    `json:"total"`
+	Active      int    `json:"active"`
+	Size        int64  `json:"size"`
+	Reclaimable int64  `json:"reclaimable"`
+}
+
+// DiskUsage holds the disk usage of the images, containers and local volumes
+// of the store
+type DiskUsage struct {
+	Images     []*ImageDiskUsage     `json:"images"`
+	Containers []*ContainerDiskUsage `json:"containers"`
+	Volumes    []*VolumeDiskUsage    `json:"volumes"`
+	Summary    []*DiskUsageSummary   `json:"summary"`
+}
+
+// GetDiskUsage computes the disk usage of all images, containers and local
+// volumes
+func GetDiskUsage(ctx context.Context, runtime *libpod.Runtime) (*DiskUsage, error) {
+	var du DiskUsage
+
+	imagesSummary, err := getImagesDiskUsage(ctx, runtime, &du)
+	if err != nil {
+		return nil, err
+	}
+	volumesSummary, ctrVolumes, err := getVolumesDiskUsage(runtime, &du)
+	if err != nil {
+		return nil, err
+	}
+	ctrsSummary, err := getContainersDiskUsage(runtime, ctrVolumes, &du)
+	if err != nil {
+		return nil, err
+	}
+	du.Summary = []*DiskUsageSummary{imagesSummary, ctrsSummary, volumesSummary}
+	return &du, nil
+}
+
+func getImagesDiskUsage(ctx context.Context, runtime *libpod.Runtime, du *DiskUsage) (*DiskUsageSummary, error) {
+	summary := &DiskUsageSummary{Type: "Images"}
+
+	images, err := runtime.ImageRuntime().GetImages()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get images")
+	}
+
+	var (
+		imageLayers  = make([]map[string]int64, len(images))
+		layerImages  = make(map[string]int)
+		layerActive  = make(map[string]bool)
+		sharedLayers = make(map[string]int64)
+	)
+	for i, img := range images {
+		layers, err := img.LayerSizes()
+		if err != nil {
+			return nil, err
+		}
+		containers, err := img.Containers()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get containers of image %s", img.ID())
+		}
+		for layer := range layers {
+			layerImages[layer]++
+			if len(containers) > 0 {
+				layerActive[layer] = true
+			}
+		}
+		imageLayers[i] = layers
+		du.Images = append(du.Images, &ImageDiskUsage{
+			ID:         img.ID(),
+			Names:      img.Names(),
+			Created:    img.Created(),
+			Containers: len(containers),
+		})
+	}
+
+	for i, img := range images {
+		usage := du.Images[i]
+		size, err := img.Size(ctx)
+		if err != nil {
+			logrus.Errorf("error getting size of image %q: %v", img.ID(), err)
+		} else {
+			usage.Size = int64(*size)
+		}
+		for layer, layerSize := range imageLayers[i] {
+			if layerImages[layer] > 1 {
+				usage.SharedSize += layerSize
+				sharedLayers[layer] = layerSize
+			}
+		}
+		usage.UniqueSize = usage.Size - usage.SharedSize
+		if usage.UniqueSize < 0 {
+			usage.UniqueSize = 0
+		}
+
+		summary.Total++
+		summary.Size += usage.UniqueSize
+		if usage.Containers > 0 {
+			summary.Active++
+		} else {
+			summary.Reclaimable += usage.UniqueSize
+		}
+	}
+	// Shared layers are counted once, and can only be reclaimed if none
+	// of the images sharing them is in use
+	for layer, layerSize := range sharedLayers {
+		summary.Size += layerSize
+		if !layerActive[layer] {
+			summary.Reclaimable += layerSize
+		}
+	}
+	return summary, nil
+}
+
+func getContainersDiskUsage(runtime *libpod.Runtime, ctrVolumes map[string]int, du *DiskUsage) (*DiskUsageSummary, error) {
+	summary := &DiskUsageSummary{Type: "Containers"}
+
+	ctrs, err := runtime.GetAllContainers()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get containers")
+	}
+	for _, ctr := range ctrs {
+		var (
+			usage = &ContainerDiskUsage{
+				ID:           ctr.ID(),
+				Name:         ctr.Name(),
+				Command:      strings.Join(ctr.Command(), " "),
+				LocalVolumes: ctrVolumes[ctr.ID()],
+				Created:      ctr.CreatedTime(),
+			}
+			state libpod.ContainerStatus
+		)
+		_, usage.Image = ctr.Image()
+		batchErr := ctr.Batch(func(c *libpod.Container) error {
+			state, err = c.State()
+			if err != nil {
+				return errors.Wrapf(err, "unable to obtain container state")
+			}
+			// Use the same size as podman ps --size
+			usage.Size, err = c.RWSize()
+			if err != nil {
+				logrus.Errorf("error getting rw size for %q: %v", c.ID(), err)
+				usage.Size = 0
+			}
+			return nil
+		})
+		if batchErr != nil {
+			return nil, batchErr
+		}
+		usage.State = state.String()
+		du.Containers = append(du.Containers, usage)
+
+		summary.Total++
+		summary.Size += usage.Size
+		if state == libpod.ContainerStateRunning || state == libpod.ContainerStatePaused {
+			summary.Active++
+		} else {
+			summary.Reclaimable += usage.Size
+		}
+	}
+	return summary, nil
+}
+
+// getVolumesDiskUsage computes the disk usage of the local volumes, and returns
+// the IDs of the containers using them mapped to the number of volumes they use
+func getVolumesDiskUsage(runtime *libpod.Runtime, du *DiskUsage) (*DiskUsageSummary, map[string]int, error) {
+	summary := &DiskUsageSummary{Type: "Local Volumes"}
+	ctrVolumes := make(map[string]int)
+
+	volumes, err := runtime.GetAllVolumes()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to get volumes")
+	}
+	for _, vol := range volumes {
+		users, err := vol.UsedBy()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "unable to get containers using volume %s", vol.Name())
+		}
+		for _, user := range users {
+			ctrVolumes[user]++
+		}
+		// The contents of volumes of plugins are managed by the plugin
+		var size int64
+		if !vol.UsesPlugin() {
+			size, err = dirSize(vol.MountPoint())
+			if err != nil {
+				logrus.Errorf("error getting size of volume %q: %v", vol.Name(), err)
+			}
+		}
+		du.Volumes = append(du.Volumes, &VolumeDiskUsage{
+			Name:  vol.Name(),
+			Links: len(users),
+			Size:  size,
+		})
+
+		summary.Total++
+		summary.Size += size
+		if len(users) > 0 {
+			summary.Active++
+		} else {
+			summary.Reclaimable += size
+		}
+	}
+	return summary, ctrVolumes, nil
+}
+
+// dirSize returns the total size of the files under the directory
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
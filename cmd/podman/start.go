@@ -1,13 +1,11 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
-	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
-	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/containers/libpod/libpod/adapter"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -77,7 +75,7 @@ func startCmd(c *cli.Context) error {
 		}
 	}
 
-	runtime, err := libpodruntime.GetRuntime(c)
+	runtime, err := adapter.GetRuntime(c)
 	if err != nil {
 		return errors.Wrapf(err, "error creating libpod runtime")
 	}
@@ -117,7 +115,7 @@ func startCmd(c *cli.Context) error {
 			}
 
 			// attach to the container and also start it not already running
-			err = startAttachCtr(ctr, os.Stdout, os.Stderr, inputStream, c.String("detach-keys"), sigProxy, !ctrRunning)
+			err = runtime.Attach(ctx, ctr, os.Stdout, os.Stderr, inputStream, c.String("detach-keys"), sigProxy, !ctrRunning)
 			if ctrRunning {
 				return err
 			}
@@ -139,19 +137,7 @@ func startCmd(c *cli.Context) error {
 			continue
 		}
 		// Handle non-attach start
-		if err := ctr.Start(ctx); err != nil {
-			var createArtifact cc.CreateConfig
-			artifact, artifactErr := ctr.GetArtifact("create-config")
-			if artifactErr == nil {
-				if jsonErr := json.Unmarshal(artifact, &createArtifact); jsonErr != nil {
-					logrus.Errorf("unable to detect if container %s should be deleted", ctr.ID())
-				}
-				if createArtifact.Rm {
-					if rmErr := runtime.RemoveContainer(ctx, ctr, true); rmErr != nil {
-						logrus.Errorf("unable to remove container %s after it failed to start", ctr.ID())
-					}
-				}
-			}
+		if err := runtime.StartContainer(ctx, ctr); err != nil {
 			if lastError != nil {
 				fmt.Fprintln(os.Stderr, lastError)
 			}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/formats"
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/cmd/podman/shared"
+	"github.com/containers/libpod/libpod/image"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	dfSystemDescription = `
+	podman system df
+
+	Show podman disk usage
+`
+	dfSystemFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "verbose, v",
+			Usage: "Show detailed information on space usage",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Pretty-print the summary using a Go template, or json",
+		},
+	}
+	dfSystemCommand = cli.Command{
+		Name:                   "df",
+		Usage:                  "Show podman disk usage",
+		Description:            dfSystemDescription,
+		Flags:                  sortFlags(dfSystemFlags),
+		Action:                 dfSystemCmd,
+		UseShortOptionHandling: true,
+		OnUsageError:           usageErrorHandler,
+	}
+)
+
+const (
+	dfSummaryFormat    = "table {{.Type}}\t{{.Total}}\t{{.Active}}\t{{.Size}}\t{{.Reclaimable}}"
+	dfImagesFormat     = "table {{.Repository}}\t{{.Tag}}\t{{.ID}}\t{{.Created}}\t{{.Size}}\t{{.SharedSize}}\t{{.UniqueSize}}\t{{.Containers}}"
+	dfContainersFormat = "table {{.ID}}\t{{.Image}}\t{{.Command}}\t{{.LocalVolumes}}\t{{.Size}}\t{{.Created}}\t{{.Status}}\t{{.Names}}"
+	dfVolumesFormat    = "table {{.VolumeName}}\t{{.Links}}\t{{.Size}}"
+
+	dfCommandTruncLength = 17
+)
+
+// dfSummaryTemplateParams is the template parameters of a row of the summary
+type dfSummaryTemplateParams struct {
+	Type        string
+	Total       int
+	Active      int
+	Size        string
+	Reclaimable string
+}
+
+// dfImageTemplateParams is the template parameters of an image in verbose mode
+type dfImageTemplateParams struct {
+	Repository string
+	Tag        string
+	ID         string
+	Created    string
+	Size       string
+	SharedSize string
+	UniqueSize string
+	Containers int
+}
+
+// dfContainerTemplateParams is the template parameters of a container in
+// verbose mode
+type dfContainerTemplateParams struct {
+	ID           string
+	Image        string
+	Command      string
+	LocalVolumes int
+	Size         string
+	Created      string
+	Status       string
+	Names        string
+}
+
+// dfVolumeTemplateParams is the template parameters of a volume in verbose mode
+type dfVolumeTemplateParams struct {
+	VolumeName string
+	Links      int
+	Size       string
+}
+
+func dfSystemCmd(c *cli.Context) error {
+	if err := validateFlags(c, dfSystemFlags); err != nil {
+		return err
+	}
+	if c.Bool("verbose") && c.IsSet("format") {
+		return errors.Errorf("cannot combine --verbose and --format")
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	du, err := shared.GetDiskUsage(getContext(), runtime)
+	if err != nil {
+		return errors.Wrapf(err, "error computing disk usage")
+	}
+
+	if c.Bool("verbose") {
+		return printVerboseDiskUsage(du)
+	}
+
+	format := dfSummaryFormat
+	if c.IsSet("format") {
+		format = strings.Replace(c.String("format"), `\t`, "\t", -1)
+	}
+	if format == formats.JSONString {
+		var output []interface{}
+		for _, s := range du.Summary {
+			output = append(output, interface{}(s))
+		}
+		return formats.JSONStructArray{Output: output}.Out()
+	}
+
+	var output []interface{}
+	for _, s := range du.Summary {
+		output = append(output, interface{}(dfSummaryTemplateParams{
+			Type:        s.Type,
+			Total:       s.Total,
+			Active:      s.Active,
+			Size:        units.HumanSize(float64(s.Size)),
+			Reclaimable: reclaimableString(s.Reclaimable, s.Size),
+		}))
+	}
+	return formats.StdoutTemplateArray{Output: output, Template: format, Fields: dfHeaderMap(dfSummaryTemplateParams{})}.Out()
+}
+
+// printVerboseDiskUsage prints the disk usage of every image, container and
+// volume
+func printVerboseDiskUsage(du *shared.DiskUsage) error {
+	var images []interface{}
+	for _, img := range du.Images {
+		created := units.HumanDuration(time.Since(img.Created)) + " ago"
+		params := dfImageTemplateParams{
+			ID:         shortID(img.ID),
+			Created:    created,
+			Size:       units.HumanSize(float64(img.Size)),
+			SharedSize: units.HumanSize(float64(img.SharedSize)),
+			UniqueSize: units.HumanSize(float64(img.UniqueSize)),
+			Containers: img.Containers,
+		}
+		if len(img.Names) == 0 {
+			params.Repository = "<none>"
+			params.Tag = "<none>"
+			images = append(images, interface{}(params))
+			continue
+		}
+		for repo, tags := range image.ReposToMap(img.Names) {
+			for _, tag := range tags {
+				params.Repository = repo
+				params.Tag = tag
+				images = append(images, interface{}(params))
+			}
+		}
+	}
+	if err := printDiskUsageSection("Images space usage:", images, dfImagesFormat, dfImageTemplateParams{}); err != nil {
+		return err
+	}
+
+	var containers []interface{}
+	for _, ctr := range du.Containers {
+		command := ctr.Command
+		if len(command) > dfCommandTruncLength {
+			command = command[0:dfCommandTruncLength] + "..."
+		}
+		containers = append(containers, interface{}(dfContainerTemplateParams{
+			ID:           shortID(ctr.ID),
+			Image:        ctr.Image,
+			Command:      strconv.Quote(command),
+			LocalVolumes: ctr.LocalVolumes,
+			Size:         units.HumanSize(float64(ctr.Size)),
+			Created:      units.HumanDuration(time.Since(ctr.Created)) + " ago",
+			Status:       ctr.State,
+			Names:        ctr.Name,
+		}))
+	}
+	fmt.Println()
+	if err := printDiskUsageSection("Containers space usage:", containers, dfContainersFormat, dfContainerTemplateParams{}); err != nil {
+		return err
+	}
+
+	var volumes []interface{}
+	for _, vol := range du.Volumes {
+		volumes = append(volumes, interface{}(dfVolumeTemplateParams{
+			VolumeName: vol.Name,
+			Links:      vol.Links,
+			Size:       units.HumanSize(float64(vol.Size)),
+		}))
+	}
+	fmt.Println()
+	return printDiskUsageSection("Local Volumes space usage:", volumes, dfVolumesFormat, dfVolumeTemplateParams{})
+}
+
+// printDiskUsageSection prints the title of a section followed by a table,
+// which only has headers if there is no output
+func printDiskUsageSection(title string, output []interface{}, format string, params interface{}) error {
+	fmt.Printf("%s\n\n", title)
+	out := formats.StdoutTemplateArray{Output: output, Template: format, Fields: dfHeaderMap(params)}
+	if err := out.Out(); err != nil {
+		return err
+	}
+	// The last row only ends with a new line if stdout is a terminal
+	if len(output) > 0 && !terminal.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Println()
+	}
+	return nil
+}
+
+// reclaimableString returns the reclaimable size along with the percentage
+// of the total size it represents
+func reclaimableString(reclaimable, total int64) string {
+	percent := 0
+	if total > 0 {
+		percent = int(float64(reclaimable) / float64(total) * 100)
+	}
+	return fmt.Sprintf("%s (%d%%)", units.HumanSize(float64(reclaimable)), percent)
+}
+
+// dfHeaderMap produces a generic map of "headers" based on the fields of
+// the template parameters
+func dfHeaderMap(params interface{}) map[string]string {
+	v := reflect.Indirect(reflect.ValueOf(params))
+	values := make(map[string]string)
+
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Type().Field(i).Name
+		value := key
+		if value == "ID" {
+			if _, ok := params.(dfContainerTemplateParams); ok {
+				value = "Container" + value
+			} else {
+				value = "Image" + value
+			}
+		}
+		values[key] = strings.ToUpper(splitCamelCase(value))
+	}
+	return values
+}
@@ -24,6 +24,10 @@ var (
 			Name:  "all, a",
 			Usage: "Remove all unused data",
 		},
+		cli.StringSliceFlag{
+			Name:  "filter",
+			Usage: "Only prune volumes matching the filter (e.g. label=key=value, until=24h)",
+		},
 		cli.BoolFlag{
 			Name:  "force, f",
 			Usage: "Do not prompt for confirmation",
@@ -44,6 +48,13 @@ var (
 )
 
 func pruneSystemCmd(c *cli.Context) error {
+	if c.IsSet("filter") && !c.Bool("volumes") {
+		return errors.Errorf("--filter can only be used with --volumes")
+	}
+	volumeFilters, err := getVolumeFilters(c.StringSlice("filter"), volumePruneFilters...)
+	if err != nil {
+		return err
+	}
 
 	// Prompt for confirmation if --force is not set
 	if !c.Bool("force") {
@@ -52,6 +63,10 @@ func pruneSystemCmd(c *cli.Context) error {
 		if c.Bool("volumes") {
 			volumeString = `
         - all volumes not used by at least one container`
+			if len(volumeFilters) > 0 {
+				volumeString = `
+        - all volumes matching the filters not used by at least one container`
+			}
 		}
 		fmt.Printf(`
 WARNING! This will remove:
@@ -79,7 +94,7 @@ Are you sure you want to continue? [y/N] `, volumeString)
 	lasterr := pruneContainers(runtime, ctx, shared.Parallelize("rm"), false)
 	if c.Bool("volumes") {
 		fmt.Println("Deleted Volumes")
-		err := volumePrune(runtime, getContext())
+		err := volumePrune(runtime, getContext(), volumeFilters)
 		if err != nil {
 			if lasterr != nil {
 				logrus.Errorf("%q", lasterr)
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/containers/libpod/cmd/podman/libpodruntime"
+	"github.com/containers/libpod/cmd/podman/shared"
+	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/docker/go-units"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var (
+	updateFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "Update all containers",
+		},
+		cli.StringFlag{
+			Name:  "blkio-weight",
+			Usage: "Block IO weight (relative weight) accepts a weight value between 10 and 1000.",
+		},
+		cli.Uint64Flag{
+			Name:  "cpu-shares",
+			Usage: "CPU shares (relative weight)",
+		},
+		cli.Float64Flag{
+			Name:  "cpus",
+			Usage: "Number of CPUs",
+		},
+		cli.StringFlag{
+			Name:  "memory, m",
+			Usage: "Memory limit (format: <number>[<unit>], where unit = b, k, m or g)",
+		},
+		cli.StringFlag{
+			Name:  "memory-swap",
+			Usage: "Swap limit equal to memory plus swap: '-1' to enable unlimited swap",
+		},
+		cli.Int64Flag{
+			Name:  "pids-limit",
+			Usage: "Tune container pids limit (set -1 for unlimited)",
+		},
+		LatestFlag,
+	}
+	updateDescription = `
+   podman update
+
+   Updates the resource limits of one or more containers.  The new limits are
+   applied immediately to running containers and are kept when the containers
+   are restarted.  The container name or ID can be used.
+`
+	updateCommand = cli.Command{
+		Name:                   "update",
+		Usage:                  "Update the resource limits of one or more containers",
+		Description:            updateDescription,
+		Flags:                  sortFlags(updateFlags),
+		Action:                 updateCmd,
+		ArgsUsage:              "CONTAINER-NAME [CONTAINER-NAME ...]",
+		UseShortOptionHandling: true,
+		OnUsageError:           usageErrorHandler,
+	}
+)
+
+// updateCmd changes the resource limits of one or more containers
+func updateCmd(c *cli.Context) error {
+	var updateFuncs []shared.ParallelWorkerInput
+
+	if err := checkAllAndLatest(c); err != nil {
+		return err
+	}
+	if err := validateFlags(c, updateFlags); err != nil {
+		return err
+	}
+	if os.Geteuid() != 0 {
+		return errors.New("update is not supported for rootless containers")
+	}
+
+	resources, err := parseUpdateResources(c)
+	if err != nil {
+		return err
+	}
+
+	runtime, err := libpodruntime.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	containers, err := getAllOrLatestContainers(c, runtime, -1, "all")
+	if err != nil {
+		if len(containers) == 0 {
+			return err
+		}
+		fmt.Println(err.Error())
+	}
+
+	for _, ctr := range containers {
+		con := ctr
+		f := func() error {
+			return con.Update(resources)
+		}
+
+		updateFuncs = append(updateFuncs, shared.ParallelWorkerInput{
+			ContainerID:  con.ID(),
+			ParallelFunc: f,
+		})
+	}
+
+	maxWorkers := shared.Parallelize("update")
+	if c.GlobalIsSet("max-workers") {
+		maxWorkers = c.GlobalInt("max-workers")
+	}
+	logrus.Debugf("Setting maximum workers to %d", maxWorkers)
+
+	updateErrors, errCount := shared.ParallelExecuteWorkerPool(maxWorkers, updateFuncs)
+	return printParallelOutput(updateErrors, errCount)
+}
+
+// parseUpdateResources converts the limits given on the command line to
+// the resources of an OCI spec
+func parseUpdateResources(c *cli.Context) (*spec.LinuxResources, error) {
+	var (
+		update cc.UpdateResourceConfig
+		err    error
+	)
+	if c.String("blkio-weight") != "" {
+		u, err := strconv.ParseUint(c.String("blkio-weight"), 10, 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for blkio-weight")
+		}
+		update.BlkioWeight = uint16(u)
+	}
+	if c.String("memory") != "" {
+		update.Memory, err = units.RAMInBytes(c.String("memory"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for memory")
+		}
+	}
+	if c.String("memory-swap") == "-1" {
+		update.MemorySwap = -1
+	} else if c.String("memory-swap") != "" {
+		update.MemorySwap, err = units.RAMInBytes(c.String("memory-swap"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for memory-swap")
+		}
+	}
+	update.CPUs = c.Float64("cpus")
+	update.CPUShares = c.Uint64("cpu-shares")
+	update.PidsLimit = c.Int64("pids-limit")
+
+	if update == (cc.UpdateResourceConfig{}) {
+		return nil, errors.Errorf("you must provide at least one resource limit to update")
+	}
+	return update.ToOCIResources()
+}
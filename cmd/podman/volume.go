@@ -1,6 +1,8 @@
 package main
 
 import (
+	"sort"
+
 	"github.com/urfave/cli"
 )
 
@@ -10,17 +12,20 @@ var (
 Volumes are created in and can be shared between containers.`
 
 	volumeSubCommands = []cli.Command{
-		volumeCreateCommand,
-		volumeLsCommand,
-		volumeRmCommand,
-		volumeInspectCommand,
-		volumePruneCommand,
+		volumeExportCommand,
+		volumeImportCommand,
 	}
 	volumeCommand = cli.Command{
 		Name:                   "volume",
 		Usage:                  "Manage volumes",
 		Description:            volumeDescription,
 		UseShortOptionHandling: true,
-		Subcommands:            volumeSubCommands,
+		Subcommands:            getVolumeSubCommandsSorted(),
 	}
 )
+
+func getVolumeSubCommandsSorted() []cli.Command {
+	volumeSubCommands = append(volumeSubCommands, getVolumeSubCommands()...)
+	sort.Sort(commandSortedAlpha{volumeSubCommands})
+	return volumeSubCommands
+}
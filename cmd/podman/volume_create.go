@@ -18,7 +18,7 @@ be created at.`
 var volumeCreateFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:  "driver",
-		Usage: "Specify volume driver name (default local)",
+		Usage: "Specify volume driver name (default local), any other driver is a volume plugin",
 	},
 	cli.StringSliceFlag{
 		Name:  "label, l",
@@ -83,7 +83,7 @@ func volumeCreateCmd(c *cli.Context) error {
 	if err != nil {
 		return errors.Wrapf(err, "unable to process options")
 	}
-	if len(options) != 0 {
+	if len(opts) != 0 {
 		options = append(options, libpod.WithVolumeOptions(opts))
 	}
 
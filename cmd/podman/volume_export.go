@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var volumeExportDescription = `
+podman volume export
+
+Exports the contents of a volume as a tar archive, keeping the ownership and
+extended attributes of its files, including their SELinux labels.
+`
+
+var volumeExportFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "output, o",
+		Usage: "Write to a file, default is STDOUT",
+		Value: "/dev/stdout",
+	},
+}
+
+var volumeExportCommand = cli.Command{
+	Name:         "export",
+	Usage:        "Export the contents of a volume as a tar archive",
+	Description:  volumeExportDescription,
+	Flags:        volumeExportFlags,
+	Action:       volumeExportCmd,
+	ArgsUsage:    "VOLUME-NAME",
+	OnUsageError: usageErrorHandler,
+}
+
+func volumeExportCmd(c *cli.Context) error {
+	if err := validateFlags(c, volumeExportFlags); err != nil {
+		return err
+	}
+
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("volume name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.Errorf("too many arguments given, need 1 at most.")
+	}
+
+	output := c.String("output")
+	if output == "/dev/stdout" && logrus.IsTerminal(os.Stdout) {
+		return errors.Errorf("refusing to export to terminal. Use -o flag or redirect")
+	}
+	if err := validateFileName(output); err != nil {
+		return err
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	return runtime.ExportVolume(args[0], output)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+
+	"github.com/containers/libpod/libpod/adapter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var volumeImportDescription = `
+podman volume import
+
+Imports the contents of a tar archive into an existing volume, restoring the
+ownership and extended attributes stored in the archive. Files of the volume
+that are not in the archive are kept. The archive is read from STDIN if it is
+not given or is '-'.
+`
+
+var volumeImportCommand = cli.Command{
+	Name:         "import",
+	Usage:        "Import a tar archive into a volume",
+	Description:  volumeImportDescription,
+	Action:       volumeImportCmd,
+	ArgsUsage:    "VOLUME-NAME [TARBALL]",
+	OnUsageError: usageErrorHandler,
+}
+
+func volumeImportCmd(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.Errorf("volume name must be specified")
+	}
+	if len(args) > 2 {
+		return errors.Errorf("too many arguments. Usage VOLUME-NAME [TARBALL]")
+	}
+
+	source := "-"
+	if len(args) == 2 {
+		source = args[1]
+	}
+	if source == "-" {
+		if logrus.IsTerminal(os.Stdin) {
+			return errors.Errorf("refusing to import from terminal. Give a tarball or redirect")
+		}
+		source = "/dev/stdin"
+	}
+	if err := validateFileName(source); err != nil {
+		return err
+	}
+
+	runtime, err := adapter.GetRuntime(c)
+	if err != nil {
+		return errors.Wrapf(err, "could not get runtime")
+	}
+	defer runtime.Shutdown(false)
+
+	return runtime.ImportVolume(args[0], source)
+}
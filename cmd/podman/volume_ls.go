@@ -7,6 +7,7 @@ import (
 	"github.com/containers/libpod/cmd/podman/formats"
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/util"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
@@ -25,6 +26,7 @@ type volumeLsTemplateParams struct {
 	Driver     string
 	Options    string
 	Scope      string
+	MountCount uint
 }
 
 // volumeLsJSONParams is the JSON parameters to list the volumes
@@ -35,6 +37,7 @@ type volumeLsJSONParams struct {
 	Driver     string            `json:"driver"`
 	Options    map[string]string `json:"options"`
 	Scope      string            `json:"scope"`
+	MountCount uint              `json:"mountCount"`
 }
 
 var volumeLsDescription = `
@@ -45,9 +48,9 @@ and the output format can be changed to JSON or a user specified Go template.
 `
 
 var volumeLsFlags = []cli.Flag{
-	cli.StringFlag{
+	cli.StringSliceFlag{
 		Name:  "filter, f",
-		Usage: "Filter volume output",
+		Usage: "Filter volume output (e.g. name=myvol, label=key=value, driver=local, dangling=true)",
 	},
 	cli.StringFlag{
 		Name:  "format",
@@ -92,40 +95,16 @@ func volumeLsCmd(c *cli.Context) error {
 	opts.Format = genVolLsFormat(c)
 
 	// Get the filter functions based on any filters set
-	var filterFuncs []libpod.VolumeFilter
-	if c.String("filter") != "" {
-		filters := strings.Split(c.String("filter"), ",")
-		for _, f := range filters {
-			filterSplit := strings.Split(f, "=")
-			if len(filterSplit) < 2 {
-				return errors.Errorf("filter input must be in the form of filter=value: %s is invalid", f)
-			}
-			generatedFunc, err := generateVolumeFilterFuncs(filterSplit[0], filterSplit[1], runtime)
-			if err != nil {
-				return errors.Wrapf(err, "invalid filter")
-			}
-			filterFuncs = append(filterFuncs, generatedFunc)
-		}
-	}
-
-	volumes, err := runtime.GetAllVolumes()
+	filterFuncs, err := getVolumeFilters(c.StringSlice("filter"))
 	if err != nil {
 		return err
 	}
 
-	// Get the volumes that match the filter
-	volsFiltered := make([]*libpod.Volume, 0, len(volumes))
-	for _, vol := range volumes {
-		include := true
-		for _, filter := range filterFuncs {
-			include = include && filter(vol)
-		}
-
-		if include {
-			volsFiltered = append(volsFiltered, vol)
-		}
+	volumes, err := runtime.Volumes(filterFuncs...)
+	if err != nil {
+		return err
 	}
-	return generateVolLsOutput(volsFiltered, opts, runtime)
+	return generateVolLsOutput(volumes, opts, runtime)
 }
 
 // generate the template based on conditions given
@@ -203,6 +182,7 @@ func getVolTemplateOutput(lsParams []volumeLsJSONParams, opts volumeLsOptions) (
 			Scope:      lsParam.Scope,
 			Labels:     labels,
 			Options:    options,
+			MountCount: lsParam.MountCount,
 		}
 
 		lsOutput = append(lsOutput, params)
@@ -215,6 +195,10 @@ func getVolJSONParams(volumes []*libpod.Volume, opts volumeLsOptions, runtime *l
 	var lsOutput []volumeLsJSONParams
 
 	for _, volume := range volumes {
+		mountCount, err := volume.MountCount()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting mount count of volume %s", volume.Name())
+		}
 		params := volumeLsJSONParams{
 			Name:       volume.Name(),
 			Labels:     volume.Labels(),
@@ -222,6 +206,7 @@ func getVolJSONParams(volumes []*libpod.Volume, opts volumeLsOptions, runtime *l
 			Driver:     volume.Driver(),
 			Options:    volume.Options(),
 			Scope:      volume.Scope(),
+			MountCount: mountCount,
 		}
 
 		lsOutput = append(lsOutput, params)
@@ -256,53 +241,22 @@ func generateVolLsOutput(volumes []*libpod.Volume, opts volumeLsOptions, runtime
 	return formats.Writer(out).Out()
 }
 
-// generateVolumeFilterFuncs returns the true if the volume matches the filter set, otherwise it returns false.
-func generateVolumeFilterFuncs(filter, filterValue string, runtime *libpod.Runtime) (func(volume *libpod.Volume) bool, error) {
-	switch filter {
-	case "name":
-		return func(v *libpod.Volume) bool {
-			return strings.Contains(v.Name(), filterValue)
-		}, nil
-	case "driver":
-		return func(v *libpod.Volume) bool {
-			return v.Driver() == filterValue
-		}, nil
-	case "scope":
-		return func(v *libpod.Volume) bool {
-			return v.Scope() == filterValue
-		}, nil
-	case "label":
-		filterArray := strings.SplitN(filterValue, "=", 2)
-		filterKey := filterArray[0]
-		if len(filterArray) > 1 {
-			filterValue = filterArray[1]
-		} else {
-			filterValue = ""
-		}
-		return func(v *libpod.Volume) bool {
-			for labelKey, labelValue := range v.Labels() {
-				if labelKey == filterKey && ("" == filterValue || labelValue == filterValue) {
-					return true
-				}
+// getVolumeFilters parses the filters given to a volume command. Each
+// filter may hold several comma separated filters. If allowed is not empty,
+// only the filter keys it lists are accepted.
+func getVolumeFilters(filters []string, allowed ...string) ([]libpod.VolumeFilter, error) {
+	var filterFuncs []libpod.VolumeFilter
+	for _, filters := range filters {
+		for _, filter := range strings.Split(filters, ",") {
+			if len(allowed) > 0 && !util.StringInSlice(strings.SplitN(filter, "=", 2)[0], allowed) {
+				return nil, errors.Errorf("invalid filter %q, valid filters are %s", filter, strings.Join(allowed, ", "))
 			}
-			return false
-		}, nil
-	case "opt":
-		filterArray := strings.SplitN(filterValue, "=", 2)
-		filterKey := filterArray[0]
-		if len(filterArray) > 1 {
-			filterValue = filterArray[1]
-		} else {
-			filterValue = ""
-		}
-		return func(v *libpod.Volume) bool {
-			for labelKey, labelValue := range v.Options() {
-				if labelKey == filterKey && ("" == filterValue || labelValue == filterValue) {
-					return true
-				}
+			filterFunc, err := libpod.GenerateVolumeFilter(filter)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid filter")
 			}
-			return false
-		}, nil
+			filterFuncs = append(filterFuncs, filterFunc)
+		}
 	}
-	return nil, errors.Errorf("%s is an invalid filter", filter)
+	return filterFuncs, nil
 }
@@ -17,11 +17,18 @@ import (
 var volumePruneDescription = `
 podman volume prune
 
-Remove all unused volumes. Will prompt for confirmation if not
-using force.
+Remove all unused volumes, or the unused volumes matching the given
+filters. Will prompt for confirmation if not using force.
 `
 
+// volumePruneFilters are the filters accepted when pruning volumes
+var volumePruneFilters = []string{"label", "until"}
+
 var volumePruneFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "filter",
+		Usage: "Only prune volumes matching the filter (e.g. label=key=value, until=24h)",
+	},
 	cli.BoolFlag{
 		Name:  "force, f",
 		Usage: "Do not prompt for confirmation",
@@ -38,10 +45,10 @@ var volumePruneCommand = cli.Command{
 	UseShortOptionHandling: true,
 }
 
-func volumePrune(runtime *adapter.LocalRuntime, ctx context.Context) error {
+func volumePrune(runtime *adapter.LocalRuntime, ctx context.Context, filters []libpod.VolumeFilter) error {
 	var lastError error
 
-	volumes, err := runtime.GetAllVolumes()
+	volumes, err := runtime.Volumes(filters...)
 	if err != nil {
 		return err
 	}
@@ -66,6 +73,11 @@ func volumePruneCmd(c *cli.Context) error {
 		return err
 	}
 
+	filters, err := getVolumeFilters(c.StringSlice("filter"), volumePruneFilters...)
+	if err != nil {
+		return err
+	}
+
 	runtime, err := adapter.GetRuntime(c)
 	if err != nil {
 		return errors.Wrapf(err, "error creating libpod runtime")
@@ -75,7 +87,11 @@ func volumePruneCmd(c *cli.Context) error {
 	// Prompt for confirmation if --force is not set
 	if !c.Bool("force") {
 		reader := bufio.NewReader(os.Stdin)
-		fmt.Println("WARNING! This will remove all volumes not used by at least one container.")
+		if len(filters) > 0 {
+			fmt.Println("WARNING! This will remove all volumes matching the filters not used by at least one container.")
+		} else {
+			fmt.Println("WARNING! This will remove all volumes not used by at least one container.")
+		}
 		fmt.Print("Are you sure you want to continue? [y/N] ")
 		ans, err := reader.ReadString('\n')
 		if err != nil {
@@ -86,5 +102,5 @@ func volumePruneCmd(c *cli.Context) error {
 		}
 	}
 
-	return volumePrune(runtime, getContext())
+	return volumePrune(runtime, getContext(), filters)
 }
@@ -0,0 +1,4 @@
+#!/bin/bash
+if pkg-config libsystemd 2> /dev/null ; then
+	echo systemd
+fi
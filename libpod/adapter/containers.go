@@ -0,0 +1,23 @@
+package adapter
+
+import (
+	"encoding/json"
+
+	cc "github.com/containers/libpod/pkg/spec"
+	"github.com/sirupsen/logrus"
+)
+
+// createdWithRm returns whether the container was created with --rm, in which
+// case it is removed when it fails to start
+func (c *Container) createdWithRm() bool {
+	var createArtifact cc.CreateConfig
+	artifact, err := c.GetArtifact("create-config")
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(artifact, &createArtifact); err != nil {
+		logrus.Errorf("unable to detect if container %s should be deleted", c.ID())
+		return false
+	}
+	return createArtifact.Rm
+}
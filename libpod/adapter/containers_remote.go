@@ -3,11 +3,13 @@
 package adapter
 
 import (
+	"context"
 	"encoding/json"
 
 	iopodman "github.com/containers/libpod/cmd/podman/varlink"
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/inspect"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 // Inspect returns an inspect struct from varlink
@@ -48,3 +50,35 @@ func (c *Container) Config() *libpod.ContainerConfig {
 	}
 	return c.Runtime.Config(c.ID())
 }
+
+// Spec returns the container's OCI runtime spec
+func (c *Container) Spec() *spec.Spec {
+	return c.Config().Spec
+}
+
+// State returns the current state of the container
+func (c *Container) State() (libpod.ContainerStatus, error) {
+	state, err := c.Runtime.ContainerState(c.ID())
+	if err != nil {
+		return libpod.ContainerStateUnknown, err
+	}
+	return state.State, nil
+}
+
+// Start starts the container
+func (c *Container) Start(ctx context.Context) error {
+	_, err := iopodman.StartContainer().Call(c.Runtime.Conn, c.ID())
+	return err
+}
+
+// Wait blocks until the container exits and returns its exit code
+func (c *Container) Wait() (int32, error) {
+	exitCode, err := iopodman.WaitContainer().Call(c.Runtime.Conn, c.ID())
+	return int32(exitCode), err
+}
+
+// Cleanup is a no-op for remote containers, the service cleans up the
+// containers it started attached and the exit command all other ones
+func (c *Container) Cleanup(ctx context.Context) error {
+	return nil
+}
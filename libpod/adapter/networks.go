@@ -0,0 +1,25 @@
+// +build !remoteclient
+
+package adapter
+
+import (
+	"net"
+)
+
+// ConnectNetwork connects a container to a CNI network
+func (r *LocalRuntime) ConnectNetwork(name, ctrName string, ip net.IP) error {
+	ctr, err := r.LookupContainer(ctrName)
+	if err != nil {
+		return err
+	}
+	return ctr.ConnectNetwork(name, ip)
+}
+
+// DisconnectNetwork disconnects a container from a CNI network
+func (r *LocalRuntime) DisconnectNetwork(name, ctrName string) error {
+	ctr, err := r.LookupContainer(ctrName)
+	if err != nil {
+		return err
+	}
+	return ctr.DisconnectNetwork(name)
+}
@@ -0,0 +1,89 @@
+// +build remoteclient
+
+package adapter
+
+import (
+	"context"
+	"net"
+
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/pkg/network"
+)
+
+// CreateNetwork creates a CNI network on the remote host
+func (r *LocalRuntime) CreateNetwork(options network.CreateOptions) (*network.Network, error) {
+	create := iopodman.NetworkCreate{
+		Name:     options.Name,
+		Driver:   options.Driver,
+		Internal: options.Internal,
+		Labels:   options.Labels,
+		Options:  options.Options,
+	}
+	if options.Subnet != nil {
+		create.Subnet = options.Subnet.String()
+	}
+	if options.Gateway != nil {
+		create.Gateway = options.Gateway.String()
+	}
+	if options.IPRange != nil {
+		create.IpRange = options.IPRange.String()
+	}
+	name, err := iopodman.CreateNetwork().Call(r.Conn, create)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetNetwork(name)
+}
+
+// GetNetwork retrieves a CNI network of the remote host by its name
+func (r *LocalRuntime) GetNetwork(name string) (*network.Network, error) {
+	config, err := iopodman.InspectNetwork().Call(r.Conn, name)
+	if err != nil {
+		return nil, err
+	}
+	// The path of the configuration file is not part of the configuration
+	return network.NewFromConfig("", []byte(config))
+}
+
+// Networks retrieves the CNI networks configured on the remote host
+func (r *LocalRuntime) Networks() ([]*network.Network, error) {
+	reply, err := iopodman.ListNetworks().Call(r.Conn)
+	if err != nil {
+		return nil, err
+	}
+	networks := make([]*network.Network, 0, len(reply))
+	for _, n := range reply {
+		networks = append(networks, &network.Network{
+			Name:       n.Name,
+			CNIVersion: n.CniVersion,
+			Plugins:    n.Plugins,
+			Subnets:    n.Subnets,
+			Labels:     n.Labels,
+			Path:       n.Path,
+		})
+	}
+	return networks, nil
+}
+
+// RemoveNetwork removes a CNI network of the remote host
+func (r *LocalRuntime) RemoveNetwork(ctx context.Context, name string, force bool) error {
+	_, err := iopodman.RemoveNetwork().Call(r.Conn, name, force)
+	return err
+}
+
+// ConnectNetwork connects a container of the remote host to a CNI network
+func (r *LocalRuntime) ConnectNetwork(name, ctrName string, ip net.IP) error {
+	staticIP := ""
+	if ip != nil {
+		staticIP = ip.String()
+	}
+	_, err := iopodman.ConnectNetwork().Call(r.Conn, name, ctrName, staticIP)
+	return err
+}
+
+// DisconnectNetwork disconnects a container of the remote host from a CNI
+// network
+func (r *LocalRuntime) DisconnectNetwork(name, ctrName string) error {
+	_, err := iopodman.DisconnectNetwork().Call(r.Conn, name, ctrName)
+	return err
+}
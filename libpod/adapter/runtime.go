@@ -8,13 +8,16 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"strconv"
+	"syscall"
 
 	"github.com/containers/image/types"
 	"github.com/containers/libpod/cmd/podman/libpodruntime"
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/libpod/image"
 	"github.com/containers/libpod/pkg/rootless"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
 
@@ -116,42 +119,154 @@ func (r *LocalRuntime) Export(name string, path string) error {
 	if err != nil {
 		return errors.Wrapf(err, "error looking up container %q", name)
 	}
-	if os.Geteuid() != 0 {
-		state, err := ctr.State()
+	if err := joinContainerUserNS(ctr); err != nil {
+		return err
+	}
+
+	return ctr.Export(path)
+}
+
+// ExportVolume is a wrapper to volume export to a tarfile
+func (r *LocalRuntime) ExportVolume(name string, path string) error {
+	vol, err := r.Runtime.GetVolume(name)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up volume %q", name)
+	}
+	return vol.Export(path)
+}
+
+// ImportVolume is a wrapper to volume import from a tarfile
+func (r *LocalRuntime) ImportVolume(name string, path string) error {
+	vol, err := r.Runtime.GetVolume(name)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up volume %q", name)
+	}
+	return vol.Import(path)
+}
+
+// CopyToContainer copies the file or directory src on the host into the
+// container at path dest. If src is "-", a tar archive is read from stdin and
+// extracted into the directory dest.
+func (r *LocalRuntime) CopyToContainer(src, name, dest string, pause bool) error {
+	ctr, err := r.Runtime.LookupContainer(name)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up container %q", name)
+	}
+	if err := joinContainerUserNS(ctr); err != nil {
+		return err
+	}
+
+	if src == "-" {
+		return ctr.CopyFromArchive(os.Stdin, dest, pause)
+	}
+	return ctr.CopyFromHost(src, dest, pause)
+}
+
+// CopyFromContainer copies the file or directory src in the container to the
+// host at path dest. If dest is "-", a tar archive of src is written to
+// stdout.
+func (r *LocalRuntime) CopyFromContainer(name, src, dest string, pause bool) error {
+	ctr, err := r.Runtime.LookupContainer(name)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up container %q", name)
+	}
+	if err := joinContainerUserNS(ctr); err != nil {
+		return err
+	}
+
+	if dest == "-" {
+		return ctr.CopyToArchive(src, os.Stdout, pause)
+	}
+	return ctr.CopyToHost(src, dest, pause)
+}
+
+// joinContainerUserNS makes a rootless podman join the user and mount
+// namespaces of the container if it is running, or create a new user
+// namespace otherwise, so its storage can be accessed
+func joinContainerUserNS(ctr *libpod.Container) error {
+	if os.Geteuid() == 0 {
+		return nil
+	}
+	state, err := ctr.State()
+	if err != nil {
+		return errors.Wrapf(err, "cannot read container state %q", ctr.ID())
+	}
+	if state == libpod.ContainerStateRunning || state == libpod.ContainerStatePaused {
+		data, err := ioutil.ReadFile(ctr.Config().ConmonPidFile)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read conmon PID file %q", ctr.Config().ConmonPidFile)
+		}
+		conmonPid, err := strconv.Atoi(string(data))
+		if err != nil {
+			return errors.Wrapf(err, "cannot parse PID %q", data)
+		}
+		became, ret, err := rootless.JoinDirectUserAndMountNS(uint(conmonPid))
 		if err != nil {
-			return errors.Wrapf(err, "cannot read container state %q", ctr.ID())
+			return err
 		}
-		if state == libpod.ContainerStateRunning || state == libpod.ContainerStatePaused {
-			data, err := ioutil.ReadFile(ctr.Config().ConmonPidFile)
-			if err != nil {
-				return errors.Wrapf(err, "cannot read conmon PID file %q", ctr.Config().ConmonPidFile)
-			}
-			conmonPid, err := strconv.Atoi(string(data))
-			if err != nil {
-				return errors.Wrapf(err, "cannot parse PID %q", data)
-			}
-			became, ret, err := rootless.JoinDirectUserAndMountNS(uint(conmonPid))
-			if err != nil {
-				return err
-			}
-			if became {
-				os.Exit(ret)
-			}
-		} else {
-			became, ret, err := rootless.BecomeRootInUserNS()
-			if err != nil {
-				return err
-			}
-			if became {
-				os.Exit(ret)
-			}
+		if became {
+			os.Exit(ret)
 		}
+		return nil
 	}
-
-	return ctr.Export(path)
+	became, ret, err := rootless.BecomeRootInUserNS()
+	if err != nil {
+		return err
+	}
+	if became {
+		os.Exit(ret)
+	}
+	return nil
 }
 
 // Import is a wrapper to import a container image
 func (r *LocalRuntime) Import(ctx context.Context, source, reference string, changes []string, history string, quiet bool) (string, error) {
 	return r.Runtime.Import(ctx, source, reference, changes, history, quiet)
 }
+
+// Attach attaches to the standard streams of a container, starting it first
+// if startContainer is set
+func (r *LocalRuntime) Attach(ctx context.Context, ctr *Container, stdout, stderr, stdin *os.File, detachKeys string, sigProxy, startContainer bool) error {
+	return StartAttachCtr(ctx, ctr.Container, stdout, stderr, stdin, detachKeys, sigProxy, startContainer)
+}
+
+// StartContainer starts a container, removing it if it fails to start and was
+// created with --rm
+func (r *LocalRuntime) StartContainer(ctx context.Context, ctr *Container) error {
+	err := ctr.Start(ctx)
+	if err != nil && ctr.createdWithRm() {
+		if rmErr := r.RemoveContainer(ctx, ctr.Container, true); rmErr != nil {
+			logrus.Errorf("unable to remove container %s after it failed to start", ctr.ID())
+		}
+	}
+	return err
+}
+
+// Exec runs a command in a running container, attached to the standard streams
+// of podman, and returns its exit code
+func (r *LocalRuntime) Exec(ctx context.Context, ctr *Container, config *libpod.ExecConfig) (int, error) {
+	pid, err := ctr.PID()
+	if err != nil {
+		return 125, err
+	}
+	became, ret, err := rootless.JoinNS(uint(pid))
+	if err != nil {
+		return 125, err
+	}
+	if became {
+		os.Exit(ret)
+	}
+
+	err = ctr.Exec(config.Terminal, config.Privileged, config.Env, config.Command, config.User, config.WorkDir, nil)
+	if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+		// The runtime already reported why it failed to run the
+		// command, if it did
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus(), nil
+		}
+	}
+	if err != nil {
+		return 125, err
+	}
+	return 0, nil
+}
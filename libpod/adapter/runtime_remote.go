@@ -9,18 +9,26 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/containers/image/types"
 	"github.com/containers/libpod/cmd/podman/varlink"
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/libpod/image"
+	"github.com/containers/libpod/pkg/varlinkapi/virtwriter"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/docker/docker/pkg/signal"
 	"github.com/opencontainers/go-digest"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 	"github.com/varlink/go/varlink"
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 // ImageRuntime is wrapper for image runtime
@@ -366,13 +374,179 @@ func (r *LocalRuntime) Export(name string, path string) error {
 	return nil
 }
 
-// Import implements the remote calls required to import a container image to the store
-func (r *LocalRuntime) Import(ctx context.Context, source, reference string, changes []string, history string, quiet bool) (string, error) {
-	// First we send the file to the host
-	fs, err := os.Open(source)
+// ExportVolume is a wrapper to volume export to a tarfile
+func (r *LocalRuntime) ExportVolume(name string, path string) error {
+	tempPath, err := iopodman.ExportVolume().Call(r.Conn, name, "")
+	if err != nil {
+		return err
+	}
+
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer outputFile.Close()
+
+	writer := bufio.NewWriter(outputFile)
+	defer writer.Flush()
+
+	reply, err := iopodman.ReceiveFile().Send(r.Conn, varlink.Upgrade, tempPath, true)
+	if err != nil {
+		return err
+	}
+
+	length, _, err := reply()
+	if err != nil {
+		return errors.Wrap(err, "unable to get file length for transfer")
+	}
+
+	if _, err := io.CopyN(writer, r.Conn.Reader, length); err != nil {
+		return errors.Wrap(err, "file transfer failed")
+	}
+	return nil
+}
+
+// ImportVolume is a wrapper to volume import from a tarfile
+func (r *LocalRuntime) ImportVolume(name string, path string) error {
+	// The length of the archive must be known to send it, so archives
+	// read from pipes are buffered in a temporary file first
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		tempFile, err := ioutil.TempFile("", "podman_volume")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tempFile.Name())
+		defer tempFile.Close()
+
+		input, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer input.Close()
+		if _, err := io.Copy(tempFile, input); err != nil {
+			return errors.Wrapf(err, "error reading archive from %q", path)
+		}
+		path = tempFile.Name()
+	}
+
+	remoteTarball, err := r.sendFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = iopodman.ImportVolume().Call(r.Conn, name, remoteTarball, true)
+	return err
+}
+
+// CopyToContainer copies the file or directory src on the client to dest in
+// the container, as CopyFromHost does. If src is "-", a tar archive is read
+// from stdin and extracted into the directory dest.
+func (r *LocalRuntime) CopyToContainer(src, name, dest string, pause bool) error {
+	tempFile, err := ioutil.TempFile("", "podman_copy")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	source := ""
+	if src == "-" {
+		if _, err := io.Copy(tempFile, os.Stdin); err != nil {
+			return errors.Wrapf(err, "error reading archive from stdin")
+		}
+	} else {
+		// The source is archived under its base name, the varlink
+		// service copies it to dest from there
+		source = src
+		srcDir, include := filepath.Split(filepath.Clean(src))
+		if srcDir == "" {
+			srcDir = "."
+		}
+		tarball, err := archive.TarWithOptions(srcDir, &archive.TarOptions{
+			Compression:  archive.Uncompressed,
+			IncludeFiles: []string{include},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error creating archive of %q", src)
+		}
+		defer tarball.Close()
+		if _, err := io.Copy(tempFile, tarball); err != nil {
+			return errors.Wrapf(err, "error creating archive of %q", src)
+		}
+	}
+
+	remoteTarball, err := r.sendFile(tempFile.Name())
+	if err != nil {
+		return err
+	}
+	_, err = iopodman.CopyToContainer().Call(r.Conn, name, remoteTarball, source, dest, pause)
+	return err
+}
+
+// CopyFromContainer copies the file or directory src in the container to dest
+// on the client, as CopyToHost does. If dest is "-", a tar archive of src is
+// written to stdout.
+func (r *LocalRuntime) CopyFromContainer(name, src, dest string, pause bool) error {
+	remoteTarball, err := iopodman.CopyFromContainer().Call(r.Conn, name, src, pause)
+	if err != nil {
+		return err
+	}
+
+	reply, err := iopodman.ReceiveFile().Send(r.Conn, varlink.Upgrade, remoteTarball, true)
+	if err != nil {
+		return err
+	}
+	length, _, err := reply()
+	if err != nil {
+		return errors.Wrap(err, "unable to get file length for transfer")
+	}
+	reader := io.LimitReader(r.Conn.Reader, length)
+
+	if dest == "-" {
+		_, err := io.Copy(os.Stdout, reader)
+		return err
+	}
+
+	options := &archive.TarOptions{NoLchown: true}
+	destInfo, err := os.Stat(dest)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error checking destination %q", dest)
+	}
+	if (err == nil && destInfo.IsDir()) || strings.HasSuffix(dest, "/") {
+		return archive.Untar(reader, dest, options)
+	}
+
+	// Extract into the parent directory of dest, and rename what was
+	// extracted to dest
+	tempDir, err := ioutil.TempDir(filepath.Dir(filepath.Clean(dest)), ".podman_copy")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	if err := archive.Untar(reader, tempDir, options); err != nil {
+		return err
+	}
+	extracted := tempDir
+	if !strings.HasSuffix(src, "/.") {
+		extracted = filepath.Join(tempDir, filepath.Base(filepath.Clean(src)))
+	}
+	if err := os.Rename(extracted, dest); err != nil {
+		return errors.Wrapf(err, "error copying %q to %q", src, dest)
+	}
+	return nil
+}
+
+// sendFile uploads the file at path to the host running the varlink service
+// and returns the path of the uploaded file on that host
+func (r *LocalRuntime) sendFile(path string) (string, error) {
+	fs, err := os.Open(path)
 	if err != nil {
 		return "", err
 	}
+	defer fs.Close()
 
 	fileInfo, err := fs.Stat()
 	if err != nil {
@@ -403,9 +577,18 @@ func (r *LocalRuntime) Import(ctx context.Context, source, reference string, cha
 	// r.Conn is kaput at this point due to the upgrade
 	if err := r.RemoteRuntime.RefreshConnection(); err != nil {
 		return "", err
+	}
+	return strings.TrimRight(tempFile, ":"), nil
+}
 
+// Import implements the remote calls required to import a container image to the store
+func (r *LocalRuntime) Import(ctx context.Context, source, reference string, changes []string, history string, quiet bool) (string, error) {
+	// First we send the file to the host
+	tempFile, err := r.sendFile(source)
+	if err != nil {
+		return "", err
 	}
-	return iopodman.ImportImage().Call(r.Conn, strings.TrimRight(tempFile, ":"), reference, history, changes, true)
+	return iopodman.ImportImage().Call(r.Conn, tempFile, reference, history, changes, true)
 }
 
 // GetAllVolumes retrieves all the volumes
@@ -413,6 +596,11 @@ func (r *LocalRuntime) GetAllVolumes() ([]*libpod.Volume, error) {
 	return nil, libpod.ErrNotImplemented
 }
 
+// Volumes retrieves the volumes matching all filters
+func (r *LocalRuntime) Volumes(filters ...libpod.VolumeFilter) ([]*libpod.Volume, error) {
+	return nil, libpod.ErrNotImplemented
+}
+
 // RemoveVolume removes a volumes
 func (r *LocalRuntime) RemoveVolume(ctx context.Context, v *libpod.Volume, force, prune bool) error {
 	return libpod.ErrNotImplemented
@@ -432,3 +620,237 @@ func (r *LocalRuntime) GetContainers(filters ...libpod.ContainerFilter) ([]*libp
 func (r *LocalRuntime) RemoveContainer(ctx context.Context, c *libpod.Container, force bool) error {
 	return libpod.ErrNotImplemented
 }
+
+// Attach attaches to the standard streams of a container over an upgraded
+// varlink connection, starting it first if startContainer is set
+func (r *LocalRuntime) Attach(ctx context.Context, ctr *Container, stdout, stderr, stdin *os.File, detachKeys string, sigProxy, startContainer bool) error {
+	resize := make(chan remotecommand.TerminalSize)
+
+	haveTerminal := terminal.IsTerminal(int(os.Stdin.Fd()))
+
+	// Check if we are attached to a terminal. If we are, generate resize
+	// events, and set the terminal to raw mode
+	spec := ctr.Spec()
+	if haveTerminal && spec != nil && spec.Process != nil && spec.Process.Terminal {
+		cancel, oldTermState, err := handleTerminalAttach(ctx, resize)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+		defer restoreTerminal(oldTermState)
+	}
+
+	// The upgraded connection cannot be used for other calls afterwards
+	conn, err := r.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	reply, err := iopodman.AttachToContainer().Send(conn, varlink.Upgrade, ctr.ID(), detachKeys, stdin != nil, startContainer)
+	if err != nil {
+		return err
+	}
+	if _, err := reply(); err != nil {
+		return err
+	}
+
+	if sigProxy {
+		r.proxySignals(ctr)
+	}
+
+	if startContainer && stdout == nil && stderr == nil {
+		fmt.Printf("%s\n", ctr.ID())
+	}
+
+	if err := streamAttach(conn, stdout, stderr, stdin, resize); err != nil {
+		return errors.Wrapf(err, "error attaching to container %s", ctr.ID())
+	}
+	return nil
+}
+
+// streamAttach copies the given streams and terminal resize events over an
+// upgraded attach connection until the service ends the session, see the
+// virtwriter package for the protocol
+func streamAttach(conn *varlink.Connection, stdout, stderr, stdin *os.File, resize chan remotecommand.TerminalSize) error {
+	mux := virtwriter.NewMux(conn.Writer)
+	if stdin != nil {
+		go func() {
+			if _, err := io.Copy(mux.Writer(virtwriter.ToStdin), stdin); err != nil {
+				logrus.Debugf("error reading standard input: %v", err)
+			}
+			// An empty frame closes the standard input on the service
+			if err := mux.WriteFrame(virtwriter.ToStdin, nil); err != nil {
+				logrus.Debugf("error closing standard input: %v", err)
+			}
+		}()
+	}
+	go func() {
+		for size := range resize {
+			if err := mux.Resize(size); err != nil {
+				logrus.Debugf("error resizing terminal: %v", err)
+			}
+		}
+	}()
+
+	writers := make(map[virtwriter.SocketDest]io.Writer)
+	if stdout != nil {
+		writers[virtwriter.ToStdout] = stdout
+	}
+	if stderr != nil {
+		writers[virtwriter.ToStderr] = stderr
+	}
+	return virtwriter.Demux(conn.Reader, writers, nil)
+}
+
+// proxySignals forwards the signals received by podman to the container
+func (r *LocalRuntime) proxySignals(ctr *Container) {
+	sigBuffer := make(chan os.Signal, 128)
+	signal.CatchAll(sigBuffer)
+
+	logrus.Debugf("Enabling signal proxying")
+
+	go func() {
+		for s := range sigBuffer {
+			// Ignore SIGCHLD and SIGPIPE - these are mostly likely
+			// intended for the podman command itself.
+			if s == signal.SIGCHLD || s == signal.SIGPIPE {
+				continue
+			}
+
+			if _, err := iopodman.KillContainer().Call(r.Conn, ctr.ID(), int64(s.(syscall.Signal))); err != nil {
+				logrus.Errorf("Error forwarding signal %d to container %s: %v", s, ctr.ID(), err)
+				signal.StopCatch(sigBuffer)
+				syscall.Kill(syscall.Getpid(), s.(syscall.Signal))
+			}
+		}
+	}()
+}
+
+// StartContainer starts a container, removing it if it fails to start and was
+// created with --rm
+func (r *LocalRuntime) StartContainer(ctx context.Context, ctr *Container) error {
+	err := ctr.Start(ctx)
+	if err != nil && ctr.createdWithRm() {
+		if _, rmErr := iopodman.RemoveContainer().Call(r.Conn, ctr.ID(), true); rmErr != nil {
+			logrus.Errorf("unable to remove container %s after it failed to start", ctr.ID())
+		}
+	}
+	return err
+}
+
+// Run creates a container from the given configuration and starts it. Unless
+// the container is detached, Run attaches to it and returns its exit code.
+func (r *LocalRuntime) Run(ctx context.Context, create iopodman.Create, stdout, stderr, stdin *os.File, detachKeys string, sigProxy bool) (int, error) {
+	id, err := iopodman.CreateContainer().Call(r.Conn, create)
+	if err != nil {
+		return 125, err
+	}
+	ctr, err := r.LookupContainer(id)
+	if err != nil {
+		return 125, err
+	}
+
+	if create.Detach {
+		if err := r.StartContainer(ctx, ctr); err != nil {
+			return startExitCode(err), err
+		}
+		fmt.Printf("%s\n", ctr.ID())
+		return 0, nil
+	}
+
+	if err := r.Attach(ctx, ctr, stdout, stderr, stdin, detachKeys, sigProxy, true); err != nil {
+		if create.Rm {
+			if _, rmErr := iopodman.RemoveContainer().Call(r.Conn, ctr.ID(), true); rmErr != nil {
+				logrus.Errorf("unable to remove container %s after failing to start and attach to it", ctr.ID())
+			}
+		}
+		return startExitCode(err), err
+	}
+
+	exitCode, err := ctr.Wait()
+	if err != nil {
+		logrus.Errorf("Cannot get exit code: %v", err)
+		exitCode = 127
+	}
+	if create.Rm {
+		if _, err := iopodman.RemoveContainer().Call(r.Conn, ctr.ID(), true); err != nil {
+			return int(exitCode), err
+		}
+	}
+	return int(exitCode), nil
+}
+
+// startExitCode returns the exit code for a container that failed to start
+func startExitCode(err error) int {
+	// This means the command did not exist
+	if strings.Contains(err.Error(), "permission denied") {
+		return 126
+	}
+	return 127
+}
+
+// Exec runs a command in a running container, attached to the standard streams
+// of podman, and returns its exit code
+func (r *LocalRuntime) Exec(ctx context.Context, ctr *Container, config *libpod.ExecConfig) (int, error) {
+	resize := make(chan remotecommand.TerminalSize)
+
+	// Check if we are attached to a terminal. If we are, generate resize
+	// events, and set the terminal to raw mode
+	if config.Terminal && terminal.IsTerminal(int(os.Stdin.Fd())) {
+		cancel, oldTermState, err := handleTerminalAttach(ctx, resize)
+		if err != nil {
+			return 125, err
+		}
+		defer cancel()
+		defer restoreTerminal(oldTermState)
+	}
+
+	execConfig := iopodman.ExecConfig{
+		Command:    config.Command,
+		Env:        config.Env,
+		Tty:        config.Terminal,
+		Privileged: config.Privileged,
+		User:       config.User,
+		Workdir:    config.WorkDir,
+	}
+	sessionID, err := iopodman.CreateExec().Call(r.Conn, ctr.ID(), execConfig)
+	if err != nil {
+		return 125, err
+	}
+	defer func() {
+		if err := iopodman.RemoveExec().Call(r.Conn, ctr.ID(), sessionID); err != nil {
+			logrus.Errorf("Error removing exec session %s from container %s: %v", sessionID, ctr.ID(), err)
+		}
+	}()
+
+	// The upgraded connection cannot be used for other calls afterwards
+	conn, err := r.Connect()
+	if err != nil {
+		return 125, err
+	}
+	defer conn.Close()
+
+	reply, err := iopodman.StartExec().Send(conn, varlink.Upgrade, ctr.ID(), sessionID, false, true)
+	if err != nil {
+		return 125, err
+	}
+	if _, err := reply(); err != nil {
+		return 125, err
+	}
+	attachErr := streamAttach(conn, os.Stdout, os.Stderr, os.Stdin, resize)
+
+	// The exit code is recorded in the exec session once the runtime
+	// exited, even if it failed to run the command
+	session, err := iopodman.InspectExec().Call(r.Conn, ctr.ID(), sessionID)
+	if err != nil {
+		return 125, err
+	}
+	if session.State != libpod.ExecStateStopped.String() {
+		if attachErr == nil {
+			attachErr = errors.Errorf("exec session %s is %s", sessionID, session.State)
+		}
+		return 125, errors.Wrapf(attachErr, "error executing in container %s", ctr.ID())
+	}
+	return int(session.ExitCode), nil
+}
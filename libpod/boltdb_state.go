@@ -122,7 +122,7 @@ func (s *BoltState) Close() error {
 	return nil
 }
 
-// Refresh clears container and pod states after a reboot
+// Refresh clears container, pod and volume states after a reboot
 func (s *BoltState) Refresh() error {
 	if !s.valid {
 		return ErrDBClosed
@@ -226,7 +226,30 @@ func (s *BoltState) Refresh() error {
 
 			return nil
 		})
-		return err
+		if err != nil {
+			return err
+		}
+
+		// Volumes are no longer mounted after a reboot, so reset
+		// their mount counts
+		volsBucket, err := getVolBucket(tx)
+		if err != nil {
+			return err
+		}
+		freshVolState, err := json.Marshal(new(VolumeState))
+		if err != nil {
+			return errors.Wrapf(err, "error marshalling volume state")
+		}
+		return volsBucket.ForEach(func(name, _ []byte) error {
+			volBkt := volsBucket.Bucket(name)
+			if volBkt == nil {
+				return errors.Wrapf(ErrInternal, "volume %s is not a bucket", string(name))
+			}
+			if err := volBkt.Put(stateKey, freshVolState); err != nil {
+				return errors.Wrapf(err, "error updating state for volume %s in DB", string(name))
+			}
+			return nil
+		})
 	})
 	return err
 }
@@ -657,6 +680,194 @@ func (s *BoltState) SaveContainer(ctr *Container) error {
 	return err
 }
 
+// RewriteContainerConfig replaces the configuration of the given container in
+// the database
+func (s *BoltState) RewriteContainerConfig(ctr *Container, newCfg *ContainerConfig) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	if !ctr.valid {
+		return ErrCtrRemoved
+	}
+
+	if s.namespace != "" && s.namespace != ctr.config.Namespace {
+		return errors.Wrapf(ErrNSMismatch, "container %s is in namespace %q, does not match our namespace %q", ctr.ID(), ctr.config.Namespace, s.namespace)
+	}
+
+	if err := checkConfigRewrite(ctr.config, newCfg); err != nil {
+		return err
+	}
+
+	configJSON, err := json.Marshal(newCfg)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling container %s config to JSON", ctr.ID())
+	}
+
+	ctrID := []byte(ctr.ID())
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		ctrBucket, err := getCtrBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		ctrToUpdate := ctrBucket.Bucket(ctrID)
+		if ctrToUpdate == nil {
+			ctr.valid = false
+			return errors.Wrapf(ErrNoSuchCtr, "container %s does not exist in DB", ctr.ID())
+		}
+
+		if err := ctrToUpdate.Put(configKey, configJSON); err != nil {
+			return errors.Wrapf(err, "error updating container %s config in DB", ctr.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ctr.config = newCfg
+
+	return nil
+}
+
+// RenameContainer changes the name of the given container in the database
+func (s *BoltState) RenameContainer(ctr *Container, newName string) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	if !ctr.valid {
+		return ErrCtrRemoved
+	}
+
+	if s.namespace != "" && s.namespace != ctr.config.Namespace {
+		return errors.Wrapf(ErrNSMismatch, "container %s is in namespace %q, does not match our namespace %q", ctr.ID(), ctr.config.Namespace, s.namespace)
+	}
+
+	if newName == ctr.Name() {
+		return nil
+	}
+
+	newConfig := *ctr.config
+	newConfig.Name = newName
+	configJSON, err := json.Marshal(&newConfig)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling container %s config to JSON", ctr.ID())
+	}
+
+	ctrID := []byte(ctr.ID())
+	oldName := []byte(ctr.Name())
+	ctrName := []byte(newName)
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		idsBucket, err := getIDBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		namesBucket, err := getNamesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		ctrBucket, err := getCtrBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		allCtrsBucket, err := getAllCtrsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		ctrToRename := ctrBucket.Bucket(ctrID)
+		if ctrToRename == nil {
+			ctr.valid = false
+			return errors.Wrapf(ErrNoSuchCtr, "container %s does not exist in DB", ctr.ID())
+		}
+
+		if nameExist := namesBucket.Get(ctrName); nameExist != nil {
+			return errors.Wrapf(ErrCtrExists, "name %s is in use", newName)
+		}
+
+		// Swap the name in the registries
+		if err := namesBucket.Delete(oldName); err != nil {
+			return errors.Wrapf(err, "error removing container %s old name (%s) from DB", ctr.ID(), ctr.Name())
+		}
+		if err := namesBucket.Put(ctrName, ctrID); err != nil {
+			return errors.Wrapf(err, "error adding container %s name (%s) to DB", ctr.ID(), newName)
+		}
+		if err := idsBucket.Put(ctrID, ctrName); err != nil {
+			return errors.Wrapf(err, "error updating container %s name in ID registry", ctr.ID())
+		}
+		if err := allCtrsBucket.Put(ctrID, ctrName); err != nil {
+			return errors.Wrapf(err, "error updating container %s in all containers bucket in DB", ctr.ID())
+		}
+
+		if err := ctrToRename.Put(configKey, configJSON); err != nil {
+			return errors.Wrapf(err, "error updating container %s config in DB", ctr.ID())
+		}
+
+		// Containers this container depends on record its name
+		for _, dependsCtr := range ctr.Dependencies() {
+			depCtrBkt := ctrBucket.Bucket([]byte(dependsCtr))
+			if depCtrBkt == nil {
+				continue
+			}
+			depCtrDependsBkt := depCtrBkt.Bucket(dependenciesBkt)
+			if depCtrDependsBkt == nil {
+				return errors.Wrapf(ErrInternal, "container %s does not have a dependencies bucket", dependsCtr)
+			}
+			if err := depCtrDependsBkt.Put(ctrID, ctrName); err != nil {
+				return errors.Wrapf(err, "error updating name of ctr %s as dependency of container %s", ctr.ID(), dependsCtr)
+			}
+		}
+
+		// As does the pod the container is part of
+		if podID := ctrToRename.Get(podIDKey); podID != nil {
+			podBucket, err := getPodBucket(tx)
+			if err != nil {
+				return err
+			}
+			podDB := podBucket.Bucket(podID)
+			if podDB == nil {
+				return errors.Wrapf(ErrNoSuchPod, "container %s is in pod %s which does not exist in database", ctr.ID(), string(podID))
+			}
+			podCtrs := podDB.Bucket(containersBkt)
+			if podCtrs == nil {
+				return errors.Wrapf(ErrInternal, "pod %s does not have a containers bucket", string(podID))
+			}
+			if err := podCtrs.Put(ctrID, ctrName); err != nil {
+				return errors.Wrapf(err, "error updating container %s name in pod %s", ctr.ID(), string(podID))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ctr.config.Name = newName
+
+	return nil
+}
+
 // ContainerInUse checks if other containers depend on the given container
 // It returns a slice of the IDs of the containers depending on the given
 // container. If the slice is empty, no containers depend on the given container
@@ -1176,6 +1387,11 @@ func (s *BoltState) AddVolume(volume *Volume) error {
 		return errors.Wrapf(err, "error marshalling volume %s config to JSON", volume.Name())
 	}
 
+	volStateJSON, err := json.Marshal(volume.state)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling volume %s state to JSON", volume.Name())
+	}
+
 	db, err := s.getDBCon()
 	if err != nil {
 		return err
@@ -1216,6 +1432,10 @@ func (s *BoltState) AddVolume(volume *Volume) error {
 			return errors.Wrapf(err, "error storing volume %s configuration in DB", volume.Name())
 		}
 
+		if err := newVol.Put(stateKey, volStateJSON); err != nil {
+			return errors.Wrapf(err, "error storing volume %s state in DB", volume.Name())
+		}
+
 		if err := allVolsBkt.Put(volName, volName); err != nil {
 			return errors.Wrapf(err, "error storing volume %s in all volumes bucket in DB", volume.Name())
 		}
@@ -1344,6 +1564,103 @@ func (s *BoltState) RemoveVolume(volume *Volume) error {
 	return err
 }
 
+// UpdateVolume updates a volume's state from the database
+func (s *BoltState) UpdateVolume(volume *Volume) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	if !volume.valid {
+		return ErrVolumeRemoved
+	}
+
+	newState := new(VolumeState)
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	volName := []byte(volume.Name())
+
+	err = db.View(func(tx *bolt.Tx) error {
+		volBkt, err := getVolBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		volDB := volBkt.Bucket(volName)
+		if volDB == nil {
+			volume.valid = false
+			return errors.Wrapf(ErrNoSuchVolume, "no volume with name %s found in database", volume.Name())
+		}
+
+		// Volumes created by older versions have no state
+		volStateBytes := volDB.Get(stateKey)
+		if volStateBytes == nil {
+			return nil
+		}
+
+		if err := json.Unmarshal(volStateBytes, newState); err != nil {
+			return errors.Wrapf(err, "error unmarshalling volume %s state JSON", volume.Name())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	volume.state = newState
+
+	return nil
+}
+
+// SaveVolume saves a volume's state to the database
+func (s *BoltState) SaveVolume(volume *Volume) error {
+	if !s.valid {
+		return ErrDBClosed
+	}
+
+	if !volume.valid {
+		return ErrVolumeRemoved
+	}
+
+	stateJSON, err := json.Marshal(volume.state)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling volume %s state to JSON", volume.Name())
+	}
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.closeDBCon(db)
+
+	volName := []byte(volume.Name())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		volBkt, err := getVolBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		volDB := volBkt.Bucket(volName)
+		if volDB == nil {
+			volume.valid = false
+			return errors.Wrapf(ErrNoSuchVolume, "no volume with name %s found in database", volume.Name())
+		}
+
+		if err := volDB.Put(stateKey, stateJSON); err != nil {
+			return errors.Wrapf(err, "error updating volume %s state in database", volume.Name())
+		}
+
+		return nil
+	})
+	return err
+}
+
 // AllVolumes returns all volumes present in the state
 func (s *BoltState) AllVolumes() ([]*Volume, error) {
 	if !s.valid {
@@ -124,6 +124,33 @@ func getTestPod(id, name string, manager lock.Manager) (*Pod, error) {
 	return pod, nil
 }
 
+// Get a new volume with the given name
+func getTestVolume(name string, manager lock.Manager) (*Volume, error) {
+	vol := &Volume{
+		config: &VolumeConfig{
+			Name:       name,
+			Labels:     map[string]string{"a": "b"},
+			MountPoint: "/path/to/volumes/" + name + "/_data",
+			Driver:     LocalVolumeDriver,
+			Options:    map[string]string{"type": "tmpfs"},
+			Scope:      "local",
+		},
+		state: &VolumeState{
+			MountCount: 1,
+		},
+		valid: true,
+	}
+
+	lock, err := manager.AllocateLock()
+	if err != nil {
+		return nil, err
+	}
+	vol.lock = lock
+	vol.config.LockID = lock.ID()
+
+	return vol, nil
+}
+
 func getTestCtrN(n string, manager lock.Manager) (*Container, error) {
 	return getTestContainer(strings.Repeat(n, 32), "test"+n, manager)
 }
@@ -10,7 +10,9 @@ import (
 
 	"github.com/containernetworking/cni/pkg/types"
 	cnitypes "github.com/containernetworking/cni/pkg/types/current"
+	"github.com/containers/image/manifest"
 	"github.com/containers/libpod/libpod/lock"
+	"github.com/containers/libpod/pkg/inspect"
 	"github.com/containers/libpod/pkg/namespaces"
 	"github.com/containers/storage"
 	"github.com/cri-o/ocicni/pkg/ocicni"
@@ -55,6 +57,49 @@ const SystemdDefaultCgroupParent = "machine.slice"
 // while waiting.
 const DefaultWaitInterval = 250 * time.Millisecond
 
+// Valid restart policy types.
+const (
+	// RestartPolicyNone indicates that no restart policy has been requested
+	// by a container.
+	RestartPolicyNone = ""
+	// RestartPolicyNo is identical in function to RestartPolicyNone.
+	RestartPolicyNo = "no"
+	// RestartPolicyAlways unconditionally restarts the container.
+	RestartPolicyAlways = "always"
+	// RestartPolicyOnFailure restarts the container on non-0 exit code,
+	// with an optional maximum number of retries.
+	RestartPolicyOnFailure = "on-failure"
+	// RestartPolicyUnlessStopped unconditionally restarts the container
+	// unless it was explicitly stopped by the user.
+	RestartPolicyUnlessStopped = "unless-stopped"
+)
+
+// Valid log drivers for a container
+const (
+	// KubernetesLogging is the default log driver. Container output is
+	// written by conmon to the container's log path in the CRI format
+	// used by Kubernetes.
+	KubernetesLogging = "k8s-file"
+	// JSONLogging is accepted for compatibility with Docker and is
+	// handled like KubernetesLogging.
+	JSONLogging = "json-file"
+	// JournaldLogging sends container output to the systemd journal.
+	JournaldLogging = "journald"
+	// NoLogging discards all container output.
+	NoLogging = "none"
+)
+
+// Valid forwarders of the published ports of rootless containers
+const (
+	// SlirpPortForwarder is the default forwarder. Ports are forwarded
+	// by slirp4netns, configured through its API.
+	SlirpPortForwarder = "slirp4netns"
+	// RootlessPortForwarder forwards ports with a helper process of
+	// podman listening on the host ports, which can expose the address
+	// of peers to the container.
+	RootlessPortForwarder = "rootlessport"
+)
+
 // LinuxNS represents a Linux namespace
 type LinuxNS int
 
@@ -171,7 +216,7 @@ type ContainerState struct {
 	OOMKilled bool `json:"oomKilled,omitempty"`
 	// PID is the PID of a running container
 	PID int `json:"pid,omitempty"`
-	// ExecSessions contains active exec sessions for container
+	// ExecSessions contains the exec sessions of the container
 	// Exec session ID is mapped to PID of exec process
 	ExecSessions map[string]*ExecSession `json:"execSessions,omitempty"`
 	// NetworkStatus contains the configuration results for all networks
@@ -185,6 +230,9 @@ type ContainerState struct {
 	// This maps the path the file will be mounted to in the container to
 	// the path of the file on disk outside the container
 	BindMounts map[string]string `json:"bindMounts,omitempty"`
+	// VolumeMounts maps the names of the named volumes that were mounted
+	// for the container to the paths their contents are at on the host
+	VolumeMounts map[string]string `json:"volumeMounts,omitempty"`
 
 	// UserNSRoot is the directory used as root for the container when using
 	// user namespaces.
@@ -194,16 +242,81 @@ type ContainerState struct {
 	// and not delegated to the OCI runtime.
 	ExtensionStageHooks map[string][]spec.Hook `json:"extensionStageHooks,omitempty"`
 
+	// StoppedByUser indicates whether the container was stopped by an
+	// explicit call to the Stop() API.
+	StoppedByUser bool `json:"stoppedByUser,omitempty"`
+	// RestartPolicyMatch indicates whether the conditions for restart
+	// policy have been met.
+	RestartPolicyMatch bool `json:"restartPolicyMatch,omitempty"`
+	// RestartCount is how many times the container was restarted by its
+	// restart policy. This is NOT incremented by normal container restarts
+	// (only by restart policy).
+	RestartCount uint `json:"restartCount,omitempty"`
+
+	// HealthCheck holds the status and recent results of the container's
+	// healthcheck. It is nil if the container has no healthcheck or it
+	// has not been started yet.
+	HealthCheck *inspect.HealthCheckResults `json:"healthCheck,omitempty"`
+
 	// containerPlatformState holds platform-specific container state.
 	containerPlatformState
 }
 
-// ExecSession contains information on an active exec session
+// ExecSessionStatus represents the current state of an exec session
+type ExecSessionStatus int
+
+const (
+	// ExecStateUnknown indicates that the state of the exec session is
+	// unknown. Sessions saved before states were tracked have this state.
+	ExecStateUnknown ExecSessionStatus = iota
+	// ExecStateCreated indicates the exec session has been created but
+	// not started
+	ExecStateCreated ExecSessionStatus = iota
+	// ExecStateRunning indicates the command of the exec session is
+	// executing
+	ExecStateRunning ExecSessionStatus = iota
+	// ExecStateStopped indicates the command of the exec session has
+	// exited
+	ExecStateStopped ExecSessionStatus = iota
+)
+
+// ExecSession contains information on an exec session
 // easyjson:json
 type ExecSession struct {
 	ID      string   `json:"id"`
 	Command []string `json:"command"`
 	PID     int      `json:"pid"`
+	// State is the state of the exec session
+	State ExecSessionStatus `json:"state,omitempty"`
+	// ExitCode is the exit code of the command, only valid once the
+	// session is stopped
+	ExitCode int `json:"exitCode,omitempty"`
+	// Config is the configuration the exec session was created with
+	Config *ExecConfig `json:"config,omitempty"`
+	// CreatedTime is the time the exec session was created
+	CreatedTime time.Time `json:"createdTime"`
+	// Detached is whether the command of the exec session was started
+	// detached, through conmon, which records its exit code in the exit
+	// file of the session
+	Detached bool `json:"detached,omitempty"`
+}
+
+// ExecConfig contains the configuration of an exec session
+// easyjson:json
+type ExecConfig struct {
+	// Command is the command to run in the container
+	Command []string `json:"command"`
+	// Env is a list of additional environment variables, formatted as
+	// KEY=VALUE
+	Env []string `json:"env,omitempty"`
+	// Terminal is whether a terminal is allocated for the command
+	Terminal bool `json:"terminal,omitempty"`
+	// Privileged is whether the command gets all capabilities
+	Privileged bool `json:"privileged,omitempty"`
+	// User is the user, and optionally the group, the command runs as
+	User string `json:"user,omitempty"`
+	// WorkDir is the working directory of the command
+	WorkDir string `json:"workDir,omitempty"`
 }
 
 // ContainerConfig contains all information that was used to create the
@@ -288,10 +401,25 @@ type ContainerConfig struct {
 	// This cannot be set unless CreateNetNS is set.
 	// If not set, the container will be dynamically assigned an IP by CNI.
 	StaticIP net.IP `json:"staticIP"`
+	// StaticMAC is a static MAC address to request for the interface of
+	// the container in its first CNI network.
+	// This cannot be set unless CreateNetNS is set.
+	StaticMAC net.HardwareAddr `json:"staticMAC,omitempty"`
+	// StaticIPs are static IPv4 and IPv6 addresses to request for the
+	// container, by CNI network. At most one address of each family is
+	// requested from a network.
+	// This cannot be set unless CreateNetNS is set.
+	StaticIPs map[string][]net.IP `json:"staticIPs,omitempty"`
 	// PortMappings are the ports forwarded to the container's network
 	// namespace
 	// These are not used unless CreateNetNS is true
 	PortMappings []ocicni.PortMapping `json:"portMappings,omitempty"`
+	// PortForwarder is the forwarder of the port mappings of a rootless
+	// container. If empty, SlirpPortForwarder is used.
+	PortForwarder string `json:"portForwarder,omitempty"`
+	// PortPeerAddress is how RootlessPortForwarder exposes the address of
+	// the peers of forwarded connections to the container
+	PortPeerAddress string `json:"portPeerAddress,omitempty"`
 	// DNS servers to use in container resolv.conf
 	// Will override servers in host resolv if set
 	DNSServer []net.IP `json:"dnsServer,omitempty"`
@@ -344,6 +472,9 @@ type ContainerConfig struct {
 	CgroupParent string `json:"cgroupParent"`
 	// LogPath log location
 	LogPath string `json:"logPath"`
+	// LogDriver is the driver used to log the container's output. If empty,
+	// KubernetesLogging is used.
+	LogDriver string `json:"logDriver,omitempty"`
 	// File containing the conmon PID
 	ConmonPidFile string `json:"conmonPidFile,omitempty"`
 	// TODO log options for log drivers
@@ -366,6 +497,23 @@ type ContainerConfig struct {
 
 	// Systemd tells libpod to setup the container in systemd mode
 	Systemd bool `json:"systemd"`
+
+	// RestartPolicy indicates what action the container will take upon
+	// exiting naturally.
+	// Allowed options are "no" (take no action), "on-failure" (restart on
+	// non-zero exit code, up to a maximum of RestartRetries times),
+	// "always" (always restart the container on any exit code) and
+	// "unless-stopped" (always restart unless the user stopped it).
+	// The empty string is treated as the default ("no")
+	RestartPolicy string `json:"restart_policy,omitempty"`
+	// RestartRetries indicates the number of attempts that will be made to
+	// restart the container. Used only if RestartPolicy is set to
+	// "on-failure".
+	RestartRetries uint `json:"restart_retries,omitempty"`
+
+	// HealthCheckConfig is the healthcheck to run in the container, if
+	// any. It is usually inherited from the HEALTHCHECK of the image.
+	HealthCheckConfig *manifest.Schema2HealthConfig `json:"healthcheck,omitempty"`
 }
 
 // ContainerStatus returns a string representation for users
@@ -390,6 +538,21 @@ func (t ContainerStatus) String() string {
 	return "bad state"
 }
 
+// String returns a string representation of an exec session state
+func (s ExecSessionStatus) String() string {
+	switch s {
+	case ExecStateUnknown:
+		return "unknown"
+	case ExecStateCreated:
+		return "created"
+	case ExecStateRunning:
+		return "running"
+	case ExecStateStopped:
+		return "stopped"
+	}
+	return "bad state"
+}
+
 // Config accessors
 // Unlocked
 
@@ -667,6 +830,36 @@ func (c *Container) LogPath() string {
 	return c.config.LogPath
 }
 
+// LogDriver returns the log driver of the container
+func (c *Container) LogDriver() string {
+	if c.config.LogDriver == "" {
+		return KubernetesLogging
+	}
+	return c.config.LogDriver
+}
+
+// RestartPolicy returns the container's restart policy.
+func (c *Container) RestartPolicy() string {
+	return c.config.RestartPolicy
+}
+
+// RestartRetries returns the number of retries that will be attempted when
+// using the "on-failure" restart policy
+func (c *Container) RestartRetries() uint {
+	return c.config.RestartRetries
+}
+
+// HealthCheckConfig returns the healthcheck configuration of the container,
+// or nil if it has none
+func (c *Container) HealthCheckConfig() *manifest.Schema2HealthConfig {
+	return c.config.HealthCheckConfig
+}
+
+// HasHealthCheck returns whether the container has a healthcheck configured
+func (c *Container) HasHealthCheck() bool {
+	return c.config.HealthCheckConfig != nil
+}
+
 // RuntimeName returns the name of the runtime
 func (c *Container) RuntimeName() string {
 	return c.runtime.ociRuntime.name
@@ -792,6 +985,19 @@ func (c *Container) OOMKilled() (bool, error) {
 	return c.state.OOMKilled, nil
 }
 
+// RestartCount returns how many times the container has been restarted by its
+// restart policy
+func (c *Container) RestartCount() (uint, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if err := c.syncContainer(); err != nil {
+			return 0, errors.Wrapf(err, "error updating container %s state", c.ID())
+		}
+	}
+	return c.state.RestartCount, nil
+}
+
 // PID returns the PID of the container
 // If the container is not running, a pid of 0 will be returned. No error will
 // occur.
@@ -808,7 +1014,7 @@ func (c *Container) PID() (int, error) {
 	return c.state.PID, nil
 }
 
-// ExecSessions retrieves active exec sessions running in the container
+// ExecSessions retrieves the IDs of the exec sessions of the container
 func (c *Container) ExecSessions() ([]string, error) {
 	if !c.batched {
 		c.lock.Lock()
@@ -827,8 +1033,8 @@ func (c *Container) ExecSessions() ([]string, error) {
 	return ids, nil
 }
 
-// ExecSession retrieves detailed information on a single active exec session in
-// a container
+// ExecSession retrieves detailed information on a single exec session in a
+// container
 func (c *Container) ExecSession(id string) (*ExecSession, error) {
 	if !c.batched {
 		c.lock.Lock()
@@ -841,13 +1047,19 @@ func (c *Container) ExecSession(id string) (*ExecSession, error) {
 
 	session, ok := c.state.ExecSessions[id]
 	if !ok {
-		return nil, errors.Wrapf(ErrNoSuchCtr, "no exec session with ID %s found in container %s", id, c.ID())
+		return nil, errors.Wrapf(ErrNoSuchExecSession, "no exec session with ID %s found in container %s", id, c.ID())
 	}
 
 	returnSession := new(ExecSession)
 	returnSession.ID = session.ID
 	returnSession.Command = session.Command
 	returnSession.PID = session.PID
+	returnSession.State = session.State
+	returnSession.ExitCode = session.ExitCode
+	if session.Config != nil {
+		returnSession.Config = new(ExecConfig)
+		deepcopier.Copy(session.Config).To(returnSession.Config)
+	}
 
 	return returnSession, nil
 }
@@ -2,19 +2,17 @@ package libpod
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/containers/libpod/libpod/driver"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/inspect"
-	"github.com/containers/libpod/pkg/lookup"
-	"github.com/containers/storage/pkg/stringid"
-	"github.com/docker/docker/daemon/caps"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -61,11 +59,11 @@ func (c *Container) Init(ctx context.Context) (err error) {
 
 	if c.state.State == ContainerStateStopped {
 		// Reinitialize the container
-		return c.reinit(ctx)
+		return c.reinit(ctx, false)
 	}
 
 	// Initialize the container for the first time
-	return c.init(ctx)
+	return c.init(ctx, false)
 }
 
 // Start starts a container
@@ -115,13 +113,13 @@ func (c *Container) Start(ctx context.Context) (err error) {
 
 	if c.state.State == ContainerStateStopped {
 		// Reinitialize the container if we need to
-		if err := c.reinit(ctx); err != nil {
+		if err := c.reinit(ctx, false); err != nil {
 			return err
 		}
 	} else if c.state.State == ContainerStateConfigured ||
 		c.state.State == ContainerStateExited {
 		// Or initialize it if necessary
-		if err := c.init(ctx); err != nil {
+		if err := c.init(ctx, false); err != nil {
 			return err
 		}
 	}
@@ -181,13 +179,13 @@ func (c *Container) StartAndAttach(ctx context.Context, streams *AttachStreams,
 
 	if c.state.State == ContainerStateStopped {
 		// Reinitialize the container if we need to
-		if err := c.reinit(ctx); err != nil {
+		if err := c.reinit(ctx, false); err != nil {
 			return nil, err
 		}
 	} else if c.state.State == ContainerStateConfigured ||
 		c.state.State == ContainerStateExited {
 		// Or initialize it if necessary
-		if err := c.init(ctx); err != nil {
+		if err := c.init(ctx, false); err != nil {
 			return nil, err
 		}
 	}
@@ -257,161 +255,37 @@ func (c *Container) Kill(signal uint) error {
 		return errors.Wrapf(ErrCtrStateInvalid, "can only kill running containers")
 	}
 
-	return c.runtime.ociRuntime.killContainer(c, signal)
-}
-
-// Exec starts a new process inside the container
-// TODO allow specifying streams to attach to
-// TODO investigate allowing exec without attaching
-func (c *Container) Exec(tty, privileged bool, env, cmd []string, user, workDir string) error {
-	var capList []string
-
-	locked := false
-	if !c.batched {
-		locked = true
-
-		c.lock.Lock()
-		defer func() {
-			if locked {
-				c.lock.Unlock()
-			}
-		}()
-
-		if err := c.syncContainer(); err != nil {
-			return err
-		}
-	}
-
-	conState := c.state.State
-
-	// TODO can probably relax this once we track exec sessions
-	if conState != ContainerStateRunning {
-		return errors.Errorf("cannot exec into container that is not running")
-	}
-	if privileged || c.config.Privileged {
-		capList = caps.GetAllCapabilities()
-	}
-
-	// If user was set, look it up in the container to get a UID to use on
-	// the host
-	hostUser := ""
-	if user != "" {
-		execUser, err := lookup.GetUserGroupInfo(c.state.Mountpoint, user, nil)
-		if err != nil {
-			return err
-		}
-
-		// runc expects user formatted as uid:gid
-		hostUser = fmt.Sprintf("%d:%d", execUser.Uid, execUser.Gid)
-	}
-
-	// Generate exec session ID
-	// Ensure we don't conflict with an existing session ID
-	sessionID := stringid.GenerateNonCryptoID()
-	found := true
-	// This really ought to be a do-while, but Go doesn't have those...
-	for found {
-		found = false
-		for id := range c.state.ExecSessions {
-			if id == sessionID {
-				found = true
-				break
-			}
-		}
-		if found == true {
-			sessionID = stringid.GenerateNonCryptoID()
-		}
+	if err := c.runtime.ociRuntime.killContainer(c, signal); err != nil {
+		return err
 	}
+	c.newContainerEvent(events.Kill)
+	return nil
+}
 
-	logrus.Debugf("Creating new exec session in container %s with session id %s", c.ID(), sessionID)
-
-	execCmd, err := c.runtime.ociRuntime.execContainer(c, cmd, capList, env, tty, workDir, hostUser, sessionID)
+// Exec starts a new process inside the container, and waits for it to exit.
+// Its exec session is removed once it exited.
+// If the process exits with a non-zero exit code, the error of the runtime is
+// returned.
+func (c *Container) Exec(tty, privileged bool, env, cmd []string, user, workDir string, streams *AttachStreams) error {
+	config := &ExecConfig{
+		Command:    cmd,
+		Env:        env,
+		Terminal:   tty,
+		Privileged: privileged,
+		User:       user,
+		WorkDir:    workDir,
+	}
+	sessionID, err := c.ExecCreate(config)
 	if err != nil {
-		return errors.Wrapf(err, "error exec %s", c.ID())
+		return err
 	}
-	chWait := make(chan error)
-	go func() {
-		chWait <- execCmd.Wait()
-	}()
-	defer close(chWait)
-
-	pidFile := c.execPidPath(sessionID)
-	// 60 second seems a reasonable time to wait
-	// https://github.com/containers/libpod/issues/1495
-	// https://github.com/containers/libpod/issues/1816
-	const pidWaitTimeout = 60000
-
-	// Wait until the runtime makes the pidfile
-	exited, err := WaitForFile(pidFile, chWait, pidWaitTimeout*time.Millisecond)
-	if err != nil {
-		if exited {
-			// If the runtime exited, propagate the error we got from the process.
-			return err
+	defer func() {
+		if err := c.ExecRemove(sessionID); err != nil {
+			logrus.Errorf("Error removing exec session %s from container %s state: %v", sessionID, c.ID(), err)
 		}
-		return errors.Wrapf(err, "timed out waiting for runtime to create pidfile for exec session in container %s", c.ID())
-	}
-
-	// Pidfile exists, read it
-	contents, err := ioutil.ReadFile(pidFile)
-	if err != nil {
-		// We don't know the PID of the exec session
-		// However, it may still be alive
-		// TODO handle this better
-		return errors.Wrapf(err, "could not read pidfile for exec session %s in container %s", sessionID, c.ID())
-	}
-	pid, err := strconv.ParseInt(string(contents), 10, 32)
-	if err != nil {
-		// As above, we don't have a valid PID, but the exec session is likely still alive
-		// TODO handle this better
-		return errors.Wrapf(err, "error parsing PID of exec session %s in container %s", sessionID, c.ID())
-	}
-
-	// We have the PID, add it to state
-	if c.state.ExecSessions == nil {
-		c.state.ExecSessions = make(map[string]*ExecSession)
-	}
-	session := new(ExecSession)
-	session.ID = sessionID
-	session.Command = cmd
-	session.PID = int(pid)
-	c.state.ExecSessions[sessionID] = session
-	if err := c.save(); err != nil {
-		// Now we have a PID but we can't save it in the DB
-		// TODO handle this better
-		return errors.Wrapf(err, "error saving exec sessions %s for container %s", sessionID, c.ID())
-	}
-
-	logrus.Debugf("Successfully started exec session %s in container %s", sessionID, c.ID())
-
-	// Unlock so other processes can use the container
-	if !c.batched {
-		c.lock.Unlock()
-		locked = false
-	}
-
-	var waitErr error
-	if !exited {
-		waitErr = <-chWait
-	}
-
-	// Lock again
-	if !c.batched {
-		locked = true
-		c.lock.Lock()
-	}
-
-	// Sync the container again to pick up changes in state
-	if err := c.syncContainer(); err != nil {
-		return errors.Wrapf(err, "error syncing container %s state to remove exec session %s", c.ID(), sessionID)
-	}
-
-	// Remove the exec session from state
-	delete(c.state.ExecSessions, sessionID)
-	if err := c.save(); err != nil {
-		logrus.Errorf("Error removing exec session %s from container %s state: %v", sessionID, c.ID(), err)
-	}
+	}()
 
-	return waitErr
+	return c.execStart(sessionID, streams, nil)
 }
 
 // AttachStreams contains streams that will be attached to the container
@@ -450,9 +324,32 @@ func (c *Container) Attach(streams *AttachStreams, keys string, resize <-chan re
 		return errors.Wrapf(ErrCtrStateInvalid, "can only attach to created or running containers")
 	}
 
+	c.newContainerEvent(events.Attach)
 	return c.attach(streams, keys, resize, false)
 }
 
+// AttachResize resizes the terminal of a running container, as done for the
+// resize events received while attached to it
+func (c *Container) AttachResize(newSize remotecommand.TerminalSize) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if c.state.State != ContainerStateRunning {
+		return errors.Wrapf(ErrCtrStateInvalid, "can only resize the terminal of running containers")
+	}
+	if c.config.Spec.Process == nil || !c.config.Spec.Process.Terminal {
+		return errors.Wrapf(ErrInvalidArg, "container %s does not have a terminal", c.ID())
+	}
+
+	return c.resizeTerminal(newSize)
+}
+
 // Mount mounts a container's filesystem on the host
 // The path where the container has been mounted is returned
 func (c *Container) Mount() (string, error) {
@@ -465,7 +362,12 @@ func (c *Container) Mount() (string, error) {
 		}
 	}
 
-	return c.mount()
+	mountPoint, err := c.mount()
+	if err != nil {
+		return "", err
+	}
+	c.newContainerEvent(events.Mount)
+	return mountPoint, nil
 }
 
 // Unmount unmounts a container's filesystem on the host
@@ -488,13 +390,17 @@ func (c *Container) Unmount(force bool) error {
 			if c.state.State == ContainerStateRunning || c.state.State == ContainerStatePaused {
 				return errors.Wrapf(ErrCtrStateInvalid, "cannot unmount storage for container %s as it is running or paused", c.ID())
 			}
-			if len(c.state.ExecSessions) != 0 {
+			if len(c.activeExecSessions()) != 0 {
 				return errors.Wrapf(ErrCtrStateInvalid, "container %s has active exec sessions, refusing to unmount", c.ID())
 			}
 			return errors.Wrapf(ErrInternal, "can't unmount %s last mount, it is still in use", c.ID())
 		}
 	}
-	return c.unmount(force)
+	if err := c.unmount(force); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Unmount)
+	return nil
 }
 
 // Pause pauses a container
@@ -548,7 +454,105 @@ func (c *Container) Export(path string) error {
 		}
 	}
 
-	return c.export(path)
+	if err := c.export(path); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Export)
+	return nil
+}
+
+// Rename changes the name of the container
+// The new name must not be in use by another container or pod
+func (c *Container) Rename(newName string) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if !nameRegex.MatchString(newName) {
+		return errors.Wrapf(ErrInvalidArg, "name must match regex [a-zA-Z0-9_-]+")
+	}
+	if newName == c.Name() {
+		return nil
+	}
+
+	if err := c.rename(newName); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Rename)
+	return nil
+}
+
+// Update changes the resource limits of the container
+// Only the limits set in resources are changed. If the container is running,
+// the new limits are applied immediately. They are saved in the container's
+// configuration so they persist when the container is restarted.
+func (c *Container) Update(resources *spec.LinuxResources) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if resources == nil {
+		return errors.Wrapf(ErrInvalidArg, "must provide resource limits to update container %s", c.ID())
+	}
+	if err := c.update(resources); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Update)
+	return nil
+}
+
+// ConnectNetwork connects the container to a CNI network
+// If the network namespace of the container is set up, the container is
+// added to the network immediately. The network is saved in the container's
+// configuration so the container stays connected when it is restarted. A
+// static IP can be requested from the network for a container whose network
+// namespace is set up.
+func (c *Container) ConnectNetwork(name string, ip net.IP) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.connectNetwork(name, ip); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Connect)
+	return nil
+}
+
+// DisconnectNetwork disconnects the container from a CNI network
+// If the network namespace of the container is set up, the interface of the
+// network is removed immediately. Containers cannot be disconnected from
+// their only network.
+func (c *Container) DisconnectNetwork(name string) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.disconnectNetwork(name); err != nil {
+		return err
+	}
+	c.newContainerEvent(events.Disconnect)
+	return nil
 }
 
 // AddArtifact creates and writes to an artifact file for the container
@@ -653,10 +657,20 @@ func (c *Container) Cleanup(ctx context.Context) error {
 	}
 
 	// Check if we have active exec sessions
-	if len(c.state.ExecSessions) != 0 {
+	if len(c.activeExecSessions()) != 0 {
 		return errors.Wrapf(ErrCtrStateInvalid, "container %s has active exec sessions, refusing to clean up", c.ID())
 	}
 
+	// If the container's restart policy requires it, restart the container
+	// instead of cleaning it up
+	restarted, err := c.handleRestartPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	if restarted {
+		return nil
+	}
+
 	return c.cleanup(ctx)
 }
 
@@ -792,9 +806,9 @@ func (c *Container) Refresh(ctx context.Context) error {
 	}
 
 	// If there are active exec sessions, we need to kill them
-	if len(c.state.ExecSessions) > 0 {
+	if activeSessions := c.activeExecSessions(); len(activeSessions) > 0 {
 		logrus.Infof("Killing %d exec sessions in container %s. They will not be restored after refresh.",
-			len(c.state.ExecSessions), c.ID())
+			len(activeSessions), c.ID())
 		if err := c.runtime.ociRuntime.execStopContainer(c, c.config.StopTimeout); err != nil {
 			return err
 		}
@@ -835,7 +849,7 @@ func (c *Container) Refresh(ctx context.Context) error {
 		if err := c.prepare(); err != nil {
 			return err
 		}
-		if err := c.init(ctx); err != nil {
+		if err := c.init(ctx, false); err != nil {
 			return err
 		}
 	}
@@ -868,6 +882,14 @@ type ContainerCheckpointOptions struct {
 	// TCPEstablished tells the API to checkpoint a container
 	// even if it contains established TCP connections
 	TCPEstablished bool
+	// TargetFile tells the API to export the checkpoint to the given
+	// archive when checkpointing, and to import it from there when
+	// restoring
+	TargetFile string
+	// IgnoreStaticIP tells the API to restore a container with the
+	// addresses its networks allocate, instead of the IP address it had
+	// when it was checkpointed
+	IgnoreStaticIP bool
 }
 
 // Checkpoint checkpoints a container
@@ -7,7 +7,6 @@ import (
 	"io"
 	"net"
 	"os"
-	"path/filepath"
 
 	"github.com/containers/libpod/pkg/kubeutils"
 	"github.com/containers/libpod/utils"
@@ -55,17 +54,8 @@ func (c *Container) attach(streams *AttachStreams, keys string, resize <-chan re
 // TODO add a channel to allow interrupting
 func (c *Container) attachContainerSocket(resize <-chan remotecommand.TerminalSize, detachKeys []byte, streams *AttachStreams, startContainer bool) error {
 	kubeutils.HandleResizing(resize, func(size remotecommand.TerminalSize) {
-		controlPath := filepath.Join(c.bundlePath(), "ctl")
-		controlFile, err := os.OpenFile(controlPath, unix.O_WRONLY, 0)
-		if err != nil {
-			logrus.Debugf("Could not open ctl file: %v", err)
-			return
-		}
-		defer controlFile.Close()
-
-		logrus.Debugf("Received a resize event: %+v", size)
-		if _, err = fmt.Fprintf(controlFile, "%d %d %d\n", 1, size.Height, size.Width); err != nil {
-			logrus.Warnf("Failed to write to control file to resize terminal: %v", err)
+		if err := c.resizeTerminal(size); err != nil {
+			logrus.Debugf("%v", err)
 		}
 	})
 
@@ -119,6 +109,21 @@ func (c *Container) attachContainerSocket(resize <-chan remotecommand.TerminalSi
 	return nil
 }
 
+// resizeTerminal tells conmon to resize the terminal of the container
+func (c *Container) resizeTerminal(size remotecommand.TerminalSize) error {
+	controlFile, err := os.OpenFile(c.ControlSocketPath(), unix.O_WRONLY, 0)
+	if err != nil {
+		return errors.Wrapf(err, "could not open ctl file of container %s", c.ID())
+	}
+	defer controlFile.Close()
+
+	logrus.Debugf("Received a resize event: %+v", size)
+	if _, err = fmt.Fprintf(controlFile, "%d %d %d\n", 1, size.Height, size.Width); err != nil {
+		return errors.Wrapf(err, "failed to write to control file to resize terminal")
+	}
+	return nil
+}
+
 func redirectResponseToOutputStreams(outputStream, errorStream io.Writer, writeOutput, writeError bool, conn io.Reader) error {
 	var err error
 	buf := make([]byte, 8192+1) /* Sync with conmon STDIO_BUF_SIZE */
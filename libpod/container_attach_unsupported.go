@@ -9,3 +9,7 @@ import (
 func (c *Container) attach(streams *AttachStreams, keys string, resize <-chan remotecommand.TerminalSize, startContainer bool) error {
 	return ErrNotImplemented
 }
+
+func (c *Container) resizeTerminal(size remotecommand.TerminalSize) error {
+	return ErrNotImplemented
+}
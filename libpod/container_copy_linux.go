@@ -0,0 +1,301 @@
+// +build linux
+
+package libpod
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/libpod/pkg/lookup"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/containers/storage/pkg/chrootarchive"
+	"github.com/containers/storage/pkg/idtools"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// CopyFromHost copies the file or directory at path src on the host to path
+// dest in the container. The copied files are owned by the user the container
+// runs as.
+// If dest is an existing directory, src is copied into it, unless src ends
+// with "/." in which case the contents of the directory src are copied.
+// If pause is true and the container is running, it is paused while the
+// files are copied.
+func (c *Container) CopyFromHost(src, dest string, pause bool) error {
+	mountPoint, cleanup, err := c.prepareCopy(pause)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	destPath, err := c.resolveCopyPath(mountPoint, dest)
+	if err != nil {
+		return err
+	}
+	owner, err := c.copyOwner(mountPoint)
+	if err != nil {
+		return err
+	}
+	idMappings, err := c.copyIDMappings()
+	if err != nil {
+		return err
+	}
+
+	archiver := chrootarchive.NewArchiverWithChown(nil, &owner, idMappings)
+	return copyWithArchiver(archiver, src, destPath, strings.HasSuffix(dest, "/"))
+}
+
+// CopyToHost copies the file or directory at path src in the container to
+// path dest on the host. The copied files are owned by the calling user.
+// Directories are handled as in CopyFromHost.
+func (c *Container) CopyToHost(src, dest string, pause bool) error {
+	mountPoint, cleanup, err := c.prepareCopy(pause)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	srcPath, err := c.resolveCopyPath(mountPoint, src)
+	if err != nil {
+		return err
+	}
+	// Keep a trailing "/." so the contents of the directory are copied
+	if strings.HasSuffix(src, "/.") {
+		srcPath += "/."
+	}
+
+	owner := idtools.IDPair{UID: os.Getuid(), GID: os.Getgid()}
+	archiver := chrootarchive.NewArchiverWithChown(nil, &owner, nil)
+	return copyWithArchiver(archiver, srcPath, dest, strings.HasSuffix(dest, "/"))
+}
+
+// CopyFromArchive extracts the tar archive read from reader into the
+// directory dest in the container. The extracted files are owned by the user
+// the container runs as.
+func (c *Container) CopyFromArchive(reader io.Reader, dest string, pause bool) error {
+	mountPoint, cleanup, err := c.prepareCopy(pause)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	destPath, err := c.resolveCopyPath(mountPoint, dest)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(destPath)
+	if err != nil {
+		return errors.Wrapf(err, "error checking destination %q in container %s", dest, c.ID())
+	}
+	if !info.IsDir() {
+		return errors.Wrapf(ErrInvalidArg, "destination %q in container %s must be a directory to extract an archive into it", dest, c.ID())
+	}
+	owner, err := c.copyOwner(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	options := &archive.TarOptions{
+		ChownOpts: &owner,
+		NoLchown:  false,
+	}
+	if err := chrootarchive.Untar(reader, destPath, options); err != nil {
+		return errors.Wrapf(err, "error extracting archive into %q in container %s", dest, c.ID())
+	}
+	return nil
+}
+
+// CopyToArchive writes an uncompressed tar archive of the file or directory
+// at path src in the container to writer. The ownership of the files in the
+// archive is given as seen from within the container.
+func (c *Container) CopyToArchive(src string, writer io.Writer, pause bool) error {
+	mountPoint, cleanup, err := c.prepareCopy(pause)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	srcPath, err := c.resolveCopyPath(mountPoint, src)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(srcPath); err != nil {
+		return errors.Wrapf(err, "error checking source %q in container %s", src, c.ID())
+	}
+	idMappings, err := c.copyIDMappings()
+	if err != nil {
+		return err
+	}
+
+	options := &archive.TarOptions{
+		Compression:  archive.Uncompressed,
+		IncludeFiles: []string{filepath.Base(srcPath)},
+		UIDMaps:      idMappings.UIDs(),
+		GIDMaps:      idMappings.GIDs(),
+	}
+	if strings.HasSuffix(src, "/.") {
+		// Archive the contents of the directory only
+		options.IncludeFiles = []string{"."}
+	} else {
+		srcPath = filepath.Dir(srcPath)
+	}
+	tarball, err := archive.TarWithOptions(srcPath, options)
+	if err != nil {
+		return errors.Wrapf(err, "error creating archive of %q in container %s", src, c.ID())
+	}
+	defer tarball.Close()
+
+	_, err = io.Copy(writer, tarball)
+	return err
+}
+
+// prepareCopy mounts the storage of the container and pauses the container
+// if requested and it is running. The returned function unmounts and unpauses
+// the container and must be called once the copy is done.
+func (c *Container) prepareCopy(pause bool) (string, func(), error) {
+	paused := false
+	if pause {
+		state, err := c.State()
+		if err != nil {
+			return "", nil, err
+		}
+		if state == ContainerStateRunning {
+			if err := c.Pause(); err != nil {
+				return "", nil, errors.Wrapf(err, "error pausing container %s for copy", c.ID())
+			}
+			paused = true
+		}
+	}
+	unpause := func() {
+		if !paused {
+			return
+		}
+		if err := c.Unpause(); err != nil {
+			logrus.Errorf("unable to unpause container %s after copy: %v", c.ID(), err)
+		}
+	}
+
+	mountPoint, err := c.Mount()
+	if err != nil {
+		unpause()
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		if err := c.Unmount(false); err != nil {
+			logrus.Errorf("unable to unmount container %s after copy: %v", c.ID(), err)
+		}
+		unpause()
+	}
+	return mountPoint, cleanup, nil
+}
+
+// resolveCopyPath returns the path on the host of the path ctrPath in the
+// container. Relative paths are taken relative to the working directory of
+// the container. Paths that are in a volume or bind mount of the container
+// resolve to the source of the mount, all other paths resolve to the root
+// filesystem of the container mounted at mountPoint.
+func (c *Container) resolveCopyPath(mountPoint, ctrPath string) (string, error) {
+	if !filepath.IsAbs(ctrPath) {
+		workDir := c.WorkingDir()
+		if workDir == "" {
+			workDir = "/"
+		}
+		ctrPath = filepath.Join(workDir, ctrPath)
+	}
+	ctrPath = filepath.Clean(ctrPath)
+
+	// Use the most specific mount that contains the path
+	mountSource, mountDest := "", ""
+	for _, m := range c.config.Spec.Mounts {
+		if m.Type != "bind" {
+			continue
+		}
+		dest := filepath.Clean(m.Destination)
+		if ctrPath != dest && !strings.HasPrefix(ctrPath, dest+"/") {
+			continue
+		}
+		if len(dest) > len(mountDest) {
+			mountSource, mountDest = m.Source, dest
+		}
+	}
+	if mountDest != "" {
+		rel, err := filepath.Rel(mountDest, ctrPath)
+		if err != nil {
+			return "", errors.Wrapf(err, "error resolving %q in mount %q of container %s", ctrPath, mountDest, c.ID())
+		}
+		return securejoin.SecureJoin(mountSource, rel)
+	}
+
+	return securejoin.SecureJoin(mountPoint, ctrPath)
+}
+
+// copyOwner returns the IDs on the host of the user the container runs as
+func (c *Container) copyOwner(mountPoint string) (idtools.IDPair, error) {
+	execUser, err := lookup.GetUserGroupInfo(mountPoint, c.User(), nil)
+	if err != nil {
+		return idtools.IDPair{}, errors.Wrapf(err, "error looking up user %q of container %s", c.User(), c.ID())
+	}
+	owner := idtools.IDPair{UID: execUser.Uid, GID: execUser.Gid}
+
+	idMappings, err := c.copyIDMappings()
+	if err != nil {
+		return idtools.IDPair{}, err
+	}
+	if idMappings.Empty() {
+		return owner, nil
+	}
+	hostOwner, err := idMappings.ToHost(owner)
+	if err != nil {
+		return idtools.IDPair{}, errors.Wrapf(err, "error mapping user %q of container %s to the host", c.User(), c.ID())
+	}
+	return hostOwner, nil
+}
+
+// copyIDMappings returns the user namespace mappings of the container
+func (c *Container) copyIDMappings() (*idtools.IDMappings, error) {
+	idMappingOpts, err := c.IDMappings()
+	if err != nil {
+		return nil, err
+	}
+	return idtools.NewIDMappingsFromMaps(idMappingOpts.UIDMap, idMappingOpts.GIDMap), nil
+}
+
+// copyWithArchiver copies the file or directory src to dest with the given
+// archiver. If dest is an existing directory, or destIsDir is set, src is
+// copied into it.
+func copyWithArchiver(archiver *archive.Archiver, src, dest string, destIsDir bool) error {
+	copyContents := strings.HasSuffix(src, "/.")
+	src = filepath.Clean(src)
+
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return errors.Wrapf(err, "error checking source %q", src)
+	}
+	destInfo, err := os.Stat(dest)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error checking destination %q", dest)
+	}
+	destExists := err == nil
+
+	if !srcInfo.IsDir() {
+		if copyContents {
+			return errors.Errorf("source %q is not a directory", src)
+		}
+		if (destExists && destInfo.IsDir()) || destIsDir {
+			dest = filepath.Join(dest, filepath.Base(src))
+		}
+		return archiver.CopyFileWithTar(src, dest)
+	}
+
+	if destExists && !destInfo.IsDir() {
+		return errors.Errorf("cannot copy directory %q to file %q", src, dest)
+	}
+	if destExists && !copyContents {
+		dest = filepath.Join(dest, filepath.Base(src))
+	}
+	return archiver.CopyWithTar(src, dest)
+}
@@ -0,0 +1,747 @@
+package libpod
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/libpod/libpod/events"
+	"github.com/containers/libpod/pkg/kubeutils"
+	"github.com/containers/libpod/pkg/lookup"
+	"github.com/containers/storage/pkg/stringid"
+	"github.com/docker/docker/daemon/caps"
+	"github.com/opencontainers/runc/libcontainer/user"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/ulule/deepcopier"
+	"golang.org/x/sys/unix"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// execPidWaitTimeout is the time to wait for the runtime to create the
+	// pidfile of an exec session. 60 seconds seems a reasonable time to
+	// wait.
+	// https://github.com/containers/libpod/issues/1495
+	// https://github.com/containers/libpod/issues/1816
+	execPidWaitTimeout = 60 * time.Second
+	// execSessionStartTimeout is the time after which exec sessions that
+	// were created but not started are removed
+	execSessionStartTimeout = 5 * time.Minute
+)
+
+// execProcess is the runtime process of a started exec session
+type execProcess struct {
+	sessionID string
+	cmd       *exec.Cmd
+	// wait receives the result of waiting for cmd
+	wait chan error
+	// terminal is the master end of the terminal of the session, if the
+	// session has a terminal allocated by us
+	terminal *os.File
+	// outputDone is closed once the output of terminal was copied
+	outputDone chan struct{}
+}
+
+// ExecCreate creates a new exec session in a running container. The session
+// is not started. It returns the ID of the new session.
+func (c *Container) ExecCreate(config *ExecConfig) (string, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return "", err
+		}
+	}
+
+	if config == nil {
+		return "", errors.Wrapf(ErrInvalidArg, "must provide a configuration for the exec session")
+	}
+	if len(config.Command) == 0 {
+		return "", errors.Wrapf(ErrInvalidArg, "must provide a command to execute")
+	}
+	if c.state.State != ContainerStateRunning {
+		return "", errors.Wrapf(ErrCtrStateInvalid, "cannot exec into container %s that is not running", c.ID())
+	}
+
+	// Generate exec session ID
+	// Ensure we don't conflict with an existing session ID
+	sessionID := stringid.GenerateNonCryptoID()
+	for {
+		if _, ok := c.state.ExecSessions[sessionID]; !ok {
+			break
+		}
+		sessionID = stringid.GenerateNonCryptoID()
+	}
+
+	session := new(ExecSession)
+	session.ID = sessionID
+	session.Command = config.Command
+	session.State = ExecStateCreated
+	session.CreatedTime = time.Now()
+	session.Config = new(ExecConfig)
+	deepcopier.Copy(config).To(session.Config)
+
+	if c.state.ExecSessions == nil {
+		c.state.ExecSessions = make(map[string]*ExecSession)
+	}
+	c.state.ExecSessions[sessionID] = session
+	if err := c.save(); err != nil {
+		return "", errors.Wrapf(err, "error saving exec session %s for container %s", sessionID, c.ID())
+	}
+
+	logrus.Debugf("Created exec session %s in container %s", sessionID, c.ID())
+
+	return sessionID, nil
+}
+
+// ExecStart starts a created exec session, attaching to the given streams, and
+// waits for its command to exit. If streams is nil, the command is attached
+// to the standard streams of the current process. Terminal resize events are
+// only handled for sessions with a terminal when streams are given.
+// The exit code of the command is recorded in the exec session, and no error
+// is returned if the command exits with a non-zero exit code.
+func (c *Container) ExecStart(sessionID string, streams *AttachStreams, resize <-chan remotecommand.TerminalSize) error {
+	err := c.execStart(sessionID, streams, resize)
+	if _, ok := errors.Cause(err).(*exec.ExitError); ok {
+		return nil
+	}
+	return err
+}
+
+// ExecStartDetached starts a created exec session without attaching to it. Its
+// command is run through conmon, which records its exit code once it exits,
+// even if the current process exited before. The exit code is picked up in the
+// exec session whenever the container is synced.
+func (c *Container) ExecStartDetached(sessionID string) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	return c.execLaunchDetached(sessionID)
+}
+
+// ExecResize resizes the terminal of a running exec session. Only terminals of
+// exec sessions started by the current process can be resized.
+func (c *Container) ExecResize(sessionID string, size remotecommand.TerminalSize) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	session, ok := c.state.ExecSessions[sessionID]
+	if !ok {
+		return errors.Wrapf(ErrNoSuchExecSession, "no exec session with ID %s found in container %s", sessionID, c.ID())
+	}
+	if session.State != ExecStateRunning {
+		return errors.Wrapf(ErrExecSessionStateInvalid, "can only resize the terminal of running exec sessions, exec session %s is %s", sessionID, session.State.String())
+	}
+	if session.Config == nil || !session.Config.Terminal {
+		return errors.Wrapf(ErrInvalidArg, "exec session %s does not have a terminal", sessionID)
+	}
+
+	process := c.runtime.getExecProcess(sessionID)
+	if process == nil || process.terminal == nil {
+		return errors.Wrapf(ErrInvalidArg, "the terminal of exec session %s can only be resized by the process that started it", sessionID)
+	}
+
+	return resizeExecTerminal(process, size)
+}
+
+// ExecRemove removes an exec session from the container. Exec sessions whose
+// command is still running cannot be removed.
+func (c *Container) ExecRemove(sessionID string) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	session, ok := c.state.ExecSessions[sessionID]
+	if !ok {
+		return errors.Wrapf(ErrNoSuchExecSession, "no exec session with ID %s found in container %s", sessionID, c.ID())
+	}
+	if execSessionRunning(session) {
+		return errors.Wrapf(ErrExecSessionStateInvalid, "cannot remove exec session %s as it is running", sessionID)
+	}
+
+	delete(c.state.ExecSessions, sessionID)
+	if err := c.save(); err != nil {
+		return errors.Wrapf(err, "error removing exec session %s from container %s state", sessionID, c.ID())
+	}
+	c.removeExecFiles(sessionID)
+
+	return nil
+}
+
+// execKill sends a signal to the command of a running exec session
+func (c *Container) execKill(sessionID string, signal syscall.Signal) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	session, ok := c.state.ExecSessions[sessionID]
+	if !ok {
+		return errors.Wrapf(ErrNoSuchExecSession, "no exec session with ID %s found in container %s", sessionID, c.ID())
+	}
+	if session.State != ExecStateRunning || session.PID <= 0 {
+		return errors.Wrapf(ErrExecSessionStateInvalid, "can only signal running exec sessions, exec session %s is %s", sessionID, session.State.String())
+	}
+	if err := unix.Kill(session.PID, signal); err != nil {
+		return errors.Wrapf(err, "error sending signal %d to exec session %s in container %s", signal, sessionID, c.ID())
+	}
+	return nil
+}
+
+// execStart starts a created exec session and waits for it to exit. Unlike
+// ExecStart, it returns the error of the runtime if the command exited with a
+// non-zero exit code.
+func (c *Container) execStart(sessionID string, streams *AttachStreams, resize <-chan remotecommand.TerminalSize) error {
+	if !c.batched {
+		c.lock.Lock()
+
+		if err := c.syncContainer(); err != nil {
+			c.lock.Unlock()
+			return err
+		}
+	}
+
+	process, err := c.execLaunch(sessionID, streams)
+
+	// Unlock so other processes can use the container
+	if !c.batched {
+		c.lock.Unlock()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	kubeutils.HandleResizing(resize, func(size remotecommand.TerminalSize) {
+		if process.terminal == nil {
+			return
+		}
+		if err := resizeExecTerminal(process, size); err != nil {
+			logrus.Debugf("Failed to resize terminal of exec session %s: %v", sessionID, err)
+		}
+	})
+
+	return c.execWait(process)
+}
+
+// execPrepare returns a created exec session that is about to be started,
+// along with the capabilities and the user of its command. The user is nil if
+// no user was set for the session.
+// The container must be locked.
+func (c *Container) execPrepare(sessionID string) (*ExecSession, []string, *user.ExecUser, error) {
+	session, ok := c.state.ExecSessions[sessionID]
+	if !ok {
+		return nil, nil, nil, errors.Wrapf(ErrNoSuchExecSession, "no exec session with ID %s found in container %s", sessionID, c.ID())
+	}
+	if session.State != ExecStateCreated {
+		return nil, nil, nil, errors.Wrapf(ErrExecSessionStateInvalid, "can only start created exec sessions, exec session %s is %s", sessionID, session.State.String())
+	}
+	if c.state.State != ContainerStateRunning {
+		return nil, nil, nil, errors.Wrapf(ErrCtrStateInvalid, "cannot exec into container %s that is not running", c.ID())
+	}
+	config := session.Config
+
+	var capList []string
+	if config.Privileged || c.config.Privileged {
+		capList = caps.GetAllCapabilities()
+	}
+
+	// If user was set, look it up in the container to get a UID to use on
+	// the host
+	var execUser *user.ExecUser
+	if config.User != "" {
+		var err error
+		execUser, err = lookup.GetUserGroupInfo(c.state.Mountpoint, config.User, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return session, capList, execUser, nil
+}
+
+// execLaunch starts the runtime for a created exec session, and records the PID
+// of its command in the session.
+// The container must be locked.
+func (c *Container) execLaunch(sessionID string, streams *AttachStreams) (*execProcess, error) {
+	session, capList, execUser, err := c.execPrepare(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	config := session.Config
+
+	hostUser := ""
+	if execUser != nil {
+		// runc expects user formatted as uid:gid
+		hostUser = fmt.Sprintf("%d:%d", execUser.Uid, execUser.Gid)
+	}
+
+	process := &execProcess{sessionID: sessionID}
+	runtimeStreams := streams
+	var closeAfterStart []io.Closer
+	if streams != nil {
+		var err error
+		runtimeStreams, closeAfterStart, err = process.setupStreams(config.Terminal, streams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logrus.Debugf("Starting exec session %s in container %s", sessionID, c.ID())
+
+	execCmd, err := c.runtime.ociRuntime.execContainer(c, config.Command, capList, config.Env, config.Terminal, config.WorkDir, hostUser, sessionID, runtimeStreams)
+	for _, closer := range closeAfterStart {
+		closer.Close()
+	}
+	if err != nil {
+		if process.terminal != nil {
+			process.terminal.Close()
+		}
+		return nil, errors.Wrapf(err, "error exec %s", c.ID())
+	}
+	process.cmd = execCmd
+	process.wait = make(chan error, 1)
+	go func() {
+		process.wait <- execCmd.Wait()
+	}()
+
+	pidFile := c.execPidPath(sessionID)
+
+	// Wait until the runtime makes the pidfile
+	exited, err := WaitForFile(pidFile, process.wait, execPidWaitTimeout)
+	if err != nil {
+		if exited {
+			// If the runtime exited, propagate the error we got from
+			// the process, and record it in the session
+			if saveErr := c.execSaveExited(process, err); saveErr != nil {
+				logrus.Errorf("%v", saveErr)
+			}
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "timed out waiting for runtime to create pidfile for exec session in container %s", c.ID())
+	}
+
+	// Pidfile exists, read it
+	pid, err := c.readExecPidFile(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// We have the PID, add it to state
+	session.PID = pid
+	session.State = ExecStateRunning
+	if err := c.save(); err != nil {
+		// Now we have a PID but we can't save it in the DB
+		// TODO handle this better
+		return nil, errors.Wrapf(err, "error saving exec sessions %s for container %s", sessionID, c.ID())
+	}
+
+	c.runtime.addExecProcess(process)
+
+	logrus.Debugf("Successfully started exec session %s in container %s", sessionID, c.ID())
+	c.newContainerEvent(events.Exec)
+
+	return process, nil
+}
+
+// execLaunchDetached starts the command of a created exec session through
+// conmon, and records the PID of the command in the session.
+// The container must be locked.
+func (c *Container) execLaunchDetached(sessionID string) error {
+	session, capList, execUser, err := c.execPrepare(sessionID)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debugf("Starting detached exec session %s in container %s", sessionID, c.ID())
+
+	process := c.execProcessSpec(session.Config, capList, execUser)
+	if err := c.runtime.ociRuntime.execContainerDetached(c, sessionID, process); err != nil {
+		c.removeExecFiles(sessionID)
+		return errors.Wrapf(err, "error exec %s", c.ID())
+	}
+
+	// Wait until the runtime makes the pidfile. If the runtime fails, conmon
+	// writes the exit file of the session instead.
+	pidFile := c.execPidPath(sessionID)
+	exitFile := c.execExitFilePath(sessionID)
+	deadline := time.Now().Add(execPidWaitTimeout)
+	for {
+		if _, err := os.Stat(pidFile); err == nil {
+			break
+		}
+		if exitCode, err := readExecExitFile(exitFile); err == nil {
+			session.State = ExecStateStopped
+			session.ExitCode = exitCode
+			if err := c.save(); err != nil {
+				logrus.Errorf("Error saving exit code of exec session %s in container %s: %v", sessionID, c.ID(), err)
+			}
+			return errors.Wrapf(ErrInternal, "runtime failed to start exec session %s in container %s, exit code %d", sessionID, c.ID(), exitCode)
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(ErrInternal, "timed out waiting for runtime to create pidfile for exec session in container %s", c.ID())
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+
+	pid, err := c.readExecPidFile(sessionID)
+	if err != nil {
+		return err
+	}
+
+	session.PID = pid
+	session.State = ExecStateRunning
+	session.Detached = true
+	if err := c.save(); err != nil {
+		return errors.Wrapf(err, "error saving exec sessions %s for container %s", sessionID, c.ID())
+	}
+
+	logrus.Debugf("Successfully started detached exec session %s in container %s", sessionID, c.ID())
+	c.newContainerEvent(events.Exec)
+
+	return nil
+}
+
+// execProcessSpec returns the process spec conmon passes to the runtime to
+// run the command of an exec session, derived from the process of the
+// container
+func (c *Container) execProcessSpec(config *ExecConfig, capList []string, execUser *user.ExecUser) *spec.Process {
+	process := new(spec.Process)
+	if c.config.Spec.Process != nil {
+		*process = *c.config.Spec.Process
+	}
+	process.Args = config.Command
+	process.Env = append(append([]string{}, process.Env...), config.Env...)
+	process.Terminal = config.Terminal
+	process.ConsoleSize = nil
+	if config.WorkDir != "" {
+		process.Cwd = config.WorkDir
+	}
+	if execUser != nil {
+		process.User = spec.User{
+			UID: uint32(execUser.Uid),
+			GID: uint32(execUser.Gid),
+		}
+	}
+	if len(capList) > 0 {
+		process.Capabilities = &spec.LinuxCapabilities{
+			Bounding:    capList,
+			Effective:   capList,
+			Inheritable: capList,
+			Permitted:   capList,
+			Ambient:     capList,
+		}
+	}
+	return process
+}
+
+// readExecPidFile reads the PID of the command of an exec session from the
+// pidfile the runtime wrote
+func (c *Container) readExecPidFile(sessionID string) (int, error) {
+	contents, err := ioutil.ReadFile(c.execPidPath(sessionID))
+	if err != nil {
+		// We don't know the PID of the exec session
+		// However, it may still be alive
+		// TODO handle this better
+		return 0, errors.Wrapf(err, "could not read pidfile for exec session %s in container %s", sessionID, c.ID())
+	}
+	pid, err := strconv.ParseInt(strings.TrimSpace(string(contents)), 10, 32)
+	if err != nil {
+		// As above, we don't have a valid PID, but the exec session is likely still alive
+		// TODO handle this better
+		return 0, errors.Wrapf(err, "error parsing PID of exec session %s in container %s", sessionID, c.ID())
+	}
+	return int(pid), nil
+}
+
+// readExecExitFile reads the exit code conmon recorded in the exit file of a
+// detached exec session
+func readExecExitFile(path string) (int, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	exitCode, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return 0, errors.Wrapf(err, "error parsing exit file %s", path)
+	}
+	return exitCode, nil
+}
+
+// removeExecFiles removes the files of an exec session
+func (c *Container) removeExecFiles(sessionID string) {
+	if err := os.Remove(c.execPidPath(sessionID)); err != nil && !os.IsNotExist(err) {
+		logrus.Debugf("Error removing pidfile of exec session %s in container %s: %v", sessionID, c.ID(), err)
+	}
+	if err := os.RemoveAll(c.execBundlePath(sessionID)); err != nil {
+		logrus.Debugf("Error removing directory of exec session %s in container %s: %v", sessionID, c.ID(), err)
+	}
+}
+
+// syncExecSessions records the exit of exec sessions whose command exited
+// without the process that started them recording it, and removes exec
+// sessions that were created but not started within
+// execSessionStartTimeout. It returns whether any session changed.
+// The container must be locked.
+func (c *Container) syncExecSessions() bool {
+	changed := false
+	for id, session := range c.state.ExecSessions {
+		switch session.State {
+		case ExecStateCreated:
+			if time.Since(session.CreatedTime) > execSessionStartTimeout {
+				logrus.Debugf("Removing exec session %s in container %s, it was never started", id, c.ID())
+				delete(c.state.ExecSessions, id)
+				c.removeExecFiles(id)
+				changed = true
+			}
+		case ExecStateRunning:
+			if session.Detached {
+				exitCode, err := readExecExitFile(c.execExitFilePath(id))
+				if err == nil {
+					session.State = ExecStateStopped
+					session.ExitCode = exitCode
+					changed = true
+				} else if os.IsNotExist(err) && !c.execConmonRunning(id) {
+					// conmon died without recording the exit
+					session.State = ExecStateStopped
+					session.ExitCode = -1
+					changed = true
+				}
+			} else if !execSessionRunning(session) && c.runtime.getExecProcess(id) == nil {
+				// The process that started the session exited before
+				// its command, so the exit code is lost. If the
+				// process is still waiting for the command, it records
+				// the exit code after this.
+				session.State = ExecStateStopped
+				session.ExitCode = -1
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// execConmonRunning returns whether the conmon process of a detached exec
+// session is still running
+func (c *Container) execConmonRunning(sessionID string) bool {
+	contents, err := ioutil.ReadFile(c.execConmonPidPath(sessionID))
+	if err != nil {
+		// conmon writes its PID as soon as it forked
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil || pid <= 0 {
+		return false
+	}
+	return unix.Kill(pid, 0) != unix.ESRCH
+}
+
+// execWait waits for the command of a started exec session to exit, and
+// records its exit code in the session. It returns the error the runtime
+// exited with.
+// The container must not be locked, unless it is batched.
+func (c *Container) execWait(process *execProcess) error {
+	waitErr := <-process.wait
+
+	c.runtime.removeExecProcess(process.sessionID)
+
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+	}
+
+	// Sync the container again to pick up changes in state
+	if err := c.syncContainer(); err != nil {
+		return errors.Wrapf(err, "error syncing container %s state to update exec session %s", c.ID(), process.sessionID)
+	}
+
+	if err := c.execSaveExited(process, waitErr); err != nil {
+		logrus.Errorf("%v", err)
+	}
+
+	return waitErr
+}
+
+// execSaveExited records that the runtime of an exec session exited with the
+// given error.
+// The container must be locked.
+func (c *Container) execSaveExited(process *execProcess, waitErr error) error {
+	if process.terminal != nil {
+		<-process.outputDone
+		process.terminal.Close()
+	}
+
+	session, ok := c.state.ExecSessions[process.sessionID]
+	if !ok {
+		// The session was removed while it was running
+		return nil
+	}
+	session.State = ExecStateStopped
+	session.ExitCode = execExitCode(waitErr)
+	if err := c.save(); err != nil {
+		return errors.Wrapf(err, "error saving exit code of exec session %s in container %s", process.sessionID, c.ID())
+	}
+
+	logrus.Debugf("Exec session %s in container %s exited with code %d", process.sessionID, c.ID(), session.ExitCode)
+
+	return nil
+}
+
+// setupStreams creates the streams the runtime of an exec session is attached
+// to. A command with a terminal is attached to the streams through a
+// pseudo-terminal. Input that is not a file is passed through a pipe, as the
+// runtime would otherwise not be considered exited before the input is closed.
+// The returned closers must be closed once the runtime started.
+func (p *execProcess) setupStreams(tty bool, streams *AttachStreams) (*AttachStreams, []io.Closer, error) {
+	if tty {
+		master, slave, err := openExecTerminal()
+		if err != nil {
+			return nil, nil, err
+		}
+		p.terminal = master
+		p.outputDone = make(chan struct{})
+
+		var output io.Writer = ioutil.Discard
+		if streams.AttachOutput {
+			output = streams.OutputStream
+		}
+		go func() {
+			// Reading from the master fails once the runtime exited
+			// and closed the slave
+			io.Copy(output, master)
+			close(p.outputDone)
+		}()
+		if streams.AttachInput {
+			go io.Copy(master, streams.InputStream)
+		}
+
+		runtimeStreams := &AttachStreams{
+			OutputStream: slave,
+			ErrorStream:  slave,
+			InputStream:  slave,
+			AttachOutput: true,
+			AttachError:  true,
+			AttachInput:  true,
+		}
+		return runtimeStreams, []io.Closer{slave}, nil
+	}
+
+	if !streams.AttachInput {
+		return streams, nil, nil
+	}
+	if _, ok := streams.InputStream.(*os.File); ok {
+		return streams, nil, nil
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error creating input pipe for exec session %s", p.sessionID)
+	}
+	go func() {
+		io.Copy(writer, streams.InputStream)
+		writer.Close()
+	}()
+	runtimeStreams := *streams
+	runtimeStreams.InputStream = reader
+	return &runtimeStreams, []io.Closer{reader}, nil
+}
+
+// activeExecSessions returns the exec sessions of the container whose command
+// is still running
+func (c *Container) activeExecSessions() []*ExecSession {
+	sessions := []*ExecSession{}
+	for _, session := range c.state.ExecSessions {
+		if execSessionRunning(session) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// execSessionRunning returns whether the command of an exec session is still
+// running. Sessions without a tracked state are considered running while their
+// PID exists.
+func execSessionRunning(session *ExecSession) bool {
+	if session.State != ExecStateRunning && session.State != ExecStateUnknown {
+		return false
+	}
+	if session.PID <= 0 {
+		return false
+	}
+	// Ping the PID with signal 0 to see if it still exists
+	if err := unix.Kill(session.PID, 0); err == unix.ESRCH {
+		return false
+	}
+	return true
+}
+
+// execExitCode returns the exit code of the command of an exec session from
+// the error its runtime exited with
+func execExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return 125
+}
+
+// addExecProcess tracks the runtime process of a started exec session
+func (r *Runtime) addExecProcess(process *execProcess) {
+	r.execProcessesLock.Lock()
+	defer r.execProcessesLock.Unlock()
+
+	if r.execProcesses == nil {
+		r.execProcesses = make(map[string]*execProcess)
+	}
+	r.execProcesses[process.sessionID] = process
+}
+
+// getExecProcess returns the runtime process of an exec session started by
+// this runtime, or nil if there is none
+func (r *Runtime) getExecProcess(sessionID string) *execProcess {
+	r.execProcessesLock.Lock()
+	defer r.execProcessesLock.Unlock()
+
+	return r.execProcesses[sessionID]
+}
+
+// removeExecProcess stops tracking the runtime process of an exec session
+func (r *Runtime) removeExecProcess(sessionID string) {
+	r.execProcessesLock.Lock()
+	defer r.execProcessesLock.Unlock()
+
+	delete(r.execProcesses, sessionID)
+}
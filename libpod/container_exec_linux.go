@@ -0,0 +1,54 @@
+// +build linux
+
+package libpod
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// openExecTerminal allocates a new pseudo-terminal for an exec session, and
+// returns its master and slave ends
+func openExecTerminal() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error opening pseudo-terminal master")
+	}
+	if err := unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, nil, errors.Wrapf(err, "error unlocking pseudo-terminal")
+	}
+	ptyNum, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, nil, errors.Wrapf(err, "error getting pseudo-terminal number")
+	}
+	slavePath := fmt.Sprintf("/dev/pts/%d", ptyNum)
+	slave, err := os.OpenFile(slavePath, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, errors.Wrapf(err, "error opening pseudo-terminal slave %s", slavePath)
+	}
+	return master, slave, nil
+}
+
+// resizeExecTerminal resizes the terminal of an exec session
+func resizeExecTerminal(process *execProcess, size remotecommand.TerminalSize) error {
+	winsize := &unix.Winsize{
+		Row: size.Height,
+		Col: size.Width,
+	}
+	if err := unix.IoctlSetWinsize(int(process.terminal.Fd()), unix.TIOCSWINSZ, winsize); err != nil {
+		return errors.Wrapf(err, "error resizing terminal of exec session %s", process.sessionID)
+	}
+	// The runtime resizes the terminal of the command to the size of its
+	// own terminal once it receives SIGWINCH
+	if err := process.cmd.Process.Signal(unix.SIGWINCH); err != nil {
+		return errors.Wrapf(err, "error signalling runtime of exec session %s", process.sessionID)
+	}
+	return nil
+}
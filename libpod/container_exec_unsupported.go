@@ -0,0 +1,17 @@
+// +build !linux
+
+package libpod
+
+import (
+	"os"
+
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+func openExecTerminal() (*os.File, *os.File, error) {
+	return nil, nil, ErrNotImplemented
+}
+
+func resizeExecTerminal(process *execProcess, size remotecommand.TerminalSize) error {
+	return ErrNotImplemented
+}
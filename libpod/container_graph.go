@@ -71,6 +71,32 @@ func buildContainerGraph(ctrs []*Container) (*containerGraph, error) {
 	return graph, nil
 }
 
+// Order the containers of a graph so that every container comes after all the
+// containers it depends on
+// The graph must not contain cycles
+func (graph *containerGraph) dependencyOrder() []*Container {
+	ordered := make([]*Container, 0, len(graph.nodes))
+	visited := make(map[string]bool, len(graph.nodes))
+
+	var visit func(*containerNode)
+	visit = func(node *containerNode) {
+		if visited[node.id] {
+			return
+		}
+		visited[node.id] = true
+		for _, dep := range node.dependsOn {
+			visit(dep)
+		}
+		ordered = append(ordered, node.container)
+	}
+
+	for _, node := range graph.notDependedOnNodes {
+		visit(node)
+	}
+
+	return ordered
+}
+
 // Detect cycles in a container graph using Tarjan's strongly connected
 // components algorithm
 // Return true if a cycle is found, false otherwise
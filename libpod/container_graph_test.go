@@ -309,3 +309,37 @@ func TestBuildContainerGraphFourContainersNoneInCycle(t *testing.T) {
 	assert.Equal(t, 2, len(graph.noDepNodes))
 	assert.Equal(t, 2, len(graph.notDependedOnNodes))
 }
+
+func TestContainerGraphDependencyOrder(t *testing.T) {
+	manager, err := lock.NewInMemoryManager(16)
+	if err != nil {
+		t.Fatalf("Error setting up locks: %v", err)
+	}
+
+	ctr1, err := getTestCtr1(manager)
+	assert.NoError(t, err)
+	ctr2, err := getTestCtr2(manager)
+	assert.NoError(t, err)
+	ctr3, err := getTestCtrN("3", manager)
+	assert.NoError(t, err)
+	ctr4, err := getTestCtrN("4", manager)
+	assert.NoError(t, err)
+
+	ctr1.config.IPCNsCtr = ctr2.config.ID
+	ctr1.config.NetNsCtr = ctr3.config.ID
+	ctr2.config.UserNsCtr = ctr3.config.ID
+
+	graph, err := buildContainerGraph([]*Container{ctr1, ctr2, ctr3, ctr4})
+	assert.NoError(t, err)
+
+	ordered := graph.dependencyOrder()
+	assert.Equal(t, 4, len(ordered))
+
+	position := make(map[string]int)
+	for i, ctr := range ordered {
+		position[ctr.ID()] = i
+	}
+	assert.Equal(t, 4, len(position))
+	assert.True(t, position[ctr3.ID()] < position[ctr2.ID()])
+	assert.True(t, position[ctr2.ID()] < position[ctr1.ID()])
+}
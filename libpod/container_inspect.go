@@ -71,21 +71,23 @@ func (c *Container) getContainerInspectData(size bool, driverData *inspect.Data)
 		Path:    path,
 		Args:    args,
 		State: &inspect.ContainerInspectState{
-			OciVersion: spec.Version,
-			Status:     runtimeInfo.State.String(),
-			Running:    runtimeInfo.State == ContainerStateRunning,
-			Paused:     runtimeInfo.State == ContainerStatePaused,
-			OOMKilled:  runtimeInfo.OOMKilled,
-			Dead:       runtimeInfo.State.String() == "bad state",
-			Pid:        runtimeInfo.PID,
-			ExitCode:   runtimeInfo.ExitCode,
-			Error:      "", // can't get yet
-			StartedAt:  runtimeInfo.StartedTime,
-			FinishedAt: runtimeInfo.FinishedTime,
+			OciVersion:  spec.Version,
+			Status:      runtimeInfo.State.String(),
+			Running:     runtimeInfo.State == ContainerStateRunning,
+			Paused:      runtimeInfo.State == ContainerStatePaused,
+			OOMKilled:   runtimeInfo.OOMKilled,
+			Dead:        runtimeInfo.State.String() == "bad state",
+			Pid:         runtimeInfo.PID,
+			ExitCode:    runtimeInfo.ExitCode,
+			Error:       "", // can't get yet
+			StartedAt:   runtimeInfo.StartedTime,
+			FinishedAt:  runtimeInfo.FinishedTime,
+			Healthcheck: runtimeInfo.HealthCheck,
 		},
 		ImageID:         config.RootfsImageID,
 		ImageName:       config.RootfsImageName,
 		ExitCommand:     config.ExitCommand,
+		RestartCount:    int32(runtimeInfo.RestartCount),
 		Namespace:       config.Namespace,
 		Rootfs:          config.Rootfs,
 		ResolvConfPath:  resolvPath,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -14,9 +15,11 @@ import (
 	"time"
 
 	"github.com/containers/buildah/imagebuildah"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/ctime"
 	"github.com/containers/libpod/pkg/hooks"
 	"github.com/containers/libpod/pkg/hooks/exec"
+	"github.com/containers/libpod/pkg/inspect"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/archive"
@@ -35,6 +38,11 @@ import (
 const (
 	// name of the directory holding the artifacts
 	artifactsDir = "artifacts"
+	// name of the file in a checkpoint archive holding the container config
+	checkpointConfigFile = "config.dump"
+	// name of the file in a checkpoint archive holding the changes made to
+	// the container's root filesystem
+	checkpointRootfsDiffFile = "rootfs-diff.tar"
 )
 
 var (
@@ -136,6 +144,13 @@ func (c *Container) CheckpointPath() string {
 	return filepath.Join(c.bundlePath(), "checkpoint")
 }
 
+// hasCheckpoint returns whether a complete checkpoint of the container exists,
+// by looking for the inventory file of CRIU
+func (c *Container) hasCheckpoint() bool {
+	_, err := os.Stat(filepath.Join(c.CheckpointPath(), "inventory.img"))
+	return err == nil
+}
+
 // AttachSocketPath retrieves the path of the container's attach socket
 func (c *Container) AttachSocketPath() string {
 	return filepath.Join(c.runtime.ociRuntime.socketsDir, c.ID(), "attach")
@@ -146,6 +161,24 @@ func (c *Container) execPidPath(sessionID string) string {
 	return filepath.Join(c.state.RunDir, "exec_pid_"+sessionID)
 }
 
+// execBundlePath returns the directory conmon runs a detached exec session in
+func (c *Container) execBundlePath(sessionID string) string {
+	return filepath.Join(c.bundlePath(), "exec_"+sessionID)
+}
+
+// execExitFilePath returns the path to the exit file conmon writes once the
+// command of a detached exec session exits. conmon names exit files after the
+// container, so every session has an exit directory of its own.
+func (c *Container) execExitFilePath(sessionID string) string {
+	return filepath.Join(c.execBundlePath(sessionID), "exits", c.ID())
+}
+
+// execConmonPidPath returns the path to the file conmon writes its PID to for
+// a detached exec session
+func (c *Container) execConmonPidPath(sessionID string) string {
+	return filepath.Join(c.execBundlePath(sessionID), "conmon.pid")
+}
+
 // exitFilePath gets the path to the container's exit file
 func (c *Container) exitFilePath() string {
 	return filepath.Join(c.runtime.ociRuntime.exitsDir, c.ID())
@@ -214,6 +247,9 @@ func (c *Container) handleExitFile(exitFile string, fi os.FileInfo) error {
 
 	c.state.Exited = true
 
+	// Write an event for the container's death
+	c.newContainerExitedEvent(c.state.ExitCode)
+
 	return nil
 }
 
@@ -225,6 +261,13 @@ func (c *Container) syncContainer() error {
 	if err := c.runtime.state.UpdateContainer(c); err != nil {
 		return err
 	}
+	// Record the exit of exec sessions whose command exited after the
+	// process that started them
+	if len(c.state.ExecSessions) > 0 && c.syncExecSessions() {
+		if err := c.save(); err != nil {
+			return err
+		}
+	}
 	// If runtime knows about the container, update its status in runtime
 	// And then save back to disk
 	if (c.state.State != ContainerStateUnknown) &&
@@ -237,6 +280,14 @@ func (c *Container) syncContainer() error {
 		}
 		// Only save back to DB if state changed
 		if c.state.State != oldState {
+			// Check for a restart policy match
+			if c.config.RestartPolicy != RestartPolicyNone && c.config.RestartPolicy != RestartPolicyNo &&
+				(oldState == ContainerStateRunning || oldState == ContainerStatePaused) &&
+				(c.state.State == ContainerStateStopped || c.state.State == ContainerStateExited) &&
+				!c.state.StoppedByUser {
+				c.state.RestartPolicyMatch = true
+			}
+
 			if err := c.save(); err != nil {
 				return err
 			}
@@ -398,6 +449,7 @@ func resetState(state *ContainerState) error {
 	state.ExecSessions = make(map[string]*ExecSession)
 	state.NetworkStatus = nil
 	state.BindMounts = make(map[string]string)
+	state.VolumeMounts = nil
 
 	return nil
 }
@@ -527,6 +579,265 @@ func (c *Container) export(path string) error {
 	return err
 }
 
+// update merges the given resource limits into the container's OCI spec,
+// applies them to the container if it has been created in the OCI runtime
+// and saves the new spec in the state
+func (c *Container) update(resources *spec.LinuxResources) error {
+	// Work on a copy of the spec so the configuration is left untouched if
+	// the update fails
+	specJSON, err := json.Marshal(c.config.Spec)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling spec of container %s", c.ID())
+	}
+	newSpec := new(spec.Spec)
+	if err := json.Unmarshal(specJSON, newSpec); err != nil {
+		return errors.Wrapf(err, "error copying spec of container %s", c.ID())
+	}
+	if newSpec.Linux == nil {
+		newSpec.Linux = new(spec.Linux)
+	}
+	if newSpec.Linux.Resources == nil {
+		newSpec.Linux.Resources = new(spec.LinuxResources)
+	}
+	mergeLinuxResources(newSpec.Linux.Resources, resources)
+	if memory := newSpec.Linux.Resources.Memory; memory != nil && memory.Limit != nil && memory.Swap != nil {
+		if *memory.Limit > 0 && *memory.Swap > 0 && *memory.Swap < *memory.Limit {
+			return errors.Wrapf(ErrInvalidArg, "memory-swap limit %d of container %s is lower than its memory limit %d", *memory.Swap, c.ID(), *memory.Limit)
+		}
+	}
+
+	switch c.state.State {
+	case ContainerStateCreated, ContainerStateRunning, ContainerStatePaused:
+		if err := c.runtime.ociRuntime.updateContainer(c, newSpec.Linux.Resources); err != nil {
+			return errors.Wrapf(err, "error updating resource limits of container %s", c.ID())
+		}
+	}
+
+	newConfig := *c.config
+	newConfig.Spec = newSpec
+	if err := c.runtime.state.RewriteContainerConfig(c, &newConfig); err != nil {
+		return errors.Wrapf(err, "error saving resource limits of container %s", c.ID())
+	}
+	return nil
+}
+
+// networks returns the CNI networks the container is attached to, in the
+// order their interfaces were created
+func (c *Container) networks() []string {
+	if len(c.config.Networks) == 0 {
+		return []string{c.runtime.defaultNetwork()}
+	}
+	return c.config.Networks
+}
+
+// checkNetworkChange verifies that the CNI networks of the container can be
+// changed after its creation
+func (c *Container) checkNetworkChange() error {
+	if !c.config.CreateNetNS {
+		return errors.Wrapf(ErrInvalidArg, "container %s network namespace is not managed by libpod", c.ID())
+	}
+	if rootless.IsRootless() {
+		return errors.Wrapf(ErrInvalidArg, "rootless container %s is not attached to CNI networks", c.ID())
+	}
+	return nil
+}
+
+// connectNetwork attaches the container to a CNI network. If the network
+// namespace of the container is set up, the container is added to the
+// network immediately. The network and the requested IP are saved in the
+// container's configuration so the container joins it with the same IP
+// whenever the namespace is set up again.
+func (c *Container) connectNetwork(name string, ip net.IP) error {
+	if err := c.checkNetworkChange(); err != nil {
+		return err
+	}
+	networks := c.networks()
+	for _, n := range networks {
+		if n == name {
+			return errors.Wrapf(ErrInvalidArg, "container %s is already connected to network %s", c.ID(), name)
+		}
+	}
+	if ip != nil && c.state.NetNS == nil {
+		return errors.Wrapf(ErrCtrStateInvalid, "cannot request IP %s for container %s, its network namespace is not set up", ip, c.ID())
+	}
+
+	newConfig := *c.config
+	newConfig.Networks = append(append([]string{}, networks...), name)
+	// The IP is requested again whenever the namespace is set up
+	if ip != nil {
+		newConfig.StaticIPs = make(map[string][]net.IP)
+		for n, ips := range c.config.StaticIPs {
+			newConfig.StaticIPs[n] = ips
+		}
+		newConfig.StaticIPs[name] = []net.IP{ip}
+	}
+
+	if c.state.NetNS == nil {
+		if err := c.runtime.state.RewriteContainerConfig(c, &newConfig); err != nil {
+			return errors.Wrapf(err, "error saving networks of container %s", c.ID())
+		}
+		return nil
+	}
+
+	result, err := c.runtime.attachNetwork(c, name, ip)
+	if err != nil {
+		return err
+	}
+	if err := c.runtime.state.RewriteContainerConfig(c, &newConfig); err != nil {
+		if err2 := c.runtime.detachNetwork(c, name, result); err2 != nil {
+			logrus.Errorf("Error detaching container %s from network %s: %v", c.ID(), name, err2)
+		}
+		return errors.Wrapf(err, "error saving networks of container %s", c.ID())
+	}
+	// The results of the networks are kept in the order of the networks
+	c.state.NetworkStatus = append(c.state.NetworkStatus, result)
+	return c.save()
+}
+
+// disconnectNetwork detaches the container from a CNI network. If the
+// network namespace of the container is set up, the interface of the network
+// is removed immediately. The container must stay attached to at least one
+// network.
+func (c *Container) disconnectNetwork(name string) error {
+	if err := c.checkNetworkChange(); err != nil {
+		return err
+	}
+	networks := c.networks()
+	idx := -1
+	for i, n := range networks {
+		if n == name {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return errors.Wrapf(ErrInvalidArg, "container %s is not connected to network %s", c.ID(), name)
+	}
+	if len(networks) == 1 {
+		return errors.Wrapf(ErrInvalidArg, "cannot disconnect container %s from network %s, it is its only network", c.ID(), name)
+	}
+
+	oldConfig := *c.config
+	newConfig := *c.config
+	newConfig.Networks = append(append([]string{}, networks[:idx]...), networks[idx+1:]...)
+	// Static IPs are not requested again if the network is reconnected
+	if _, ok := c.config.StaticIPs[name]; ok {
+		newConfig.StaticIPs = make(map[string][]net.IP)
+		for n, ips := range c.config.StaticIPs {
+			if n != name {
+				newConfig.StaticIPs[n] = ips
+			}
+		}
+	}
+	if err := c.runtime.state.RewriteContainerConfig(c, &newConfig); err != nil {
+		return errors.Wrapf(err, "error saving networks of container %s", c.ID())
+	}
+
+	// The results of the networks are kept in the order of the networks
+	if c.state.NetNS == nil || idx >= len(c.state.NetworkStatus) {
+		return nil
+	}
+	if err := c.runtime.detachNetwork(c, name, c.state.NetworkStatus[idx]); err != nil {
+		if err2 := c.runtime.state.RewriteContainerConfig(c, &oldConfig); err2 != nil {
+			logrus.Errorf("Error restoring networks of container %s: %v", c.ID(), err2)
+		}
+		return err
+	}
+	c.state.NetworkStatus = append(c.state.NetworkStatus[:idx:idx], c.state.NetworkStatus[idx+1:]...)
+	return c.save()
+}
+
+// mergeLinuxResources sets the limits of dst that are set in src
+func mergeLinuxResources(dst, src *spec.LinuxResources) {
+	if src.CPU != nil {
+		if dst.CPU == nil {
+			dst.CPU = new(spec.LinuxCPU)
+		}
+		if src.CPU.Shares != nil {
+			dst.CPU.Shares = src.CPU.Shares
+		}
+		if src.CPU.Quota != nil {
+			dst.CPU.Quota = src.CPU.Quota
+		}
+		if src.CPU.Period != nil {
+			dst.CPU.Period = src.CPU.Period
+		}
+	}
+	if src.Memory != nil {
+		if dst.Memory == nil {
+			dst.Memory = new(spec.LinuxMemory)
+		}
+		if src.Memory.Limit != nil {
+			// As when a container is created, the swap limit defaults
+			// to double the memory limit, unless the container has a
+			// swap limit that was not defaulted
+			if src.Memory.Swap == nil && swapLimitDefaulted(dst.Memory) {
+				swap := 2 * *src.Memory.Limit
+				dst.Memory.Swap = &swap
+			}
+			dst.Memory.Limit = src.Memory.Limit
+		}
+		if src.Memory.Swap != nil {
+			dst.Memory.Swap = src.Memory.Swap
+		}
+	}
+	if src.Pids != nil {
+		dst.Pids = src.Pids
+	}
+	if src.BlockIO != nil && src.BlockIO.Weight != nil {
+		if dst.BlockIO == nil {
+			dst.BlockIO = new(spec.LinuxBlockIO)
+		}
+		dst.BlockIO.Weight = src.BlockIO.Weight
+	}
+}
+
+// swapLimitDefaulted returns whether memory has no swap limit, or the swap
+// limit defaulted to double the memory limit when the container was created
+func swapLimitDefaulted(memory *spec.LinuxMemory) bool {
+	if memory.Swap == nil || *memory.Swap == 0 {
+		return true
+	}
+	return memory.Limit != nil && *memory.Swap == 2**memory.Limit
+}
+
+// rename changes the name of the container in the state and in c/storage
+func (c *Container) rename(newName string) error {
+	oldName := c.Name()
+
+	// Rename the storage container first, so names reserved by containers
+	// that are not managed by libpod (e.g. buildah's) are detected before
+	// the state is modified
+	if c.config.Rootfs == "" {
+		if err := c.runtime.store.SetNames(c.ID(), []string{newName}); err != nil {
+			return errors.Wrapf(err, "error renaming storage for container %s", c.ID())
+		}
+	}
+
+	if err := c.runtime.state.RenameContainer(c, newName); err != nil {
+		if c.config.Rootfs == "" {
+			if err2 := c.runtime.store.SetNames(c.ID(), []string{oldName}); err2 != nil {
+				logrus.Errorf("error restoring storage name of container %s: %v", c.ID(), err2)
+			}
+		}
+		return errors.Wrapf(err, "error renaming container %s to %s", c.ID(), newName)
+	}
+
+	if c.config.Rootfs == "" {
+		metadata, err := c.runtime.storageService.GetContainerMetadata(c.ID())
+		if err != nil {
+			logrus.Errorf("error retrieving storage metadata of container %s: %v", c.ID(), err)
+			return nil
+		}
+		metadata.ContainerName = newName
+		if err := c.runtime.storageService.SetContainerMetadata(c.ID(), metadata); err != nil {
+			logrus.Errorf("error updating storage metadata of container %s: %v", c.ID(), err)
+		}
+	}
+
+	return nil
+}
+
 // Get path of artifact with a given name for this container
 func (c *Container) getArtifactPath(name string) string {
 	return filepath.Join(c.config.StaticDir, artifactsDir, name)
@@ -629,7 +940,9 @@ func (c *Container) completeNetworkSetup() error {
 }
 
 // Initialize a container, creating it in the runtime
-func (c *Container) init(ctx context.Context) error {
+// If retainRetries is false, the count of restarts made by the container's
+// restart policy is reset
+func (c *Container) init(ctx context.Context, retainRetries bool) error {
 	// Generate the OCI spec
 	spec, err := c.generateSpec(ctx)
 	if err != nil {
@@ -651,11 +964,18 @@ func (c *Container) init(ctx context.Context) error {
 	c.state.ExitCode = 0
 	c.state.Exited = false
 	c.state.State = ContainerStateCreated
+	c.state.StoppedByUser = false
+	c.state.RestartPolicyMatch = false
+
+	if !retainRetries {
+		c.state.RestartCount = 0
+	}
 
 	if err := c.save(); err != nil {
 		return err
 	}
 
+	defer c.newContainerEvent(events.Init)
 	return c.completeNetworkSetup()
 }
 
@@ -697,7 +1017,7 @@ func (c *Container) cleanupRuntime(ctx context.Context) error {
 // Should only be done on ContainerStateStopped containers.
 // Not necessary for ContainerStateExited - the container has already been
 // removed from the runtime, so init() can proceed freely.
-func (c *Container) reinit(ctx context.Context) error {
+func (c *Container) reinit(ctx context.Context, retainRetries bool) error {
 	logrus.Debugf("Recreating container %s in OCI runtime", c.ID())
 
 	if err := c.cleanupRuntime(ctx); err != nil {
@@ -705,7 +1025,7 @@ func (c *Container) reinit(ctx context.Context) error {
 	}
 
 	// Initialize the container again
-	return c.init(ctx)
+	return c.init(ctx, retainRetries)
 }
 
 // Initialize (if necessary) and start a container
@@ -743,12 +1063,12 @@ func (c *Container) initAndStart(ctx context.Context) (err error) {
 	if c.state.State == ContainerStateStopped {
 		logrus.Debugf("Recreating container %s in OCI runtime", c.ID())
 
-		if err := c.reinit(ctx); err != nil {
+		if err := c.reinit(ctx, false); err != nil {
 			return err
 		}
 	} else if c.state.State == ContainerStateConfigured ||
 		c.state.State == ContainerStateExited {
-		if err := c.init(ctx); err != nil {
+		if err := c.init(ctx, false); err != nil {
 			return err
 		}
 	}
@@ -769,18 +1089,126 @@ func (c *Container) start() error {
 	logrus.Debugf("Started container %s", c.ID())
 
 	c.state.State = ContainerStateRunning
+	c.state.StoppedByUser = false
+	c.state.RestartPolicyMatch = false
+
+	// A fresh start resets the health of the container
+	if c.HasHealthCheck() {
+		c.state.HealthCheck = &inspect.HealthCheckResults{
+			Status: HealthCheckStarting,
+			Log:    []inspect.HealthCheckLog{},
+		}
+	}
+
+	defer c.newContainerEvent(events.Start)
 
 	return c.save()
 }
 
+// Restart a container that has exited, if its restart policy requires it.
+// Returns whether the container was restarted. Must be called with the
+// container locked and synced, after the container has exited.
+func (c *Container) handleRestartPolicy(ctx context.Context) (restarted bool, err error) {
+	// If we did not get a restart policy match, exit immediately.
+	// Do the same if we're not a policy that restarts.
+	if !c.state.RestartPolicyMatch ||
+		c.config.RestartPolicy == RestartPolicyNo ||
+		c.config.RestartPolicy == RestartPolicyNone {
+		return false, nil
+	}
+
+	// If we're RestartPolicyOnFailure, we need to check retries and exit
+	// code.
+	if c.config.RestartPolicy == RestartPolicyOnFailure {
+		if c.state.ExitCode == 0 {
+			return false, nil
+		}
+
+		// If we don't have a max retries set, continue
+		if c.config.RestartRetries > 0 {
+			if c.state.RestartCount >= c.config.RestartRetries {
+				logrus.Debugf("Container %s restart policy trigger: retries exhausted", c.ID())
+				return false, nil
+			}
+			logrus.Debugf("Container %s restart policy trigger: on retry %d (of %d)",
+				c.ID(), c.state.RestartCount+1, c.config.RestartRetries)
+		}
+	}
+
+	// Is the container running again?
+	// If so, we don't have to do anything
+	if c.state.State == ContainerStateRunning || c.state.State == ContainerStatePaused {
+		return false, nil
+	} else if c.state.State == ContainerStateUnknown {
+		return false, errors.Wrapf(ErrInternal, "invalid container state encountered in restart attempt")
+	}
+
+	// Need to check if dependencies are alive
+	notRunning, err := c.checkDependenciesRunning()
+	if err != nil {
+		return false, errors.Wrapf(err, "error checking dependencies for container %s", c.ID())
+	}
+	if len(notRunning) > 0 {
+		depString := strings.Join(notRunning, ",")
+		return false, errors.Wrapf(ErrCtrStateInvalid, "some dependencies of container %s are not started: %s", c.ID(), depString)
+	}
+
+	logrus.Debugf("Restarting container %s due to restart policy %s", c.ID(), c.config.RestartPolicy)
+
+	// Increment restart count
+	c.state.RestartCount = c.state.RestartCount + 1
+	logrus.Debugf("Container %s now on retry %d", c.ID(), c.state.RestartCount)
+	if err := c.save(); err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err != nil {
+			if err2 := c.cleanup(ctx); err2 != nil {
+				logrus.Errorf("error cleaning up container %s: %v", c.ID(), err2)
+			}
+		}
+	}()
+	if err := c.prepare(); err != nil {
+		return false, err
+	}
+
+	if c.state.State == ContainerStateStopped {
+		// Reinitialize the container if we need to
+		if err := c.reinit(ctx, true); err != nil {
+			return false, err
+		}
+	} else if c.state.State == ContainerStateConfigured ||
+		c.state.State == ContainerStateExited {
+		// Initialize the container
+		if err := c.init(ctx, true); err != nil {
+			return false, err
+		}
+	}
+	if err := c.start(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Internal, non-locking function to stop container
 func (c *Container) stop(timeout uint) error {
 	logrus.Debugf("Stopping ctr %s with timeout %d", c.ID(), timeout)
 
+	// Record that the stop was requested before signalling the container,
+	// so a racing cleanup process does not treat the exit as eligible for
+	// a restart policy
+	c.state.StoppedByUser = true
+	if err := c.save(); err != nil {
+		return err
+	}
+
 	if err := c.runtime.ociRuntime.stopContainer(c, timeout); err != nil {
 		return err
 	}
 
+	c.newContainerEvent(events.Stop)
+
 	// Wait until we have an exit file, and sync once we do
 	return c.waitForExitFileAndSync()
 }
@@ -794,6 +1222,7 @@ func (c *Container) pause() error {
 	logrus.Debugf("Paused container %s", c.ID())
 
 	c.state.State = ContainerStatePaused
+	defer c.newContainerEvent(events.Pause)
 
 	return c.save()
 }
@@ -807,6 +1236,7 @@ func (c *Container) unpause() error {
 	logrus.Debugf("Unpaused container %s", c.ID())
 
 	c.state.State = ContainerStateRunning
+	defer c.newContainerEvent(events.Unpause)
 
 	return c.save()
 }
@@ -817,6 +1247,8 @@ func (c *Container) restartWithTimeout(ctx context.Context, timeout uint) (err e
 		return errors.Wrapf(ErrCtrStateInvalid, "unable to restart a container in a paused or unknown state")
 	}
 
+	c.newContainerEvent(events.Restart)
+
 	if c.state.State == ContainerStateRunning {
 		if err := c.stop(timeout); err != nil {
 			return err
@@ -835,13 +1267,13 @@ func (c *Container) restartWithTimeout(ctx context.Context, timeout uint) (err e
 
 	if c.state.State == ContainerStateStopped {
 		// Reinitialize the container if we need to
-		if err := c.reinit(ctx); err != nil {
+		if err := c.reinit(ctx, false); err != nil {
 			return err
 		}
 	} else if c.state.State == ContainerStateConfigured ||
 		c.state.State == ContainerStateExited {
 		// Initialize the container
-		if err := c.init(ctx); err != nil {
+		if err := c.init(ctx, false); err != nil {
 			return err
 		}
 	}
@@ -856,6 +1288,14 @@ func (c *Container) restartWithTimeout(ctx context.Context, timeout uint) (err e
 // good
 func (c *Container) mountStorage() (string, error) {
 	var err error
+
+	// Named volumes are mounted even if the root filesystem already is,
+	// as it may have been mounted without starting the container.
+	// They are unmounted by cleanupStorage, also if mounting fails.
+	if err := c.mountNamedVolumes(); err != nil {
+		return "", err
+	}
+
 	// Container already mounted, nothing to do
 	if c.state.Mounted {
 		return c.state.Mountpoint, nil
@@ -890,6 +1330,10 @@ func (c *Container) mountStorage() (string, error) {
 
 // cleanupStorage unmounts and cleans up the container's root filesystem
 func (c *Container) cleanupStorage() error {
+	if err := c.unmountNamedVolumes(); err != nil {
+		return err
+	}
+
 	if !c.state.Mounted {
 		// Already unmounted, do nothing
 		logrus.Debugf("Storage is already unmounted, skipping...")
@@ -932,6 +1376,11 @@ func (c *Container) cleanup(ctx context.Context) error {
 
 	logrus.Debugf("Cleaning up container %s", c.ID())
 
+	// Only record an event if there is something to clean up
+	if c.state.State == ContainerStateStopped {
+		defer c.newContainerEvent(events.Cleanup)
+	}
+
 	// Clean up network namespace, if present
 	if err := c.cleanupNetwork(); err != nil {
 		lastError = err
@@ -1292,3 +1741,89 @@ func getExcludedCGroups() (excludes []string) {
 	excludes = []string{"rdma"}
 	return
 }
+
+// namedVolumeFromSource returns the name of the named volume a mount source
+// belongs to, and the path of the source within the volume
+func (c *Container) namedVolumeFromSource(source string) (string, string, bool) {
+	if c.runtime.config.VolumePath == "" || !strings.HasPrefix(source, c.runtime.config.VolumePath+"/") {
+		return "", "", false
+	}
+	split := strings.SplitN(source[len(c.runtime.config.VolumePath)+1:], "/", 3)
+	subPath := ""
+	if len(split) == 3 {
+		subPath = split[2]
+	}
+	return split[0], subPath, true
+}
+
+// mountNamedVolumes mounts the named volumes of the container that need
+// mounting before use, and records where their contents are in the
+// container's state
+func (c *Container) mountNamedVolumes() error {
+	for _, m := range c.config.Spec.Mounts {
+		volumeName, _, ok := c.namedVolumeFromSource(m.Source)
+		if !ok {
+			continue
+		}
+		if _, mounted := c.state.VolumeMounts[volumeName]; mounted {
+			continue
+		}
+		volume, err := c.runtime.state.Volume(volumeName)
+		if err != nil {
+			return errors.Wrapf(err, "error retrieving volume %s of container %s", volumeName, c.ID())
+		}
+		if !volume.needsMount() {
+			continue
+		}
+		mountPoint, err := volume.mount(c.ID())
+		if err != nil {
+			return errors.Wrapf(err, "error mounting volume %s for container %s", volumeName, c.ID())
+		}
+		if c.state.VolumeMounts == nil {
+			c.state.VolumeMounts = make(map[string]string)
+		}
+		c.state.VolumeMounts[volumeName] = mountPoint
+	}
+	return nil
+}
+
+// unmountNamedVolumes unmounts the named volumes that were mounted for the
+// container. Volumes that fail to unmount are forgotten regardless, as the
+// container could never be cleaned up otherwise.
+func (c *Container) unmountNamedVolumes() error {
+	if len(c.state.VolumeMounts) == 0 {
+		return nil
+	}
+	for volumeName := range c.state.VolumeMounts {
+		if err := c.unmountNamedVolume(volumeName); err != nil {
+			logrus.Errorf("Error unmounting volume %s of container %s: %v", volumeName, c.ID(), err)
+		}
+		delete(c.state.VolumeMounts, volumeName)
+	}
+	if c.valid {
+		return c.save()
+	}
+	return nil
+}
+
+func (c *Container) unmountNamedVolume(volumeName string) error {
+	volume, err := c.runtime.state.Volume(volumeName)
+	if err != nil {
+		return err
+	}
+	return volume.unmount(c.ID())
+}
+
+// namedVolumeSource returns the host path of a mount source, which differs
+// from the source for named volumes that were mounted by volume plugins
+func (c *Container) namedVolumeSource(source string) string {
+	volumeName, subPath, ok := c.namedVolumeFromSource(source)
+	if !ok {
+		return source
+	}
+	mountPoint, ok := c.state.VolumeMounts[volumeName]
+	if !ok {
+		return source
+	}
+	return filepath.Join(mountPoint, subPath)
+}
@@ -5,6 +5,7 @@ package libpod
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -18,6 +19,7 @@ import (
 
 	cnitypes "github.com/containernetworking/cni/pkg/types/current"
 	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containers/libpod/libpod/events"
 	crioAnnotations "github.com/containers/libpod/pkg/annotations"
 	"github.com/containers/libpod/pkg/apparmor"
 	"github.com/containers/libpod/pkg/criu"
@@ -25,6 +27,7 @@ import (
 	"github.com/containers/libpod/pkg/resolvconf"
 	"github.com/containers/libpod/pkg/rootless"
 	"github.com/containers/libpod/pkg/secrets"
+	"github.com/containers/storage/pkg/archive"
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/mrunalp/fileutils"
 	"github.com/opencontainers/runc/libcontainer/user"
@@ -174,7 +177,11 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 	if err != nil {
 		return nil, err
 	}
-	g := generate.NewFromSpec(c.config.Spec)
+	// Work on a copy of the mounts, so those of the container's config
+	// keep referring to named volumes by their path in the volume path
+	newSpec := *c.config.Spec
+	newSpec.Mounts = append([]spec.Mount{}, c.config.Spec.Mounts...)
+	g := generate.NewFromSpec(&newSpec)
 
 	// If network namespace was requested, add it now
 	if c.config.CreateNetNS {
@@ -270,40 +277,8 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 	}
 
 	// Add shared namespaces from other containers
-	if c.config.IPCNsCtr != "" {
-		if err := c.addNamespaceContainer(&g, IPCNS, c.config.IPCNsCtr, spec.IPCNamespace); err != nil {
-			return nil, err
-		}
-	}
-	if c.config.MountNsCtr != "" {
-		if err := c.addNamespaceContainer(&g, MountNS, c.config.MountNsCtr, spec.MountNamespace); err != nil {
-			return nil, err
-		}
-	}
-	if c.config.NetNsCtr != "" {
-		if err := c.addNamespaceContainer(&g, NetNS, c.config.NetNsCtr, spec.NetworkNamespace); err != nil {
-			return nil, err
-		}
-	}
-	if c.config.PIDNsCtr != "" {
-		if err := c.addNamespaceContainer(&g, PIDNS, c.config.PIDNsCtr, string(spec.PIDNamespace)); err != nil {
-			return nil, err
-		}
-	}
-	if c.config.UserNsCtr != "" {
-		if err := c.addNamespaceContainer(&g, UserNS, c.config.UserNsCtr, spec.UserNamespace); err != nil {
-			return nil, err
-		}
-	}
-	if c.config.UTSNsCtr != "" {
-		if err := c.addNamespaceContainer(&g, UTSNS, c.config.UTSNsCtr, spec.UTSNamespace); err != nil {
-			return nil, err
-		}
-	}
-	if c.config.CgroupNsCtr != "" {
-		if err := c.addNamespaceContainer(&g, CgroupNS, c.config.CgroupNsCtr, spec.CgroupNamespace); err != nil {
-			return nil, err
-		}
+	if err := c.addSharedNamespaces(&g); err != nil {
+		return nil, err
 	}
 
 	if c.config.Rootfs == "" {
@@ -368,6 +343,7 @@ func (c *Container) generateSpec(ctx context.Context) (*spec.Spec, error) {
 	// For private volumes any root propagation value should work.
 	rootPropagation := ""
 	for _, m := range mounts {
+		m.Source = c.namedVolumeSource(m.Source)
 		g.AddMount(m)
 		for _, opt := range m.Options {
 			switch opt {
@@ -454,6 +430,47 @@ func (c *Container) setupSystemd(mounts []spec.Mount, g generate.Generator) erro
 	return nil
 }
 
+// Add the namespaces the container shares with other containers to the spec
+func (c *Container) addSharedNamespaces(g *generate.Generator) error {
+	if c.config.IPCNsCtr != "" {
+		if err := c.addNamespaceContainer(g, IPCNS, c.config.IPCNsCtr, spec.IPCNamespace); err != nil {
+			return err
+		}
+	}
+	if c.config.MountNsCtr != "" {
+		if err := c.addNamespaceContainer(g, MountNS, c.config.MountNsCtr, spec.MountNamespace); err != nil {
+			return err
+		}
+	}
+	if c.config.NetNsCtr != "" {
+		if err := c.addNamespaceContainer(g, NetNS, c.config.NetNsCtr, spec.NetworkNamespace); err != nil {
+			return err
+		}
+	}
+	if c.config.PIDNsCtr != "" {
+		if err := c.addNamespaceContainer(g, PIDNS, c.config.PIDNsCtr, string(spec.PIDNamespace)); err != nil {
+			return err
+		}
+	}
+	if c.config.UserNsCtr != "" {
+		if err := c.addNamespaceContainer(g, UserNS, c.config.UserNsCtr, spec.UserNamespace); err != nil {
+			return err
+		}
+	}
+	if c.config.UTSNsCtr != "" {
+		if err := c.addNamespaceContainer(g, UTSNS, c.config.UTSNsCtr, spec.UTSNamespace); err != nil {
+			return err
+		}
+	}
+	if c.config.CgroupNsCtr != "" {
+		if err := c.addNamespaceContainer(g, CgroupNS, c.config.CgroupNsCtr, spec.CgroupNamespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Add an existing container's namespace to the spec
 func (c *Container) addNamespaceContainer(g *generate.Generator, ns LinuxNS, ctr string, specNS string) error {
 	nsCtr, err := c.runtime.state.Container(ctr)
@@ -480,9 +497,16 @@ func (c *Container) checkpoint(ctx context.Context, options ContainerCheckpointO
 		return errors.Errorf("checkpointing a container requires at least CRIU %d", criu.MinCriuVersion)
 	}
 
-	if c.state.State != ContainerStateRunning {
+	// Paused containers can be checkpointed as well, CRIU leaves them
+	// frozen when it is done
+	if c.state.State != ContainerStateRunning && c.state.State != ContainerStatePaused {
 		return errors.Wrapf(ErrCtrStateInvalid, "%q is not running, cannot checkpoint", c.state.State)
 	}
+	if options.TargetFile != "" {
+		if err := c.checkExportable(); err != nil {
+			return err
+		}
+	}
 	if err := c.runtime.ociRuntime.checkpointContainer(c, options); err != nil {
 		return err
 	}
@@ -499,6 +523,7 @@ func (c *Container) checkpoint(ctx context.Context, options ContainerCheckpointO
 	}
 
 	logrus.Debugf("Checkpointed container %s", c.ID())
+	c.newContainerEvent(events.Checkpoint)
 
 	if !options.KeepRunning {
 		c.state.State = ContainerStateStopped
@@ -509,6 +534,17 @@ func (c *Container) checkpoint(ctx context.Context, options ContainerCheckpointO
 		}
 	}
 
+	if options.TargetFile != "" {
+		if err := c.exportCheckpoint(options.TargetFile); err != nil {
+			// The checkpoint itself was taken, so make sure the
+			// state reflects it
+			if err2 := c.save(); err2 != nil {
+				logrus.Errorf("error saving container %s state: %v", c.ID(), err2)
+			}
+			return err
+		}
+	}
+
 	if !options.Keep {
 		// Remove log file
 		os.Remove(filepath.Join(c.bundlePath(), "dump.log"))
@@ -529,35 +565,44 @@ func (c *Container) restore(ctx context.Context, options ContainerCheckpointOpti
 		return errors.Wrapf(ErrCtrStateInvalid, "container %s is running or paused, cannot restore", c.ID())
 	}
 
+	if options.TargetFile != "" {
+		if err := c.importCheckpoint(options.TargetFile); err != nil {
+			return err
+		}
+	}
+
 	// Let's try to stat() CRIU's inventory file. If it does not exist, it makes
 	// no sense to try a restore. This is a minimal check if a checkpoint exist.
 	if _, err := os.Stat(filepath.Join(c.CheckpointPath(), "inventory.img")); os.IsNotExist(err) {
 		return errors.Wrapf(err, "A complete checkpoint for this container cannot be found, cannot restore")
 	}
 
-	// Read network configuration from checkpoint
+	// Read network configuration from checkpoint, unless the container
+	// takes the addresses its networks allocate.
 	// Currently only one interface with one IP is supported.
-	networkStatusFile, err := os.Open(filepath.Join(c.bundlePath(), "network.status"))
-	if err == nil {
-		// The file with the network.status does exist. Let's restore the
-		// container with the same IP address as during checkpointing.
-		defer networkStatusFile.Close()
-		var networkStatus []*cnitypes.Result
-		networkJSON, err := ioutil.ReadAll(networkStatusFile)
-		if err != nil {
-			return err
-		}
-		json.Unmarshal(networkJSON, &networkStatus)
-		// Take the first IP address
-		var IP net.IP
-		if len(networkStatus) > 0 {
-			if len(networkStatus[0].IPs) > 0 {
-				IP = networkStatus[0].IPs[0].Address.IP
+	if !options.IgnoreStaticIP {
+		networkStatusFile, err := os.Open(filepath.Join(c.bundlePath(), "network.status"))
+		if err == nil {
+			// The file with the network.status does exist. Let's restore the
+			// container with the same IP address as during checkpointing.
+			defer networkStatusFile.Close()
+			var networkStatus []*cnitypes.Result
+			networkJSON, err := ioutil.ReadAll(networkStatusFile)
+			if err != nil {
+				return err
+			}
+			json.Unmarshal(networkJSON, &networkStatus)
+			// Take the first IP address
+			var IP net.IP
+			if len(networkStatus) > 0 {
+				if len(networkStatus[0].IPs) > 0 {
+					IP = networkStatus[0].IPs[0].Address.IP
+				}
+			}
+			if IP != nil {
+				// Tell CNI which IP address we want.
+				c.requestedIP = IP
 			}
-		}
-		if IP != nil {
-			// Tell CNI which IP address we want.
-			c.requestedIP = IP
 		}
 	}
 
@@ -573,13 +618,24 @@ func (c *Container) restore(ctx context.Context, options ContainerCheckpointOpti
 		return err
 	}
 
-	// Read config
-	jsonPath := filepath.Join(c.bundlePath(), "config.json")
-	logrus.Debugf("generate.NewFromFile at %v", jsonPath)
-	g, err := generate.NewFromFile(jsonPath)
-	if err != nil {
-		logrus.Debugf("generate.NewFromFile failed with %v", err)
-		return err
+	var g generate.Generator
+	if options.TargetFile != "" {
+		// The runtime spec of an imported checkpoint refers to paths on
+		// the host it was taken on, so generate one for this host
+		newSpec, err := c.generateSpec(ctx)
+		if err != nil {
+			return err
+		}
+		g = generate.NewFromSpec(newSpec)
+	} else {
+		// Read config
+		jsonPath := filepath.Join(c.bundlePath(), "config.json")
+		logrus.Debugf("generate.NewFromFile at %v", jsonPath)
+		g, err = generate.NewFromFile(jsonPath)
+		if err != nil {
+			logrus.Debugf("generate.NewFromFile failed with %v", err)
+			return err
+		}
 	}
 
 	// We want to have the same network namespace as before.
@@ -587,6 +643,12 @@ func (c *Container) restore(ctx context.Context, options ContainerCheckpointOpti
 		g.AddOrReplaceLinuxNamespace(spec.NetworkNamespace, c.state.NetNS.Path())
 	}
 
+	// The containers we share namespaces with may have been restored
+	// themselves, so their namespaces have moved
+	if err := c.addSharedNamespaces(&g); err != nil {
+		return err
+	}
+
 	// Save the OCI spec to disk
 	if err := c.saveSpec(g.Spec()); err != nil {
 		return err
@@ -606,6 +668,7 @@ func (c *Container) restore(ctx context.Context, options ContainerCheckpointOpti
 	logrus.Debugf("Restored container %s", c.ID())
 
 	c.state.State = ContainerStateRunning
+	c.newContainerEvent(events.Restore)
 
 	if !options.Keep {
 		// Delete all checkpoint related files. At this point, in theory, all files
@@ -629,6 +692,135 @@ func (c *Container) restore(ctx context.Context, options ContainerCheckpointOpti
 	return c.save()
 }
 
+// checkExportable verifies that the container does not depend on anything
+// that only exists on this host, so a checkpoint of it can be restored
+// elsewhere
+func (c *Container) checkExportable() error {
+	if c.config.Pod != "" {
+		return errors.Wrapf(ErrInvalidArg, "cannot export checkpoint of container %s as it is part of a pod", c.ID())
+	}
+	if len(c.Dependencies()) > 0 {
+		return errors.Wrapf(ErrInvalidArg, "cannot export checkpoint of container %s as it depends on other containers", c.ID())
+	}
+	if c.config.Rootfs != "" {
+		return errors.Wrapf(ErrInvalidArg, "cannot export checkpoint of container %s as it was not created from an image", c.ID())
+	}
+	if len(c.config.LocalVolumes) > 0 {
+		return errors.Wrapf(ErrInvalidArg, "cannot export checkpoint of container %s as it uses volumes of other containers", c.ID())
+	}
+	for _, mount := range c.config.Spec.Mounts {
+		if strings.HasPrefix(mount.Source, c.runtime.config.VolumePath) {
+			return errors.Wrapf(ErrInvalidArg, "cannot export checkpoint of container %s as it uses named volume %s", c.ID(), mount.Source)
+		}
+	}
+	return nil
+}
+
+// exportCheckpoint writes the checkpoint of the container, together with its
+// configuration and the changes made to its root filesystem, to a gzipped
+// tar archive
+func (c *Container) exportCheckpoint(dest string) error {
+	logrus.Debugf("Exporting checkpoint of container %s to %s", c.ID(), dest)
+
+	configJSON, err := json.MarshalIndent(c.config, "", "     ")
+	if err != nil {
+		return errors.Wrapf(err, "error encoding container %s config", c.ID())
+	}
+	configPath := filepath.Join(c.bundlePath(), checkpointConfigFile)
+	if err := ioutil.WriteFile(configPath, configJSON, 0600); err != nil {
+		return err
+	}
+	defer os.Remove(configPath)
+
+	storeCtr, err := c.runtime.store.Container(c.ID())
+	if err != nil {
+		return errors.Wrapf(err, "error looking up container %s in storage", c.ID())
+	}
+	diff, err := c.runtime.store.Diff("", storeCtr.LayerID, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error getting root filesystem changes of container %s", c.ID())
+	}
+	defer diff.Close()
+	diffPath := filepath.Join(c.bundlePath(), checkpointRootfsDiffFile)
+	diffFile, err := os.OpenFile(diffPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(diffPath)
+	_, err = io.Copy(diffFile, diff)
+	diffFile.Close()
+	if err != nil {
+		return errors.Wrapf(err, "error writing root filesystem changes of container %s", c.ID())
+	}
+
+	input, err := archive.TarWithOptions(c.bundlePath(), &archive.TarOptions{
+		Compression: archive.Gzip,
+		IncludeFiles: []string{
+			"checkpoint",
+			"config.json",
+			"network.status",
+			checkpointConfigFile,
+			checkpointRootfsDiffFile,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error creating checkpoint archive of container %s", c.ID())
+	}
+	defer input.Close()
+
+	outFile, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "error creating checkpoint archive %s", dest)
+	}
+	defer outFile.Close()
+	if _, err := io.Copy(outFile, input); err != nil {
+		return errors.Wrapf(err, "error writing checkpoint archive %s", dest)
+	}
+	return nil
+}
+
+// importCheckpoint unpacks a checkpoint archive written by exportCheckpoint
+// into the bundle of the container, and applies the root filesystem changes
+// it contains to the container's storage
+func (c *Container) importCheckpoint(input string) error {
+	logrus.Debugf("Importing checkpoint of container %s from %s", c.ID(), input)
+
+	archiveFile, err := os.Open(input)
+	if err != nil {
+		return errors.Wrapf(err, "error opening checkpoint archive %s", input)
+	}
+	defer archiveFile.Close()
+
+	// The container was created from the config in the archive already,
+	// and its runtime spec is generated for this host when restoring
+	options := &archive.TarOptions{
+		ExcludePatterns: []string{
+			"config.json",
+			checkpointConfigFile,
+		},
+	}
+	if err := archive.Untar(archiveFile, c.bundlePath(), options); err != nil {
+		return errors.Wrapf(err, "error unpacking checkpoint archive %s", input)
+	}
+
+	diffPath := filepath.Join(c.bundlePath(), checkpointRootfsDiffFile)
+	diffFile, err := os.Open(diffPath)
+	if err != nil {
+		return errors.Wrapf(err, "checkpoint archive %s does not contain root filesystem changes", input)
+	}
+	defer os.Remove(diffPath)
+	defer diffFile.Close()
+
+	storeCtr, err := c.runtime.store.Container(c.ID())
+	if err != nil {
+		return errors.Wrapf(err, "error looking up container %s in storage", c.ID())
+	}
+	if _, err := c.runtime.store.ApplyDiff(storeCtr.LayerID, diffFile); err != nil {
+		return errors.Wrapf(err, "error applying root filesystem changes to container %s", c.ID())
+	}
+	return nil
+}
+
 // Make standard bind mounts to include in the container
 func (c *Container) makeBindMounts() error {
 	if err := os.Chown(c.state.RunDir, c.RootUID(), c.RootGID()); err != nil {
@@ -79,3 +79,57 @@ func init() {
 		panic("we need a reliable executable path on Windows")
 	}
 }
+
+func TestMergeLinuxResourcesSwap(t *testing.T) {
+	limit := func(v int64) *int64 { return &v }
+
+	// The swap limit defaulted at creation follows the memory limit
+	dst := &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(100), Swap: limit(200)}}
+	mergeLinuxResources(dst, &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(300)}})
+	assert.Equal(t, int64(300), *dst.Memory.Limit)
+	assert.Equal(t, int64(600), *dst.Memory.Swap)
+
+	// A swap limit set explicitly is kept
+	dst = &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(100), Swap: limit(1000)}}
+	mergeLinuxResources(dst, &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(300)}})
+	assert.Equal(t, int64(1000), *dst.Memory.Swap)
+
+	dst = &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(100), Swap: limit(-1)}}
+	mergeLinuxResources(dst, &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(300)}})
+	assert.Equal(t, int64(-1), *dst.Memory.Swap)
+
+	// Without a swap limit, the swap limit defaults to double the memory
+	dst = &rspec.LinuxResources{}
+	mergeLinuxResources(dst, &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(300)}})
+	assert.Equal(t, int64(600), *dst.Memory.Swap)
+
+	// An explicit swap limit of the update wins
+	dst = &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(100), Swap: limit(200)}}
+	mergeLinuxResources(dst, &rspec.LinuxResources{Memory: &rspec.LinuxMemory{Limit: limit(300), Swap: limit(400)}})
+	assert.Equal(t, int64(400), *dst.Memory.Swap)
+}
+
+func TestReadExecExitFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "libpod_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	exitFile := filepath.Join(dir, "exit")
+	_, err = readExecExitFile(exitFile)
+	assert.True(t, os.IsNotExist(err))
+
+	if err := ioutil.WriteFile(exitFile, []byte("137\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	exitCode, err := readExecExitFile(exitFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 137, exitCode)
+
+	if err := ioutil.WriteFile(exitFile, []byte(""), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = readExecExitFile(exitFile)
+	assert.Error(t, err)
+}
@@ -13,6 +13,9 @@ var (
 	ErrNoSuchImage = errors.New("no such image")
 	// ErrNoSuchVolume indicates the requested volume does not exist
 	ErrNoSuchVolume = errors.New("no such volume")
+	// ErrNoSuchExecSession indicates the requested exec session does not
+	// exist
+	ErrNoSuchExecSession = errors.New("no such exec session")
 
 	// ErrCtrExists indicates a container with the same name or ID already
 	// exists
@@ -27,8 +30,13 @@ var (
 	// ErrCtrStateInvalid indicates a container is in an improper state for
 	// the requested operation
 	ErrCtrStateInvalid = errors.New("container state improper")
+	// ErrExecSessionStateInvalid indicates an exec session is in an
+	// improper state for the requested operation
+	ErrExecSessionStateInvalid = errors.New("exec session state improper")
 	// ErrVolumeBeingUsed indicates that a volume is being used by at least one container
 	ErrVolumeBeingUsed = errors.New("volume is being used")
+	// ErrNetworkBeingUsed indicates that a network is being used by at least one container
+	ErrNetworkBeingUsed = errors.New("network is being used")
 
 	// ErrRuntimeFinalized indicates that the runtime has already been
 	// created and cannot be modified
@@ -0,0 +1,80 @@
+package libpod
+
+import (
+	"fmt"
+
+	"github.com/containers/libpod/libpod/events"
+	"github.com/sirupsen/logrus"
+)
+
+// newContainerEvent creates a new event based on a container
+func (c *Container) newContainerEvent(status events.Status) {
+	e := events.NewEvent(status)
+	e.ID = c.ID()
+	e.Name = c.Name()
+	e.Type = events.Container
+	e.Attributes = c.eventAttributes()
+	c.runtime.writeEvent(e)
+}
+
+// newContainerExitedEvent creates a new event for a container's death
+func (c *Container) newContainerExitedEvent(exitCode int32) {
+	e := events.NewEvent(events.Died)
+	e.ID = c.ID()
+	e.Name = c.Name()
+	e.Type = events.Container
+	e.Attributes = c.eventAttributes()
+	e.Attributes["exitCode"] = fmt.Sprintf("%d", exitCode)
+	c.runtime.writeEvent(e)
+}
+
+// eventAttributes returns the attributes recorded with every event of the
+// container
+func (c *Container) eventAttributes() map[string]string {
+	attributes := map[string]string{
+		"image": c.config.RootfsImageName,
+	}
+	if c.config.Pod != "" {
+		attributes["pod"] = c.config.Pod
+	}
+	return attributes
+}
+
+// newPodEvent creates a new event for a libpod pod
+func (p *Pod) newPodEvent(status events.Status) {
+	e := events.NewEvent(status)
+	e.ID = p.ID()
+	e.Name = p.Name()
+	e.Type = events.Pod
+	p.runtime.writeEvent(e)
+}
+
+// newVolumeEvent creates a new event for a libpod volume
+func (v *Volume) newVolumeEvent(status events.Status) {
+	e := events.NewEvent(status)
+	e.Name = v.Name()
+	e.Type = events.Volume
+	v.runtime.writeEvent(e)
+}
+
+// writeEvent records an event with the eventer of the runtime. Failing to
+// record an event is not fatal to the operation that caused it.
+func (r *Runtime) writeEvent(e events.Event) {
+	if r.eventer == nil {
+		return
+	}
+	if err := r.eventer.Write(e); err != nil {
+		logrus.Errorf("unable to write %s event: %q", e.Type, err)
+	}
+}
+
+// Events is a wrapper function for everyone to begin tailing the events log
+// with options. The events are sent to the channel given in the options,
+// which is closed once reading is done.
+func (r *Runtime) Events(options events.ReadOptions) error {
+	if !r.valid {
+		close(options.EventChannel)
+		return ErrRuntimeStopped
+	}
+	return r.eventer.Read(options)
+}
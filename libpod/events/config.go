@@ -0,0 +1,130 @@
+package events
+
+import (
+	"time"
+)
+
+// EventerOptions describe where events are recorded
+type EventerOptions struct {
+	// LogFilePath is the path to the file events are written to and read
+	// from
+	LogFilePath string
+	// Journald indicates whether events are also sent to the systemd
+	// journal
+	Journald bool
+}
+
+// Eventer is the interface for recording and reading events
+type Eventer interface {
+	// Write an event to a backend
+	Write(event Event) error
+	// Read an event from the backend
+	Read(options ReadOptions) error
+}
+
+// ReadOptions describe the attributes needed to read event logs
+type ReadOptions struct {
+	// EventChannel is the comm path back to user. It is closed once all
+	// matching events have been sent.
+	EventChannel chan *Event
+	// Filters are key/value pairs that describe which events to return
+	Filters []string
+	// Since reads events after the given time
+	Since string
+	// Stream is follow
+	Stream bool
+	// Until reads events before the given time
+	Until string
+}
+
+// Event describes the attributes of a libpod event
+type Event struct {
+	// ID can be for the container, image, pod or volume
+	ID string `json:",omitempty"`
+	// Name is the name of the container, image, pod or volume
+	Name string `json:",omitempty"`
+	// Status describes the event that occurred
+	Status Status
+	// Time the event occurred
+	Time time.Time
+	// Type of event that occurred
+	Type Type
+	// Attributes hold additional information about the event, for
+	// instance the image of a container or the exit code of a process
+	Attributes map[string]string `json:",omitempty"`
+}
+
+// Type of event that occurred (container, volume, image, pod, etc)
+type Type string
+
+// Status describes the actual event action (stop, start, create, kill)
+type Status string
+
+const (
+	// Container - event is related to containers
+	Container Type = "container"
+	// Image - event is related to images
+	Image Type = "image"
+	// Pod - event is related to pods
+	Pod Type = "pod"
+	// Volume - event is related to volumes
+	Volume Type = "volume"
+
+	// Attach ...
+	Attach Status = "attach"
+	// Checkpoint ...
+	Checkpoint Status = "checkpoint"
+	// Cleanup ...
+	Cleanup Status = "cleanup"
+	// Commit ...
+	Commit Status = "commit"
+	// Connect is emitted when a container is connected to a network
+	Connect Status = "connect"
+	// Create ...
+	Create Status = "create"
+	// Disconnect is emitted when a container is disconnected from a
+	// network
+	Disconnect Status = "disconnect"
+	// Died is emitted when the process of a container exits
+	Died Status = "died"
+	// Exec ...
+	Exec Status = "exec"
+	// Export ...
+	Export Status = "export"
+	// Import ...
+	Import Status = "import"
+	// Init ...
+	Init Status = "init"
+	// Kill ...
+	Kill Status = "kill"
+	// Mount ...
+	Mount Status = "mount"
+	// Pause ...
+	Pause Status = "pause"
+	// Pull ...
+	Pull Status = "pull"
+	// Push ...
+	Push Status = "push"
+	// Remove ...
+	Remove Status = "remove"
+	// Rename ...
+	Rename Status = "rename"
+	// Restart ...
+	Restart Status = "restart"
+	// Restore ...
+	Restore Status = "restore"
+	// Start ...
+	Start Status = "start"
+	// Stop ...
+	Stop Status = "stop"
+	// Tag ...
+	Tag Status = "tag"
+	// Unmount ...
+	Unmount Status = "unmount"
+	// Unpause ...
+	Unpause Status = "unpause"
+	// Untag ...
+	Untag Status = "untag"
+	// Update ...
+	Update Status = "update"
+)
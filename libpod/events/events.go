@@ -0,0 +1,132 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNoJournaldLogging indicates that events can not be read back from the
+// systemd journal
+var ErrNoJournaldLogging = errors.New("reading events from journald is not supported")
+
+// NewEventer creates an eventer based on the given options
+func NewEventer(options EventerOptions) (Eventer, error) {
+	if options.LogFilePath == "" {
+		return nil, errors.Errorf("a path to the events log file is required")
+	}
+	logFile := EventLogFile{options}
+	if !options.Journald {
+		return logFile, nil
+	}
+	return multiEventer{
+		reader:  logFile,
+		writers: []Eventer{logFile, EventJournalD{options}},
+	}, nil
+}
+
+// multiEventer writes events to several backends, and reads them from one
+type multiEventer struct {
+	reader  Eventer
+	writers []Eventer
+}
+
+// Write an event to all backends
+func (m multiEventer) Write(ee Event) error {
+	var lastErr error
+	for _, w := range m.writers {
+		if err := w.Write(ee); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Read events from the primary backend
+func (m multiEventer) Read(options ReadOptions) error {
+	return m.reader.Read(options)
+}
+
+// NewEvent creates an event struct and populates with
+// the given status and time.
+func NewEvent(status Status) Event {
+	return Event{
+		Status: status,
+		Time:   time.Now(),
+	}
+}
+
+// ToJSONString returns the event as a json'ified string
+func (e *Event) ToJSONString() (string, error) {
+	b, err := json.Marshal(e)
+	return string(b), err
+}
+
+// ToHumanReadable returns human readable event output
+func (e *Event) ToHumanReadable() string {
+	humanFormat := fmt.Sprintf("%s %s %s %s", e.Time, e.Type, e.Status, e.ID)
+	attrs := make([]string, 0, len(e.Attributes)+1)
+	if e.Name != "" {
+		attrs = append(attrs, fmt.Sprintf("name=%s", e.Name))
+	}
+	keys := make([]string, 0, len(e.Attributes))
+	for k := range e.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, fmt.Sprintf("%s=%s", k, e.Attributes[k]))
+	}
+	if len(attrs) > 0 {
+		humanFormat += fmt.Sprintf(" (%s)", strings.Join(attrs, ", "))
+	}
+	return humanFormat
+}
+
+// newEventFromJSONString takes json and converts it to an event
+func newEventFromJSONString(event string) (*Event, error) {
+	e := Event{}
+	if err := json.Unmarshal([]byte(event), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// String converts a Type to a string
+func (t Type) String() string {
+	return string(t)
+}
+
+// String converts a Status to a string
+func (s Status) String() string {
+	return string(s)
+}
+
+// StringToType converts string to an EventType
+func StringToType(name string) (Type, error) {
+	switch name {
+	case Container.String():
+		return Container, nil
+	case Image.String():
+		return Image, nil
+	case Pod.String():
+		return Pod, nil
+	case Volume.String():
+		return Volume, nil
+	}
+	return "", errors.Errorf("unknown event type %q", name)
+}
+
+// StringToStatus converts a string to an Event Status
+func StringToStatus(name string) (Status, error) {
+	for _, s := range []Status{Attach, Checkpoint, Cleanup, Commit, Connect, Create, Died, Disconnect, Exec, Export, Import, Init, Kill, Mount, Pause, Pull, Push, Remove, Rename, Restart, Restore, Start, Stop, Tag, Unmount, Unpause, Untag, Update} {
+		if name == s.String() {
+			return s, nil
+		}
+	}
+	return "", errors.Errorf("unknown event status %q", name)
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(status Status, eventType Type, id, name string) Event {
+	e := NewEvent(status)
+	e.ID = id
+	e.Name = name
+	e.Type = eventType
+	return e
+}
+
+func TestEventFilters(t *testing.T) {
+	ctr := newTestEvent(Start, Container, "0123456789abcdef", "ctr1")
+	ctr.Attributes = map[string]string{"image": "alpine", "pod": "pod1"}
+	vol := newTestEvent(Create, Volume, "", "vol1")
+
+	for _, test := range []struct {
+		filters  []string
+		ctrMatch bool
+		volMatch bool
+	}{
+		{nil, true, true},
+		{[]string{"container=ctr1"}, true, false},
+		{[]string{"container=0123"}, true, false},
+		{[]string{"event=start"}, true, false},
+		{[]string{"status=create"}, false, true},
+		{[]string{"image=alpine"}, true, false},
+		{[]string{"pod=pod1"}, true, false},
+		{[]string{"volume=vol1"}, false, true},
+		{[]string{"type=volume"}, false, true},
+		{[]string{"type=container", "event=stop"}, false, false},
+	} {
+		options, err := generateEventOptions(test.filters, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, test.ctrMatch, shouldSendEvent(&ctr, options), "container event with filters %v", test.filters)
+		assert.Equal(t, test.volMatch, shouldSendEvent(&vol, options), "volume event with filters %v", test.filters)
+	}
+}
+
+func TestEventFiltersInvalid(t *testing.T) {
+	_, err := generateEventOptions([]string{"foo"}, "", "")
+	assert.Error(t, err)
+	_, err = generateEventOptions([]string{"foo=bar"}, "", "")
+	assert.Error(t, err)
+	_, err = generateEventOptions(nil, "not a time", "")
+	assert.Error(t, err)
+}
+
+func TestEventLogFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	eventer, err := NewEventer(EventerOptions{LogFilePath: filepath.Join(tmpDir, "events", "events.log")})
+	require.NoError(t, err)
+
+	old := newTestEvent(Create, Container, "abc", "old")
+	old.Time = time.Now().Add(-time.Hour)
+	require.NoError(t, eventer.Write(old))
+	require.NoError(t, eventer.Write(newTestEvent(Pull, Image, "def", "alpine")))
+	require.NoError(t, eventer.Write(newTestEvent(Start, Container, "abc", "old")))
+
+	read := func(filters []string, since string) []*Event {
+		eventChannel := make(chan *Event)
+		errChannel := make(chan error)
+		go func() {
+			errChannel <- eventer.Read(ReadOptions{
+				EventChannel: eventChannel,
+				Filters:      filters,
+				Since:        since,
+			})
+		}()
+		var got []*Event
+		for e := range eventChannel {
+			got = append(got, e)
+		}
+		require.NoError(t, <-errChannel)
+		return got
+	}
+
+	assert.Len(t, read(nil, ""), 3)
+
+	got := read([]string{"type=container"}, "30m")
+	require.Len(t, got, 1)
+	assert.Equal(t, Start, got[0].Status)
+	assert.Equal(t, "old", got[0].Name)
+
+	got = read([]string{"type=image"}, "")
+	require.Len(t, got, 1)
+	assert.Equal(t, "alpine", got[0].Name)
+}
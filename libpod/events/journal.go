@@ -0,0 +1,83 @@
+package events
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// journalSocket is the socket of the native journald protocol
+const journalSocket = "/run/systemd/journal/socket"
+
+// EventJournalD is the journald implementation of an eventer. Events can
+// only be written to the journal; they are read back from the events log
+// file.
+type EventJournalD struct {
+	options EventerOptions
+}
+
+// Write sends an event to the systemd journal
+func (e EventJournalD) Write(ee Event) error {
+	fields := map[string]string{
+		"MESSAGE":           ee.ToHumanReadable(),
+		"PRIORITY":          "6",
+		"SYSLOG_IDENTIFIER": "podman",
+		"PODMAN_EVENT":      ee.Status.String(),
+		"PODMAN_TYPE":       ee.Type.String(),
+		"PODMAN_TIME":       ee.Time.Format("2006-01-02T15:04:05.999999999Z07:00"),
+	}
+	if ee.ID != "" {
+		fields["PODMAN_ID"] = ee.ID
+	}
+	if ee.Name != "" {
+		fields["PODMAN_NAME"] = ee.Name
+	}
+	for k, v := range ee.Attributes {
+		fields["PODMAN_"+journalFieldName(k)] = v
+	}
+
+	var data bytes.Buffer
+	for k, v := range fields {
+		if !strings.Contains(v, "\n") {
+			fmt.Fprintf(&data, "%s=%s\n", k, v)
+			continue
+		}
+		// Values containing newlines must be sent with their length
+		// as a little endian 64 bit integer
+		fmt.Fprintf(&data, "%s\n", k)
+		if err := binary.Write(&data, binary.LittleEndian, uint64(len(v))); err != nil {
+			return err
+		}
+		fmt.Fprintf(&data, "%s\n", v)
+	}
+
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write(data.Bytes())
+	return err
+}
+
+// Read is not supported for journald, events are read from the log file
+func (e EventJournalD) Read(options ReadOptions) error {
+	close(options.EventChannel)
+	return ErrNoJournaldLogging
+}
+
+// journalFieldName converts an attribute name to a valid journal field name,
+// which may only contain uppercase letters, digits and underscores
+func journalFieldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
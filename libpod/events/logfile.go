@@ -0,0 +1,104 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hpcloud/tail"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// EventLogFile is the structure for event writing to a logfile. It contains
+// the eventer options and the event itself. Methods for reading and writing
+// are also defined.
+type EventLogFile struct {
+	options EventerOptions
+}
+
+// Write appends an event to the logfile, creating it if needed
+func (e EventLogFile) Write(ee Event) error {
+	if err := os.MkdirAll(filepath.Dir(e.options.LogFilePath), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for events log file %s", e.options.LogFilePath)
+	}
+	f, err := os.OpenFile(e.options.LogFilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0700)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	eventJSONString, err := ee.ToJSONString()
+	if err != nil {
+		return err
+	}
+	// A single write with O_APPEND keeps lines from concurrent writers
+	// from being interleaved
+	if _, err := f.WriteString(fmt.Sprintf("%s\n", eventJSONString)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Read reads events from the logfile and sends the ones matching the given
+// options to the event channel
+func (e EventLogFile) Read(options ReadOptions) error {
+	defer close(options.EventChannel)
+
+	eventOptions, err := generateEventOptions(options.Filters, options.Since, options.Until)
+	if err != nil {
+		return errors.Wrapf(err, "unable to generate event options")
+	}
+
+	if _, err := os.Stat(e.options.LogFilePath); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		// No events have been written yet
+		if !options.Stream {
+			return nil
+		}
+	}
+
+	t, err := tail.TailFile(e.options.LogFilePath, tail.Config{
+		ReOpen: options.Stream,
+		Follow: options.Stream,
+		Logger: tail.DiscardingLogger,
+	})
+	if err != nil {
+		return err
+	}
+	defer t.Cleanup()
+
+	if options.Stream && options.Until != "" {
+		// Stop following the log once the until time has passed
+		untilOption, _ := generateEventOptions(nil, "", options.Until)
+		go func() {
+			for {
+				if shouldSendEvent(&Event{Time: time.Now()}, untilOption) {
+					time.Sleep(time.Second)
+					continue
+				}
+				if err := t.Stop(); err != nil {
+					logrus.Debugf("error stopping events log tail: %v", err)
+				}
+				return
+			}
+		}()
+	}
+
+	for line := range t.Lines {
+		if line.Err != nil {
+			return line.Err
+		}
+		event, err := newEventFromJSONString(line.Text)
+		if err != nil {
+			logrus.Errorf("unable to parse event %q: %v", line.Text, err)
+			continue
+		}
+		if shouldSendEvent(event, eventOptions) {
+			options.EventChannel <- event
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package libpod
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/libpod/pkg/inspect"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// HealthCheckStatus represents the result of running a healthcheck
+type HealthCheckStatus int
+
+const (
+	// HealthCheckSuccess means the healthcheck command returned 0
+	HealthCheckSuccess HealthCheckStatus = iota
+	// HealthCheckFailure means the healthcheck command returned non-zero
+	// or did not finish within its timeout
+	HealthCheckFailure HealthCheckStatus = iota
+	// HealthCheckContainerStopped means the healthcheck could not be run
+	// because the container is not running
+	HealthCheckContainerStopped HealthCheckStatus = iota
+	// HealthCheckContainerNotFound means the container could not be found
+	HealthCheckContainerNotFound HealthCheckStatus = iota
+	// HealthCheckNotDefined means the container has no healthcheck
+	HealthCheckNotDefined HealthCheckStatus = iota
+	// HealthCheckInternalError means an error occurred while running the
+	// healthcheck
+	HealthCheckInternalError HealthCheckStatus = iota
+)
+
+const (
+	// HealthCheckHealthy is the status of a container whose most recent
+	// healthcheck succeeded
+	HealthCheckHealthy string = "healthy"
+	// HealthCheckUnhealthy is the status of a container whose healthcheck
+	// has failed more times in a row than it is allowed to
+	HealthCheckUnhealthy string = "unhealthy"
+	// HealthCheckStarting is the status of a container whose healthcheck
+	// has not yet been run or succeeded since the container was started
+	HealthCheckStarting string = "starting"
+
+	// MaxHealthCheckNumberLogs is the maximum number of healthcheck
+	// results kept in the container state
+	MaxHealthCheckNumberLogs int = 5
+	// MaxHealthCheckLogLength is the maximum length, in bytes, of the
+	// output of a single healthcheck kept in the container state
+	MaxHealthCheckLogLength = 500
+
+	// DefaultHealthCheckRetries is the number of consecutive failures
+	// needed to mark a container unhealthy if none was configured
+	DefaultHealthCheckRetries = 3
+)
+
+// String returns a human readable description of the healthcheck result
+func (s HealthCheckStatus) String() string {
+	switch s {
+	case HealthCheckSuccess:
+		return HealthCheckHealthy
+	case HealthCheckFailure:
+		return HealthCheckUnhealthy
+	case HealthCheckContainerStopped:
+		return "container not running"
+	case HealthCheckContainerNotFound:
+		return "container not found"
+	case HealthCheckNotDefined:
+		return "no healthcheck defined"
+	}
+	return "internal error"
+}
+
+// bufferCloser is a bytes.Buffer that satisfies io.WriteCloser, so it can
+// be used to capture the output of an exec session
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+// Close is a no-op
+func (b *bufferCloser) Close() error {
+	return nil
+}
+
+// HealthCheck runs the healthcheck of the given container once, records the
+// result in the container's state and returns it
+func (r *Runtime) HealthCheck(name string) (HealthCheckStatus, error) {
+	container, err := r.LookupContainer(name)
+	if err != nil {
+		return HealthCheckContainerNotFound, errors.Wrapf(err, "unable to lookup %s to perform a healthcheck", name)
+	}
+	return container.runHealthCheck()
+}
+
+// healthCheckCommand converts the Test of a healthcheck into a command that
+// can be executed in the container. A nil command means the healthcheck is
+// disabled.
+func healthCheckCommand(test []string) []string {
+	if len(test) == 0 {
+		return nil
+	}
+	switch test[0] {
+	case "NONE":
+		return nil
+	case "CMD":
+		return test[1:]
+	case "CMD-SHELL":
+		return []string{"/bin/sh", "-c", strings.Join(test[1:], " ")}
+	}
+	return test
+}
+
+// runHealthCheck executes the healthcheck of the container and updates the
+// healthcheck results in the container's state
+func (c *Container) runHealthCheck() (HealthCheckStatus, error) {
+	hcConfig := c.HealthCheckConfig()
+	if hcConfig == nil {
+		return HealthCheckNotDefined, errors.Errorf("container %s has no defined healthcheck", c.ID())
+	}
+	hcCommand := healthCheckCommand(hcConfig.Test)
+	if len(hcCommand) == 0 {
+		return HealthCheckNotDefined, errors.Errorf("container %s has no defined healthcheck", c.ID())
+	}
+
+	state, err := c.State()
+	if err != nil {
+		return HealthCheckInternalError, err
+	}
+	if state != ContainerStateRunning {
+		return HealthCheckContainerStopped, errors.Wrapf(ErrCtrStateInvalid, "container %s is not running", c.ID())
+	}
+
+	output := new(bufferCloser)
+	streams := &AttachStreams{
+		OutputStream: output,
+		ErrorStream:  output,
+		AttachOutput: true,
+		AttachError:  true,
+	}
+
+	sessionID, err := c.ExecCreate(&ExecConfig{Command: hcCommand})
+	if err != nil {
+		return HealthCheckInternalError, errors.Wrapf(err, "unable to create healthcheck session in container %s", c.ID())
+	}
+	defer func() {
+		if err := c.ExecRemove(sessionID); err != nil {
+			logrus.Errorf("Error removing exec session %s from container %s state: %v", sessionID, c.ID(), err)
+		}
+	}()
+
+	logrus.Debugf("executing healthcheck command %v in container %s", hcCommand, c.ID())
+	timeStart := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.execStart(sessionID, streams, nil)
+	}()
+
+	var timeout <-chan time.Time
+	if hcConfig.Timeout > 0 {
+		timer := time.NewTimer(hcConfig.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var hcErr error
+	timedOut := false
+	select {
+	case hcErr = <-done:
+	case <-timeout:
+		timedOut = true
+		hcErr = c.killHealthCheck(sessionID, done)
+	}
+	timeEnd := time.Now()
+
+	hcResult := HealthCheckSuccess
+	exitCode := 0
+	if timedOut {
+		hcResult = HealthCheckFailure
+		exitCode = 1
+		fmt.Fprintf(output, "healthcheck command exceeded timeout of %s", hcConfig.Timeout.String())
+	} else if hcErr != nil {
+		hcResult = HealthCheckFailure
+		exitCode = 1
+		if exitErr, ok := errors.Cause(hcErr).(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				exitCode = status.ExitStatus()
+			}
+		} else {
+			// The healthcheck could not be executed at all
+			fmt.Fprintf(output, "%v", hcErr)
+		}
+	}
+
+	hcLog := output.String()
+	if len(hcLog) > MaxHealthCheckLogLength {
+		hcLog = hcLog[:MaxHealthCheckLogLength]
+	}
+	result := inspect.HealthCheckLog{
+		Start:    timeStart.Format(time.RFC3339Nano),
+		End:      timeEnd.Format(time.RFC3339Nano),
+		ExitCode: exitCode,
+		Output:   hcLog,
+	}
+
+	if err := c.updateHealthCheckResults(result, hcResult == HealthCheckSuccess); err != nil {
+		return HealthCheckInternalError, errors.Wrapf(err, "unable to update health check results for container %s", c.ID())
+	}
+	return hcResult, nil
+}
+
+// killHealthCheck kills the command of a healthcheck that exceeded its
+// timeout, and returns the error its exec session exited with. The command
+// may not have been started yet, so killing it is retried until it is killed
+// or the session exits.
+func (c *Container) killHealthCheck(sessionID string, done <-chan error) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		err := c.execKill(sessionID, syscall.SIGKILL)
+		if err == nil {
+			return <-done
+		}
+		logrus.Debugf("unable to kill healthcheck session %s in container %s: %v", sessionID, c.ID(), err)
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateHealthCheckResults records the result of a healthcheck in the state
+// of the container and updates its health status
+func (c *Container) updateHealthCheckResults(result inspect.HealthCheckLog, success bool) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	if c.state.HealthCheck == nil {
+		c.state.HealthCheck = &inspect.HealthCheckResults{
+			Status: HealthCheckStarting,
+		}
+	}
+	results := c.state.HealthCheck
+
+	if success {
+		results.FailingStreak = 0
+		results.Status = HealthCheckHealthy
+	} else if !c.inHealthCheckStartPeriod() {
+		// Failures during the start period do not count towards the
+		// number of retries
+		results.FailingStreak++
+		retries := c.config.HealthCheckConfig.Retries
+		if retries <= 0 {
+			retries = DefaultHealthCheckRetries
+		}
+		if results.FailingStreak >= retries {
+			results.Status = HealthCheckUnhealthy
+		}
+	}
+
+	results.Log = append(results.Log, result)
+	if len(results.Log) > MaxHealthCheckNumberLogs {
+		results.Log = results.Log[len(results.Log)-MaxHealthCheckNumberLogs:]
+	}
+
+	return c.save()
+}
+
+// inHealthCheckStartPeriod returns whether the container is still within the
+// start period of its healthcheck. Must be called with the container locked.
+func (c *Container) inHealthCheckStartPeriod() bool {
+	startPeriod := c.config.HealthCheckConfig.StartPeriod
+	if startPeriod <= 0 {
+		return false
+	}
+	return time.Since(c.state.StartedTime) < startPeriod
+}
+
+// HealthCheckStatus returns the current health status of the container:
+// "healthy", "unhealthy" or "starting". If the container has no healthcheck
+// or has not been started, an empty string is returned.
+func (c *Container) HealthCheckStatus() (string, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return "", errors.Wrapf(err, "error updating container %s state", c.ID())
+		}
+	}
+
+	if c.state.HealthCheck == nil {
+		return "", nil
+	}
+	return c.state.HealthCheck.Status, nil
+}
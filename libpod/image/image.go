@@ -20,6 +20,7 @@ import (
 	"github.com/containers/image/types"
 	"github.com/containers/libpod/libpod/common"
 	"github.com/containers/libpod/libpod/driver"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/inspect"
 	"github.com/containers/libpod/pkg/registries"
 	"github.com/containers/libpod/pkg/util"
@@ -58,6 +59,9 @@ type Image struct {
 type Runtime struct {
 	store               storage.Store
 	SignaturePolicyPath string
+	// Eventer is used to record image events. It may be nil, in which
+	// case no events are recorded.
+	Eventer events.Eventer
 }
 
 // ErrRepoTagNotFound is the error returned when the image id given doesn't match a rep tag in store
@@ -156,6 +160,7 @@ func (ir *Runtime) New(ctx context.Context, name, signaturePolicyPath, authfile
 		return nil, errors.Wrapf(err, "error retrieving local image after pulling %s", name)
 	}
 	newImage.image = img
+	ir.newImageEvent(events.Pull, img.ID, name)
 	return &newImage, nil
 }
 
@@ -184,11 +189,27 @@ func (ir *Runtime) LoadFromArchiveReference(ctx context.Context, srcRef types.Im
 		}
 		newImage.image = img
 		newImages = append(newImages, &newImage)
+		ir.newImageEvent(events.Pull, img.ID, name)
 	}
 
 	return newImages, nil
 }
 
+// newImageEvent records an event for the image with the given ID and name,
+// if the runtime has an eventer
+func (ir *Runtime) newImageEvent(status events.Status, id, name string) {
+	if ir.Eventer == nil {
+		return
+	}
+	e := events.NewEvent(status)
+	e.Type = events.Image
+	e.ID = id
+	e.Name = name
+	if err := ir.Eventer.Write(e); err != nil {
+		logrus.Errorf("unable to write image event: %q", err)
+	}
+}
+
 // Shutdown closes down the storage and require a bool arg as to
 // whether it should do so forcibly.
 func (ir *Runtime) Shutdown(force bool) error {
@@ -339,6 +360,7 @@ func (i *Image) Remove(force bool) error {
 	if _, err := i.imageruntime.store.DeleteImage(i.ID(), true); err != nil {
 		return err
 	}
+	i.imageruntime.newImageEvent(events.Remove, i.ID(), i.InputName)
 	for parent != nil {
 		nextParent, err := parent.GetParent()
 		if err != nil {
@@ -484,6 +506,7 @@ func (i *Image) TagImage(tag string) error {
 		return err
 	}
 	i.reloadImage()
+	i.imageruntime.newImageEvent(events.Tag, i.ID(), ref.String())
 	return nil
 }
 
@@ -504,6 +527,7 @@ func (i *Image) UntagImage(tag string) error {
 		return err
 	}
 	i.reloadImage()
+	i.imageruntime.newImageEvent(events.Untag, i.ID(), tag)
 	return nil
 }
 
@@ -552,6 +576,7 @@ func (i *Image) PushImageToReference(ctx context.Context, dest types.ImageRefere
 	if err != nil {
 		return errors.Wrapf(err, "Error copying image to the remote destination")
 	}
+	i.imageruntime.newImageEvent(events.Push, i.ID(), transports.ImageName(dest))
 	return nil
 }
 
@@ -643,6 +668,31 @@ func (i *Image) Layer() (*storage.Layer, error) {
 	return i.imageruntime.store.Layer(i.image.TopLayer)
 }
 
+// LayerSizes returns the IDs of all the layers of the image, from the top layer
+// down to the base layer, mapped to their uncompressed sizes. Layers can be
+// shared with other images.
+func (i *Image) LayerSizes() (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	for layerID := i.TopLayer(); layerID != ""; {
+		layer, err := i.imageruntime.store.Layer(layerID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting layer info %q", layerID)
+		}
+		size := layer.UncompressedSize
+		// The uncompressed size is only valid if the digest of the
+		// diff is known, so compute it otherwise
+		if layer.UncompressedDigest == "" {
+			size, err = i.imageruntime.store.DiffSize(layer.Parent, layer.ID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error getting size of layer %q", layerID)
+			}
+		}
+		sizes[layer.ID] = size
+		layerID = layer.Parent
+	}
+	return sizes, nil
+}
+
 // History contains the history information of an image
 type History struct {
 	ID        string     `json:"id"`
@@ -784,6 +834,28 @@ func (i *Image) ociv1Image(ctx context.Context) (*ociv1.Image, error) {
 	return imgRef.OCIConfig(ctx)
 }
 
+// GetHealthCheck returns the HEALTHCHECK configuration of an image, if the
+// image was built with one.  OCI images do not carry healthchecks, so this
+// is only ever non-nil for images in the Docker format.
+func (i *Image) GetHealthCheck(ctx context.Context) (*manifest.Schema2HealthConfig, error) {
+	imgRef, err := i.toImageRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+	configBlob, err := imgRef.ConfigBlob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dockerImage := manifest.Schema2Image{}
+	if err := json.Unmarshal(configBlob, &dockerImage); err != nil {
+		return nil, err
+	}
+	if dockerImage.Config == nil {
+		return nil, nil
+	}
+	return dockerImage.Config.Healthcheck, nil
+}
+
 func (i *Image) imageInspectInfo(ctx context.Context) (*types.ImageInspectInfo, error) {
 	if i.inspectInfo == nil {
 		sr, err := i.toStorageReference()
@@ -913,7 +985,12 @@ func (ir *Runtime) Import(ctx context.Context, path, reference string, writer io
 	if err != nil {
 		return nil, err
 	}
-	return ir.NewFromLocal(reference)
+	newImage, err := ir.NewFromLocal(reference)
+	if err != nil {
+		return nil, err
+	}
+	ir.newImageEvent(events.Import, newImage.ID(), reference)
+	return newImage, nil
 }
 
 // MatchRepoTag takes a string and tries to match it against an
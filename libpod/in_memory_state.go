@@ -354,6 +354,75 @@ func (s *InMemoryState) SaveContainer(ctr *Container) error {
 	return s.checkNSMatch(ctr.ID(), ctr.Namespace())
 }
 
+// RewriteContainerConfig replaces the configuration of a container
+func (s *InMemoryState) RewriteContainerConfig(ctr *Container, newCfg *ContainerConfig) error {
+	if !ctr.valid {
+		return errors.Wrapf(ErrCtrRemoved, "container with ID %s is not valid", ctr.ID())
+	}
+
+	stateCtr, ok := s.containers[ctr.ID()]
+	if !ok {
+		ctr.valid = false
+		return errors.Wrapf(ErrNoSuchCtr, "container with ID %s not found in state", ctr.ID())
+	}
+
+	if err := s.checkNSMatch(ctr.ID(), ctr.Namespace()); err != nil {
+		return err
+	}
+
+	if err := checkConfigRewrite(ctr.config, newCfg); err != nil {
+		return err
+	}
+
+	stateCtr.config = newCfg
+	ctr.config = newCfg
+
+	return nil
+}
+
+// RenameContainer changes the name of a container
+func (s *InMemoryState) RenameContainer(ctr *Container, newName string) error {
+	if !ctr.valid {
+		return errors.Wrapf(ErrCtrRemoved, "container with ID %s is not valid", ctr.ID())
+	}
+
+	if _, ok := s.containers[ctr.ID()]; !ok {
+		ctr.valid = false
+		return errors.Wrapf(ErrNoSuchCtr, "container with ID %s not found in state", ctr.ID())
+	}
+
+	if err := s.checkNSMatch(ctr.ID(), ctr.Namespace()); err != nil {
+		return err
+	}
+
+	oldName := ctr.Name()
+	if newName == oldName {
+		return nil
+	}
+
+	if err := s.nameIndex.Reserve(newName, ctr.ID()); err != nil {
+		return errors.Wrapf(ErrCtrExists, "name %s is in use", newName)
+	}
+
+	if ctr.config.Namespace != "" {
+		nsIndex, ok := s.namespaceIndexes[ctr.config.Namespace]
+		if !ok {
+			s.nameIndex.Release(newName)
+			return errors.Wrapf(ErrInternal, "error retrieving index for namespace %q", ctr.config.Namespace)
+		}
+		if err := nsIndex.nameIndex.Reserve(newName, ctr.ID()); err != nil {
+			s.nameIndex.Release(newName)
+			return errors.Wrapf(err, "error registering container name %s", newName)
+		}
+		nsIndex.nameIndex.Release(oldName)
+	}
+	s.nameIndex.Release(oldName)
+
+	ctr.config.Name = newName
+
+	return nil
+}
+
 // ContainerInUse checks if the given container is being used by other containers
 func (s *InMemoryState) ContainerInUse(ctr *Container) ([]string, error) {
 	if !ctr.valid {
@@ -476,6 +545,36 @@ func (s *InMemoryState) VolumeInUse(volume *Volume) ([]string, error) {
 	return arr, nil
 }
 
+// UpdateVolume updates a volume from the state
+// At present this is a no-op, as there is no backing store
+func (s *InMemoryState) UpdateVolume(volume *Volume) error {
+	if !volume.valid {
+		return ErrVolumeRemoved
+	}
+
+	if _, ok := s.volumes[volume.Name()]; !ok {
+		volume.valid = false
+		return errors.Wrapf(ErrNoSuchVolume, "no volume exists in state with name %s", volume.Name())
+	}
+
+	return nil
+}
+
+// SaveVolume saves a volume's state
+// At present this is a no-op, as there is no backing store
+func (s *InMemoryState) SaveVolume(volume *Volume) error {
+	if !volume.valid {
+		return ErrVolumeRemoved
+	}
+
+	if _, ok := s.volumes[volume.Name()]; !ok {
+		volume.valid = false
+		return errors.Wrapf(ErrNoSuchVolume, "no volume exists in state with name %s", volume.Name())
+	}
+
+	return nil
+}
+
 // AllVolumes returns all volumes that exist in the state
 func (s *InMemoryState) AllVolumes() ([]*Volume, error) {
 	allVols := make([]*Volume, 0, len(s.volumes))
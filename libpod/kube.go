@@ -3,6 +3,8 @@ package libpod
 import (
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -130,35 +132,42 @@ func containersToServicePorts(containers []v1.Container) []v1.ServicePort {
 func (p *Pod) podWithContainers(containers []*Container, ports []v1.ContainerPort) (*v1.Pod, error) {
 	var (
 		podContainers []v1.Container
+		restartPolicy v1.RestartPolicy
 	)
+	volumes := newKubeVolumes()
 	first := true
 	for _, ctr := range containers {
 		if !ctr.IsInfra() {
-			result, err := containerToV1Container(ctr)
+			result, err := containerToV1Container(ctr, volumes)
 			if err != nil {
 				return nil, err
 			}
 			// We add the original port declarations from the libpod infra container
 			// to the first kubernetes container description because otherwise we loose
 			// the original container/port bindings.
-			if first && len(ports) > 0 {
-				result.Ports = ports
+			if first {
+				if len(ports) > 0 {
+					result.Ports = ports
+				}
+				// Kube only knows about restart policies for the whole pod, so
+				// the policy of the first container is used
+				restartPolicy = libpodRestartPolicyToKube(ctr.RestartPolicy())
 				first = false
 			}
 			podContainers = append(podContainers, result)
 		}
 	}
-	return addContainersToPodObject(podContainers, p.Name()), nil
+	return addContainersToPodObject(podContainers, volumes.volumes, p.Name(), p.Labels(), restartPolicy), nil
 }
 
-func addContainersToPodObject(containers []v1.Container, podName string) *v1.Pod {
+func addContainersToPodObject(containers []v1.Container, volumes []v1.Volume, podName string, podLabels map[string]string, restartPolicy v1.RestartPolicy) *v1.Pod {
 	tm := v12.TypeMeta{
 		Kind:       "Pod",
 		APIVersion: "v1",
 	}
 
 	// Add a label called "app" with the containers name as a value
-	labels := make(map[string]string)
+	labels := libpodLabelsToKubeLabels(podLabels)
 	labels["app"] = removeUnderscores(podName)
 	om := v12.ObjectMeta{
 		// The name of the pod is container_name-libpod
@@ -170,7 +179,9 @@ func addContainersToPodObject(containers []v1.Container, podName string) *v1.Pod
 		CreationTimestamp: v12.Now(),
 	}
 	ps := v1.PodSpec{
-		Containers: containers,
+		Containers:    containers,
+		Volumes:       volumes,
+		RestartPolicy: restartPolicy,
 	}
 	p := v1.Pod{
 		TypeMeta:   tm,
@@ -184,18 +195,20 @@ func addContainersToPodObject(containers []v1.Container, podName string) *v1.Pod
 // for a single container.  we "insert" that container description in a pod.
 func simplePodWithV1Container(ctr *Container) (*v1.Pod, error) {
 	var containers []v1.Container
-	result, err := containerToV1Container(ctr)
+	volumes := newKubeVolumes()
+	result, err := containerToV1Container(ctr, volumes)
 	if err != nil {
 		return nil, err
 	}
 	containers = append(containers, result)
-	return addContainersToPodObject(containers, ctr.Name()), nil
+	return addContainersToPodObject(containers, volumes.volumes, ctr.Name(), ctr.Labels(), libpodRestartPolicyToKube(ctr.RestartPolicy())), nil
 
 }
 
 // containerToV1Container converts information we know about a libpod container
-// to a V1.Container specification.
-func containerToV1Container(c *Container) (v1.Container, error) {
+// to a V1.Container specification. The volumes used by the container are
+// added to volumes.
+func containerToV1Container(c *Container, volumes *kubeVolumes) (v1.Container, error) {
 	kubeContainer := v1.Container{}
 	kubeSec, err := generateKubeSecurityContext(c)
 	if err != nil {
@@ -212,15 +225,9 @@ func containerToV1Container(c *Container) (v1.Container, error) {
 		return kubeContainer, errors.Wrapf(ErrNotImplemented, "linux devices")
 	}
 
-	if len(c.config.UserVolumes) > 0 {
-		// TODO When we until we can resolve what the volume name should be, this is disabled
-		// Volume names need to be coordinated "globally" in the kube files.
-		volumes, err := libpodMountsToKubeVolumeMounts(c)
-		if err != nil {
-			return kubeContainer, err
-		}
-		kubeContainer.VolumeMounts = volumes
-		return kubeContainer, errors.Wrapf(ErrNotImplemented, "volume names")
+	volumeMounts, err := libpodMountsToKubeVolumeMounts(c, volumes)
+	if err != nil {
+		return kubeContainer, err
 	}
 
 	envVariables, err := libpodEnvVarsToKubeEnvVars(c.config.Spec.Process.Env)
@@ -233,6 +240,8 @@ func containerToV1Container(c *Container) (v1.Container, error) {
 		return kubeContainer, nil
 	}
 
+	limits, requests := libpodMaxAndMinToResourceList(c)
+
 	containerCommands := c.Command()
 	kubeContainer.Name = removeUnderscores(c.Name())
 
@@ -248,8 +257,11 @@ func containerToV1Container(c *Container) (v1.Container, error) {
 	// This should not be applicable
 	//container.EnvFromSource =
 	kubeContainer.Env = envVariables
-	// TODO enable resources when we can support naming conventions
-	//container.Resources
+	kubeContainer.VolumeMounts = volumeMounts
+	kubeContainer.Resources = v1.ResourceRequirements{
+		Limits:   limits,
+		Requests: requests,
+	}
 	kubeContainer.SecurityContext = kubeSec
 	kubeContainer.StdinOnce = false
 	kubeContainer.TTY = c.config.Spec.Process.Terminal
@@ -300,52 +312,245 @@ func libpodEnvVarsToKubeEnvVars(envs []string) ([]v1.EnvVar, error) {
 	return envVars, nil
 }
 
-// Is this worth it?
-func libpodMaxAndMinToResourceList(c *Container) (v1.ResourceList, v1.ResourceList) { //nolint
-	// It does not appear we can properly calculate CPU resources from the information
-	// we know in libpod.  Libpod knows CPUs by time, shares, etc.
+// libpodMaxAndMinToResourceList converts the cgroup limits of a container
+// to kube resource limits and requests
+func libpodMaxAndMinToResourceList(c *Container) (v1.ResourceList, v1.ResourceList) {
+	maxResources := make(v1.ResourceList)
+	minResources := make(v1.ResourceList)
+	if c.config.Spec.Linux == nil || c.config.Spec.Linux.Resources == nil {
+		return maxResources, minResources
+	}
+	resources := c.config.Spec.Linux.Resources
 
-	// We also only know about a memory limit; no memory minimum
-	maxResources := make(map[v1.ResourceName]resource.Quantity)
-	minResources := make(map[v1.ResourceName]resource.Quantity)
-	config := c.Config()
-	maxMem := config.Spec.Linux.Resources.Memory.Limit
+	if resources.Memory != nil {
+		if resources.Memory.Limit != nil && *resources.Memory.Limit > 0 {
+			maxResources[v1.ResourceMemory] = *resource.NewQuantity(*resources.Memory.Limit, resource.BinarySI)
+		}
+		if resources.Memory.Reservation != nil && *resources.Memory.Reservation > 0 {
+			minResources[v1.ResourceMemory] = *resource.NewQuantity(*resources.Memory.Reservation, resource.BinarySI)
+		}
+	}
 
-	_ = maxMem
+	if resources.CPU != nil {
+		// The quota is the CPU time the container may use per period, which
+		// is what kube expects as a fraction of CPUs
+		if resources.CPU.Quota != nil && *resources.CPU.Quota > 0 && resources.CPU.Period != nil && *resources.CPU.Period > 0 {
+			milliCPU := *resources.CPU.Quota * 1000 / int64(*resources.CPU.Period)
+			maxResources[v1.ResourceCPU] = *resource.NewMilliQuantity(milliCPU, resource.DecimalSI)
+		}
+		// Kube grants 1024 CPU shares per requested CPU
+		if resources.CPU.Shares != nil && *resources.CPU.Shares > 0 {
+			milliCPU := int64(*resources.CPU.Shares) * 1000 / 1024
+			minResources[v1.ResourceCPU] = *resource.NewMilliQuantity(milliCPU, resource.DecimalSI)
+		}
+	}
+
+	// Kube rejects requests that exceed the limits
+	for name, min := range minResources {
+		if max, ok := maxResources[name]; ok && min.Cmp(max) > 0 {
+			minResources[name] = max
+		}
+	}
 
 	return maxResources, minResources
 }
 
-func generateKubeVolumeMount(hostSourcePath string, mounts []specs.Mount) (v1.VolumeMount, error) {
-	vm := v1.VolumeMount{}
-	for _, m := range mounts {
-		if m.Source == hostSourcePath {
-			// TODO Name is not provided and is required by Kube; therefore, this is disabled earlier
-			//vm.Name =
-			vm.MountPath = m.Source
-			vm.SubPath = m.Destination
-			if util.StringInSlice("ro", m.Options) {
-				vm.ReadOnly = true
+// libpodRestartPolicyToKube converts the restart policy of a container to the
+// restart policy of a kube pod
+func libpodRestartPolicyToKube(policy string) v1.RestartPolicy {
+	switch policy {
+	case RestartPolicyAlways, RestartPolicyUnlessStopped:
+		return v1.RestartPolicyAlways
+	case RestartPolicyOnFailure:
+		return v1.RestartPolicyOnFailure
+	default:
+		return v1.RestartPolicyNever
+	}
+}
+
+// kubeVolumes collects the volumes used by the containers of a kube pod, so
+// that every volume is only added to the pod spec once
+type kubeVolumes struct {
+	volumes []v1.Volume
+	// names maps the source of a volume to the name of its pod spec entry
+	names map[string]string
+	used  map[string]bool
+}
+
+func newKubeVolumes() *kubeVolumes {
+	return &kubeVolumes{
+		names: make(map[string]string),
+		used:  make(map[string]bool),
+	}
+}
+
+// add adds a volume with the given source to the pod spec, unless it already
+// is present, and returns the name of its entry
+func (k *kubeVolumes) add(key, baseName string, source v1.VolumeSource) string {
+	if name, ok := k.names[key]; ok {
+		return name
+	}
+	name := baseName
+	for i := 1; k.used[name]; i++ {
+		suffix := fmt.Sprintf("-%d", i)
+		name = sanitizeKubeName(baseName, kubeNameMaxLength-len(suffix)) + suffix
+	}
+	k.names[key] = name
+	k.used[name] = true
+	k.volumes = append(k.volumes, v1.Volume{
+		Name:         name,
+		VolumeSource: source,
+	})
+	return name
+}
+
+// userVolumeMounts returns the mounts of the container's spec that were added
+// by the user as volumes, with named volumes resolved to their mountpoints
+func (c *Container) userVolumeMounts() ([]specs.Mount, error) {
+	var mounts []specs.Mount
+	seen := make(map[string]bool)
+	for _, source := range c.config.UserVolumes {
+		if !filepath.IsAbs(source) {
+			volume, err := c.runtime.state.Volume(source)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error retrieving volume %s of container %s", source, c.ID())
+			}
+			source = volume.MountSource()
+		}
+		if seen[source] {
+			continue
+		}
+		seen[source] = true
+
+		found := false
+		for _, m := range c.config.Spec.Mounts {
+			if m.Source == source {
+				mounts = append(mounts, m)
+				found = true
 			}
-			return vm, nil
 		}
+		if !found {
+			return nil, errors.Errorf("unable to find mount source %s", source)
+		}
+	}
+	return mounts, nil
+}
+
+// generateKubeVolumeMount converts a mount of the container to a kube volume
+// mount, and adds the volume it refers to to volumes. Named volumes are
+// referred to by a PersistentVolumeClaim of the same name, everything else is
+// a hostPath volume.
+func (c *Container) generateKubeVolumeMount(m specs.Mount, volumes *kubeVolumes) v1.VolumeMount {
+	vm := v1.VolumeMount{
+		MountPath: m.Destination,
+		ReadOnly:  util.StringInSlice("ro", m.Options),
+	}
+	if volumeName, subPath, ok := c.namedVolumeFromSource(m.Source); ok {
+		claimName := sanitizeKubeName(volumeName, kubeNameMaxLength)
+		vm.Name = volumes.add("claim:"+claimName, claimName, v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+			},
+		})
+		vm.SubPath = subPath
+		return vm
+	}
+
+	hostPath := &v1.HostPathVolumeSource{
+		Path: m.Source,
+	}
+	if info, err := os.Stat(m.Source); err == nil {
+		hostPathType := v1.HostPathFile
+		if info.IsDir() {
+			hostPathType = v1.HostPathDirectory
+		}
+		hostPath.Type = &hostPathType
 	}
-	return vm, errors.New("unable to find mount source")
+	baseName := sanitizeKubeName(strings.Trim(m.Source, "/"), kubeNameMaxLength-len("-host")) + "-host"
+	vm.Name = volumes.add("host:"+m.Source, strings.TrimPrefix(baseName, "-"), v1.VolumeSource{
+		HostPath: hostPath,
+	})
+	return vm
 }
 
 // libpodMountsToKubeVolumeMounts converts the containers mounts to a struct kube understands
-func libpodMountsToKubeVolumeMounts(c *Container) ([]v1.VolumeMount, error) {
-	// At this point, I dont think we can distinguish between the default
-	// volume mounts and user added ones.  For now, we pass them all.
+func libpodMountsToKubeVolumeMounts(c *Container, volumes *kubeVolumes) ([]v1.VolumeMount, error) {
+	mounts, err := c.userVolumeMounts()
+	if err != nil {
+		return nil, err
+	}
 	var vms []v1.VolumeMount
-	for _, hostSourcePath := range c.config.UserVolumes {
-		vm, err := generateKubeVolumeMount(hostSourcePath, c.config.Spec.Mounts)
+	for _, m := range mounts {
+		vms = append(vms, c.generateKubeVolumeMount(m, volumes))
+	}
+	return vms, nil
+}
+
+// GenerateKubePersistentVolumeClaims generates a PersistentVolumeClaim for
+// each named volume used by the container
+func (c *Container) GenerateKubePersistentVolumeClaims() ([]*v1.PersistentVolumeClaim, error) {
+	return containersToKubePersistentVolumeClaims([]*Container{c})
+}
+
+// GenerateKubePersistentVolumeClaims generates a PersistentVolumeClaim for
+// each named volume used by the containers of the pod
+func (p *Pod) GenerateKubePersistentVolumeClaims() ([]*v1.PersistentVolumeClaim, error) {
+	allContainers, err := p.allContainers()
+	if err != nil {
+		return nil, err
+	}
+	return containersToKubePersistentVolumeClaims(allContainers)
+}
+
+func containersToKubePersistentVolumeClaims(containers []*Container) ([]*v1.PersistentVolumeClaim, error) {
+	var claims []*v1.PersistentVolumeClaim
+	seen := make(map[string]bool)
+	for _, ctr := range containers {
+		if ctr.IsInfra() {
+			continue
+		}
+		mounts, err := ctr.userVolumeMounts()
 		if err != nil {
-			return vms, err
+			return nil, err
+		}
+		for _, m := range mounts {
+			volumeName, _, ok := ctr.namedVolumeFromSource(m.Source)
+			if !ok || seen[volumeName] {
+				continue
+			}
+			seen[volumeName] = true
+			volume, err := ctr.runtime.state.Volume(volumeName)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error retrieving volume %s of container %s", volumeName, ctr.ID())
+			}
+			claims = append(claims, volumeToKubePersistentVolumeClaim(volume))
 		}
-		vms = append(vms, vm)
 	}
-	return vms, nil
+	return claims, nil
+}
+
+// volumeToKubePersistentVolumeClaim generates a PersistentVolumeClaim for a
+// named volume. Libpod volumes have no size, so a default request is used.
+func volumeToKubePersistentVolumeClaim(volume *Volume) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		TypeMeta: v12.TypeMeta{
+			Kind:       "PersistentVolumeClaim",
+			APIVersion: "v1",
+		},
+		ObjectMeta: v12.ObjectMeta{
+			Name:              sanitizeKubeName(volume.Name(), kubeNameMaxLength),
+			Labels:            libpodLabelsToKubeLabels(volume.Labels()),
+			CreationTimestamp: v12.Now(),
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse(kubeDefaultClaimSize),
+				},
+			},
+		},
+	}
 }
 
 func determineCapAddDropFromCapabilities(defaultCaps, containerCaps []string) *v1.Capabilities {
@@ -450,3 +655,85 @@ func generateKubeVolumeDeviceFromLinuxDevice(devices []specs.LinuxDevice) ([]v1.
 func removeUnderscores(s string) string {
 	return strings.Replace(s, "_", "", -1)
 }
+
+const (
+	// kubeNameMaxLength is the maximum length of the names of kube
+	// volumes and of label names and values
+	kubeNameMaxLength = 63
+	// kubeLabelPrefixMaxLength is the maximum length of the prefix of a
+	// kube label key
+	kubeLabelPrefixMaxLength = 253
+	// kubeDefaultClaimSize is the storage requested by generated
+	// PersistentVolumeClaims
+	kubeDefaultClaimSize = "1Gi"
+)
+
+// sanitizeKube replaces the characters of s that are not accepted by valid
+// with dashes, and trims it to maxLength. The result starts and ends with an
+// alphanumeric character.
+func sanitizeKube(s string, maxLength int, valid func(r rune) bool) string {
+	mapped := strings.Map(func(r rune) rune {
+		if valid(r) {
+			return r
+		}
+		return '-'
+	}, s)
+	if len(mapped) > maxLength {
+		mapped = mapped[:maxLength]
+	}
+	return strings.TrimFunc(mapped, func(r rune) bool {
+		return !isKubeAlphanumeric(r)
+	})
+}
+
+func isKubeAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// sanitizeKubeName converts s to a valid DNS-1123 label, as required for the
+// names of volumes and PersistentVolumeClaims
+func sanitizeKubeName(s string, maxLength int) string {
+	return sanitizeKube(strings.ToLower(s), maxLength, func(r rune) bool {
+		return isKubeAlphanumeric(r) || r == '-'
+	})
+}
+
+// sanitizeKubeLabelValue converts s to a valid kube label value, which is
+// also the format of the name part of label keys
+func sanitizeKubeLabelValue(s string) string {
+	return sanitizeKube(s, kubeNameMaxLength, func(r rune) bool {
+		return isKubeAlphanumeric(r) || r == '-' || r == '_' || r == '.'
+	})
+}
+
+// sanitizeKubeLabelKey converts s to a valid kube label key. The optional
+// prefix of the key must be a DNS subdomain.
+func sanitizeKubeLabelKey(s string) string {
+	prefix := ""
+	name := s
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		prefix = sanitizeKube(strings.ToLower(s[:i]), kubeLabelPrefixMaxLength, func(r rune) bool {
+			return isKubeAlphanumeric(r) || r == '-' || r == '.'
+		})
+		name = s[i+1:]
+	}
+	name = sanitizeKubeLabelValue(name)
+	if prefix == "" || name == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
+
+// libpodLabelsToKubeLabels converts libpod labels to valid kube labels.
+// Labels whose keys are left empty by the conversion are dropped.
+func libpodLabelsToKubeLabels(labels map[string]string) map[string]string {
+	kubeLabels := make(map[string]string)
+	for key, value := range labels {
+		kubeKey := sanitizeKubeLabelKey(key)
+		if kubeKey == "" {
+			continue
+		}
+		kubeLabels[kubeKey] = sanitizeKubeLabelValue(value)
+	}
+	return kubeLabels
+}
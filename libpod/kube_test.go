@@ -0,0 +1,173 @@
+package libpod
+
+import (
+	"strings"
+	"testing"
+
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+)
+
+func TestSanitizeKubeLabelValue(t *testing.T) {
+	for _, test := range []struct {
+		value    string
+		expected string
+	}{
+		{"web", "web"},
+		{"a_b.c-d", "a_b.c-d"},
+		{"my app!", "my-app"},
+		{"--x--", "x"},
+		{"ÄB", "B"},
+		{"!!!", ""},
+		{"", ""},
+		{strings.Repeat("a", 70), strings.Repeat("a", 63)},
+		// Truncation must not leave a trailing dash
+		{strings.Repeat("a", 62) + "-b", strings.Repeat("a", 62)},
+	} {
+		assert.Equal(t, test.expected, sanitizeKubeLabelValue(test.value), test.value)
+	}
+}
+
+func TestSanitizeKubeName(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		expected string
+	}{
+		{"myvol", "myvol"},
+		{"My_Volume", "my-volume"},
+		{"a.b", "a-b"},
+		{"_vol_", "vol"},
+		{"", ""},
+		{strings.Repeat("A", 70), strings.Repeat("a", 63)},
+	} {
+		assert.Equal(t, test.expected, sanitizeKubeName(test.name, kubeNameMaxLength), test.name)
+	}
+}
+
+func TestSanitizeKubeLabelKey(t *testing.T) {
+	for _, test := range []struct {
+		key      string
+		expected string
+	}{
+		{"app", "app"},
+		{"io.podman/Name", "io.podman/Name"},
+		{"Example.COM/my key", "example.com/my-key"},
+		{"a/b/c", "a-b/c"},
+		{"/name", "name"},
+		{"!!/name", "name"},
+		{"io.podman/", ""},
+		{"io.podman/!!", ""},
+		{"", ""},
+		{strings.Repeat("a", 300) + "/x", strings.Repeat("a", 253) + "/x"},
+		{strings.Repeat("a", 70), strings.Repeat("a", 63)},
+	} {
+		assert.Equal(t, test.expected, sanitizeKubeLabelKey(test.key), test.key)
+	}
+}
+
+func TestLibpodLabelsToKubeLabels(t *testing.T) {
+	labels := map[string]string{
+		"app":        "web",
+		"my key":     "my value",
+		"empty":      "",
+		"io.podman/": "dropped",
+	}
+	assert.Equal(t, map[string]string{
+		"app":    "web",
+		"my-key": "my-value",
+		"empty":  "",
+	}, libpodLabelsToKubeLabels(labels))
+	assert.Empty(t, libpodLabelsToKubeLabels(nil))
+}
+
+func TestLibpodMaxAndMinToResourceList(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+	uint64Ptr := func(i uint64) *uint64 { return &i }
+
+	for _, test := range []struct {
+		name      string
+		linux     *spec.Linux
+		maxLimits map[v1.ResourceName]string
+		minLimits map[v1.ResourceName]string
+	}{
+		{
+			name: "no linux section",
+		},
+		{
+			name:  "no resources",
+			linux: &spec.Linux{},
+		},
+		{
+			name: "unset limits",
+			linux: &spec.Linux{Resources: &spec.LinuxResources{
+				Memory: &spec.LinuxMemory{},
+				CPU:    &spec.LinuxCPU{},
+			}},
+		},
+		{
+			name: "zero limits",
+			linux: &spec.Linux{Resources: &spec.LinuxResources{
+				Memory: &spec.LinuxMemory{Limit: int64Ptr(0), Reservation: int64Ptr(0)},
+				CPU:    &spec.LinuxCPU{Quota: int64Ptr(0), Period: uint64Ptr(100000), Shares: uint64Ptr(0)},
+			}},
+		},
+		{
+			name: "unlimited quota",
+			linux: &spec.Linux{Resources: &spec.LinuxResources{
+				CPU: &spec.LinuxCPU{Quota: int64Ptr(-1), Period: uint64Ptr(100000)},
+			}},
+		},
+		{
+			name: "quota without period",
+			linux: &spec.Linux{Resources: &spec.LinuxResources{
+				CPU: &spec.LinuxCPU{Quota: int64Ptr(50000)},
+			}},
+		},
+		{
+			name: "memory",
+			linux: &spec.Linux{Resources: &spec.LinuxResources{
+				Memory: &spec.LinuxMemory{Limit: int64Ptr(512 << 20), Reservation: int64Ptr(256 << 20)},
+			}},
+			maxLimits: map[v1.ResourceName]string{v1.ResourceMemory: "512Mi"},
+			minLimits: map[v1.ResourceName]string{v1.ResourceMemory: "256Mi"},
+		},
+		{
+			name: "cpu",
+			linux: &spec.Linux{Resources: &spec.LinuxResources{
+				CPU: &spec.LinuxCPU{Quota: int64Ptr(150000), Period: uint64Ptr(100000), Shares: uint64Ptr(512)},
+			}},
+			maxLimits: map[v1.ResourceName]string{v1.ResourceCPU: "1500m"},
+			minLimits: map[v1.ResourceName]string{v1.ResourceCPU: "500m"},
+		},
+		{
+			name: "requests above limits",
+			linux: &spec.Linux{Resources: &spec.LinuxResources{
+				Memory: &spec.LinuxMemory{Limit: int64Ptr(256 << 20), Reservation: int64Ptr(512 << 20)},
+				CPU:    &spec.LinuxCPU{Quota: int64Ptr(50000), Period: uint64Ptr(100000), Shares: uint64Ptr(2048)},
+			}},
+			maxLimits: map[v1.ResourceName]string{v1.ResourceMemory: "256Mi", v1.ResourceCPU: "500m"},
+			minLimits: map[v1.ResourceName]string{v1.ResourceMemory: "256Mi", v1.ResourceCPU: "500m"},
+		},
+	} {
+		ctr := &Container{config: &ContainerConfig{Spec: &spec.Spec{Linux: test.linux}}}
+		maxResources, minResources := libpodMaxAndMinToResourceList(ctr)
+
+		maxLimits := make(map[v1.ResourceName]string)
+		for name, quantity := range maxResources {
+			maxLimits[name] = quantity.String()
+		}
+		minLimits := make(map[v1.ResourceName]string)
+		for name, quantity := range minResources {
+			minLimits[name] = quantity.String()
+		}
+		if test.maxLimits == nil {
+			test.maxLimits = map[v1.ResourceName]string{}
+		}
+		if test.minLimits == nil {
+			test.minLimits = map[v1.ResourceName]string{}
+		}
+		assert.Equal(t, test.maxLimits, maxLimits, test.name)
+		assert.Equal(t, test.minLimits, minLimits, test.name)
+	}
+}
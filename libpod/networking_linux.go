@@ -14,11 +14,15 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containernetworking/cni/pkg/types"
 	cnitypes "github.com/containernetworking/cni/pkg/types/current"
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/containers/libpod/pkg/firewall"
 	"github.com/containers/libpod/pkg/inspect"
 	"github.com/containers/libpod/pkg/netns"
+	"github.com/containers/libpod/pkg/network"
+	"github.com/containers/libpod/pkg/rootlessport"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -26,28 +30,186 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-// Get an OCICNI network config
-func (r *Runtime) getPodNetwork(id, name, nsPath string, networks []string, ports []ocicni.PortMapping, staticIP net.IP) ocicni.PodNetwork {
-	network := ocicni.PodNetwork{
-		Name:         name,
-		Namespace:    name, // TODO is there something else we should put here? We don't know about Kube namespaces
-		ID:           id,
-		NetNS:        nsPath,
-		PortMappings: ports,
-		Networks:     networks,
-	}
+// podNetwork is the network configuration of a container: the OCICNI network
+// config, and the static configuration of the interfaces of the container that
+// ocicni cannot pass to the CNI plugins
+type podNetwork struct {
+	ocicni.PodNetwork
+	// RuntimeConfig is the static configuration of the interface of the
+	// container in each network that needs more than a static IP
+	RuntimeConfig map[string]networkRuntimeConfig
+}
 
-	if staticIP != nil {
-		defaultNetwork := r.netPlugin.GetDefaultNetworkName()
+// networkRuntimeConfig is the static configuration of the interface of a
+// container in a single CNI network
+type networkRuntimeConfig struct {
+	IPs []net.IP
+	MAC net.HardwareAddr
+}
 
-		network.Networks = []string{defaultNetwork}
-		network.NetworkConfig = make(map[string]ocicni.NetworkConfig)
-		network.NetworkConfig[defaultNetwork] = ocicni.NetworkConfig{IP: staticIP.String()}
+// Get an OCICNI network config, along with the static configuration of the
+// interfaces of the container
+func (r *Runtime) getPodNetwork(id, name, nsPath string, networks []string, ports []ocicni.PortMapping, staticIP net.IP, staticMAC net.HardwareAddr, staticIPs map[string][]net.IP) podNetwork {
+	network := podNetwork{
+		PodNetwork: ocicni.PodNetwork{
+			Name:         name,
+			Namespace:    name, // TODO is there something else we should put here? We don't know about Kube namespaces
+			ID:           id,
+			NetNS:        nsPath,
+			PortMappings: ports,
+			Networks:     networks,
+		},
+	}
+
+	if staticIP == nil && staticMAC == nil && len(staticIPs) == 0 {
+		return network
+	}
+
+	// Networks connected after creation are kept, a static IP or MAC
+	// address is only requested from the first network
+	if len(network.Networks) == 0 {
+		network.Networks = []string{r.netPlugin.GetDefaultNetworkName()}
+	}
+	network.NetworkConfig = make(map[string]ocicni.NetworkConfig)
+	for idx, netName := range network.Networks {
+		conf := networkRuntimeConfig{
+			IPs: staticIPs[netName],
+		}
+		if idx == 0 {
+			if staticIP != nil && len(conf.IPs) == 0 {
+				conf.IPs = []net.IP{staticIP}
+			}
+			conf.MAC = staticMAC
+		}
+		// ocicni passes a single static IP per network
+		switch {
+		case conf.MAC == nil && len(conf.IPs) == 1:
+			network.NetworkConfig[netName] = ocicni.NetworkConfig{IP: conf.IPs[0].String()}
+		case conf.MAC != nil || len(conf.IPs) > 1:
+			if network.RuntimeConfig == nil {
+				network.RuntimeConfig = make(map[string]networkRuntimeConfig)
+			}
+			network.RuntimeConfig[netName] = conf
+		}
 	}
 
 	return network
 }
 
+// loopbackConfList is the CNI configuration the loopback interface of
+// containers is brought up with, the configuration ocicni uses
+const loopbackConfList = `{
+  "cniVersion": "0.2.0",
+  "name": "cni-loopback",
+  "plugins": [{
+    "type": "loopback"
+  }]
+}`
+
+// setUpPod adds the network namespace of a container to its CNI networks.
+// Networks are set up by ocicni, unless the static configuration of an
+// interface cannot be passed to the CNI plugins by ocicni.
+func (r *Runtime) setUpPod(network podNetwork) ([]types.Result, error) {
+	if len(network.RuntimeConfig) == 0 {
+		return r.netPlugin.SetUpPod(network.PodNetwork)
+	}
+
+	lists := make([]*libcni.NetworkConfigList, 0, len(network.Networks))
+	for _, name := range network.Networks {
+		list, err := r.getNetworkConfList(name)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+
+	cniConfig := libcni.NewCNIConfig(r.config.CNIPluginDir, nil)
+
+	// Bring up the loopback interface, as ocicni does before adding the
+	// container to its networks
+	lo, err := libcni.ConfListFromBytes([]byte(loopbackConfList))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing loopback network configuration")
+	}
+	loRt := getNetworkRuntimeConf(network.ID, network.Name, network.NetNS, "lo", nil, networkRuntimeConfig{})
+	if _, err := cniConfig.AddNetworkList(lo, loRt); err != nil {
+		return nil, errors.Wrapf(err, "error adding container %s to the loopback network", network.ID)
+	}
+
+	results := make([]types.Result, 0, len(network.Networks))
+	for idx, name := range network.Networks {
+		conf, ok := network.RuntimeConfig[name]
+		if !ok {
+			if ip := net.ParseIP(network.NetworkConfig[name].IP); ip != nil {
+				conf.IPs = []net.IP{ip}
+			}
+		}
+		// Interfaces are named as ocicni names them, so the networks
+		// are torn down by ocicni
+		ifName := fmt.Sprintf("eth%d", idx)
+		rt := getNetworkRuntimeConf(network.ID, network.Name, network.NetNS, ifName, network.PortMappings, conf)
+
+		logrus.Debugf("Adding container %s to network %s on interface %s", network.ID, name, ifName)
+		res, err := cniConfig.AddNetworkList(lists[idx], rt)
+		if err != nil {
+			partial := network.PodNetwork
+			partial.Networks = network.Networks[:idx+1]
+			if err2 := r.netPlugin.TearDownPod(partial); err2 != nil {
+				logrus.Errorf("Error tearing down partially configured networks of container %s: %v", network.ID, err2)
+			}
+			return nil, errors.Wrapf(err, "error adding container %s to network %s", network.ID, name)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// tearDownPod removes the network namespace of a container from its CNI
+// networks. Networks are torn down by ocicni, unless networks connected and
+// disconnected while the namespace was set up left interfaces that are not
+// named after the position of their network, as ocicni names them.
+func (r *Runtime) tearDownPod(ctr *Container, network podNetwork) error {
+	ifNames := make([]string, len(network.Networks))
+	renamed := false
+	for idx := range network.Networks {
+		ifNames[idx] = fmt.Sprintf("eth%d", idx)
+		if idx < len(ctr.state.NetworkStatus) {
+			if ifName := ctrInterfaceName(ctr.state.NetworkStatus[idx], network.NetNS); ifName != "" && ifName != ifNames[idx] {
+				ifNames[idx] = ifName
+				renamed = true
+			}
+		}
+	}
+	if !renamed {
+		return r.netPlugin.TearDownPod(network.PodNetwork)
+	}
+
+	cniConfig := libcni.NewCNIConfig(r.config.CNIPluginDir, nil)
+	var lastErr error
+	for idx, name := range network.Networks {
+		list, err := r.getNetworkConfList(name)
+		if err != nil {
+			logrus.Errorf("Error removing container %s from network %s: %v", network.ID, name, err)
+			lastErr = err
+			continue
+		}
+		conf, ok := network.RuntimeConfig[name]
+		if !ok {
+			if ip := net.ParseIP(network.NetworkConfig[name].IP); ip != nil {
+				conf.IPs = []net.IP{ip}
+			}
+		}
+		rt := getNetworkRuntimeConf(network.ID, network.Name, network.NetNS, ifNames[idx], network.PortMappings, conf)
+
+		logrus.Debugf("Removing container %s from network %s on interface %s", network.ID, name, ifNames[idx])
+		if err := cniConfig.DelNetworkList(list, rt); err != nil {
+			logrus.Errorf("Error removing container %s from network %s: %v", network.ID, name, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
 // Create and configure a new network namespace for a container
 func (r *Runtime) configureNetNS(ctr *Container, ctrNS ns.NetNS) ([]*cnitypes.Result, error) {
 	var requestedIP net.IP
@@ -59,15 +221,15 @@ func (r *Runtime) configureNetNS(ctr *Container, ctrNS ns.NetNS) ([]*cnitypes.Re
 		requestedIP = ctr.config.StaticIP
 	}
 
-	podNetwork := r.getPodNetwork(ctr.ID(), ctr.Name(), ctrNS.Path(), ctr.config.Networks, ctr.config.PortMappings, requestedIP)
+	podNetwork := r.getPodNetwork(ctr.ID(), ctr.Name(), ctrNS.Path(), ctr.config.Networks, ctr.config.PortMappings, requestedIP, ctr.config.StaticMAC, ctr.config.StaticIPs)
 
-	results, err := r.netPlugin.SetUpPod(podNetwork)
+	results, err := r.setUpPod(podNetwork)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error configuring network namespace for container %s", ctr.ID())
 	}
 	defer func() {
 		if err != nil {
-			if err2 := r.netPlugin.TearDownPod(podNetwork); err2 != nil {
+			if err2 := r.netPlugin.TearDownPod(podNetwork.PodNetwork); err2 != nil {
 				logrus.Errorf("Error tearing down partially created network namespace for container %s: %v", ctr.ID(), err2)
 			}
 		}
@@ -121,6 +283,10 @@ func (r *Runtime) createNetNS(ctr *Container) (n ns.NetNS, q []*cnitypes.Result,
 	return ctrNS, networkStatus, err
 }
 
+// slirp4netnsIP is the address slirp4netns configures in the network namespace
+// of rootless containers
+const slirp4netnsIP = "10.0.2.100"
+
 type slirp4netnsCmdArg struct {
 	Proto     string `json:"proto,omitempty"`
 	HostAddr  string `json:"host_addr"`
@@ -153,9 +319,12 @@ func (r *Runtime) setupRootlessNetNS(ctr *Container) (err error) {
 	defer syncW.Close()
 
 	havePortMapping := len(ctr.Config().PortMappings) > 0
+	// Ports are forwarded with the API of slirp4netns, unless the container
+	// uses the rootlessport helper
+	slirpPortMapping := havePortMapping && ctr.config.PortForwarder != RootlessPortForwarder
 	apiSocket := filepath.Join(r.ociRuntime.tmpDir, fmt.Sprintf("%s.net", ctr.config.ID))
 	var cmd *exec.Cmd
-	if havePortMapping {
+	if slirpPortMapping {
 		// if we need ports to be mapped from the host, create a API socket to use for communicating with slirp4netns.
 		cmd = exec.Command(path, "-c", "-e", "3", "-r", "4", "--api-socket", apiSocket, fmt.Sprintf("%d", ctr.state.PID), "tap0")
 	} else {
@@ -199,7 +368,21 @@ func (r *Runtime) setupRootlessNetNS(ctr *Container) (err error) {
 		}
 	}
 
-	if havePortMapping {
+	if havePortMapping && !slirpPortMapping {
+		// The helper exits with slirp4netns, when conmon closes the
+		// other end of the sync pipe
+		config := &rootlessport.Config{
+			Mappings:    ctr.config.PortMappings,
+			NetNSPath:   fmt.Sprintf("/proc/%d/ns/net", ctr.state.PID),
+			ChildIP:     slirp4netnsIP,
+			PeerAddress: ctr.config.PortPeerAddress,
+		}
+		if err := rootlessport.Start(config, ctr.rootlessSlirpSyncR); err != nil {
+			return errors.Wrapf(err, "failed to forward ports of container %s", ctr.ID())
+		}
+	}
+
+	if slirpPortMapping {
 		const pidWaitTimeout = 60 * time.Second
 		chWait := make(chan error)
 		go func() {
@@ -377,10 +560,10 @@ func (r *Runtime) teardownNetNS(ctr *Container) error {
 		requestedIP = ctr.config.StaticIP
 	}
 
-	podNetwork := r.getPodNetwork(ctr.ID(), ctr.Name(), ctr.state.NetNS.Path(), ctr.config.Networks, ctr.config.PortMappings, requestedIP)
+	podNetwork := r.getPodNetwork(ctr.ID(), ctr.Name(), ctr.state.NetNS.Path(), ctr.config.Networks, ctr.config.PortMappings, requestedIP, ctr.config.StaticMAC, ctr.config.StaticIPs)
 
 	// The network may have already been torn down, so don't fail here, just log
-	if err := r.netPlugin.TearDownPod(podNetwork); err != nil {
+	if err := r.tearDownPod(ctr, podNetwork); err != nil {
 		return errors.Wrapf(err, "error tearing down CNI namespace configuration for container %s", ctr.ID())
 	}
 
@@ -467,6 +650,239 @@ func (c *Container) getContainerNetworkInfo(data *inspect.ContainerInspectData)
 				data.NetworkSettings.MacAddress = i.Mac
 			}
 		}
+
+		// Report the addresses of every network, the results are kept
+		// in the order of the networks
+		networks := c.networks()
+		data.NetworkSettings.Networks = make(map[string]*inspect.NetworkEndpoint)
+		for idx, result := range c.state.NetworkStatus {
+			if idx >= len(networks) {
+				break
+			}
+			endpoint := networkEndpoint(result, data.NetworkSettings.SandboxKey)
+			endpoint.IPAMConfig = c.staticIPAMConfig(idx, networks[idx])
+			data.NetworkSettings.Networks[networks[idx]] = endpoint
+		}
 	}
 	return data
 }
+
+// networkEndpoint returns the interface and addresses of the container
+// configured by a CNI result
+func networkEndpoint(result *cnitypes.Result, sandbox string) *inspect.NetworkEndpoint {
+	endpoint := new(inspect.NetworkEndpoint)
+	for _, ctrIP := range result.IPs {
+		ones, _ := ctrIP.Address.Mask.Size()
+		gateway := ""
+		if ctrIP.Gateway != nil {
+			gateway = ctrIP.Gateway.String()
+		}
+		if ctrIP.Version == "4" {
+			endpoint.IPAddress = ctrIP.Address.IP.String()
+			endpoint.IPPrefixLen = ones
+			endpoint.Gateway = gateway
+		} else {
+			endpoint.GlobalIPv6Address = ctrIP.Address.IP.String()
+			endpoint.GlobalIPv6PrefixLen = ones
+			endpoint.IPv6Gateway = gateway
+		}
+	}
+	for _, i := range result.Interfaces {
+		if i.Sandbox == sandbox {
+			endpoint.InterfaceName = i.Name
+			endpoint.MacAddress = i.Mac
+		}
+	}
+	return endpoint
+}
+
+// staticIPAMConfig returns the static addresses requested for the container in
+// the network at index idx of its networks, nil if none were requested
+func (c *Container) staticIPAMConfig(idx int, name string) *inspect.EndpointIPAMConfig {
+	ips := append([]net.IP{}, c.config.StaticIPs[name]...)
+	if idx == 0 && c.config.StaticIP != nil {
+		ips = append(ips, c.config.StaticIP)
+	}
+	if len(ips) == 0 {
+		return nil
+	}
+	config := new(inspect.EndpointIPAMConfig)
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			config.IPv4Address = ip.String()
+		} else {
+			config.IPv6Address = ip.String()
+		}
+	}
+	return config
+}
+
+// ctrInterfaceName returns the name of the interface a CNI result configured
+// in the network namespace of the container
+func ctrInterfaceName(result *cnitypes.Result, sandbox string) string {
+	for _, i := range result.Interfaces {
+		if i.Sandbox == sandbox {
+			return i.Name
+		}
+	}
+	return ""
+}
+
+// freeInterfaceName returns the name of the interface of a network connected
+// to a container. ocicni names interfaces after the position of their network,
+// so the interface is named after the position the network is added at, unless
+// a network disconnected earlier left that name in use. The first interface
+// name not used by a network of the container is returned then.
+func freeInterfaceName(ctr *Container) string {
+	used := make(map[string]bool)
+	for _, result := range ctr.state.NetworkStatus {
+		used[ctrInterfaceName(result, ctr.state.NetNS.Path())] = true
+	}
+	if ifName := fmt.Sprintf("eth%d", len(ctr.networks())); !used[ifName] {
+		return ifName
+	}
+	for i := 0; ; i++ {
+		ifName := fmt.Sprintf("eth%d", i)
+		if !used[ifName] {
+			return ifName
+		}
+	}
+}
+
+// getNetworkRuntimeConf returns the CNI runtime configuration of a single
+// network of a container, matching the configuration ocicni uses so the
+// network is torn down with the rest of the namespace. The static
+// configuration of the interface is passed to the plugins both as capability
+// arguments and as CNI_ARGS.
+func getNetworkRuntimeConf(id, name, nsPath, ifName string, ports []ocicni.PortMapping, conf networkRuntimeConfig) *libcni.RuntimeConf {
+	rt := &libcni.RuntimeConf{
+		ContainerID: id,
+		NetNS:       nsPath,
+		IfName:      ifName,
+		Args: [][2]string{
+			{"IgnoreUnknown", "1"},
+			{"K8S_POD_NAMESPACE", name},
+			{"K8S_POD_NAME", name},
+			{"K8S_POD_INFRA_CONTAINER_ID", id},
+		},
+		CapabilityArgs: make(map[string]interface{}),
+	}
+	if len(conf.IPs) > 0 {
+		ips := make([]string, 0, len(conf.IPs))
+		for _, ip := range conf.IPs {
+			ips = append(ips, ip.String())
+		}
+		// host-local parses the IP argument as a single address, so
+		// several addresses are only passed as runtime configuration
+		if len(ips) == 1 {
+			rt.Args = append(rt.Args, [2]string{"IP", ips[0]})
+		}
+		rt.CapabilityArgs["ips"] = ips
+	}
+	if conf.MAC != nil {
+		rt.Args = append(rt.Args, [2]string{"MAC", conf.MAC.String()})
+		rt.CapabilityArgs["mac"] = conf.MAC.String()
+	}
+	if len(ports) > 0 {
+		rt.CapabilityArgs["portMappings"] = ports
+	}
+	return rt
+}
+
+// getNetworkConfList returns the CNI configuration list of a network
+func (r *Runtime) getNetworkConfList(name string) (*libcni.NetworkConfigList, error) {
+	n, err := network.Get(r.config.CNIConfigDir, name)
+	if err != nil {
+		return nil, err
+	}
+	return libcni.ConfListFromBytes(n.Config)
+}
+
+// attachNetwork adds the network namespace of a container to a single CNI
+// network, creating the next free interface of the container
+func (r *Runtime) attachNetwork(ctr *Container, name string, ip net.IP) (*cnitypes.Result, error) {
+	list, err := r.getNetworkConfList(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ifName := freeInterfaceName(ctr)
+	var conf networkRuntimeConfig
+	if ip != nil {
+		conf.IPs = []net.IP{ip}
+	}
+	rt := getNetworkRuntimeConf(ctr.ID(), ctr.Name(), ctr.state.NetNS.Path(), ifName, nil, conf)
+	cniConfig := libcni.NewCNIConfig(r.config.CNIPluginDir, nil)
+
+	logrus.Debugf("Adding container %s to network %s on interface %s", ctr.ID(), name, ifName)
+	res, err := cniConfig.AddNetworkList(list, rt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error adding container %s to network %s", ctr.ID(), name)
+	}
+	defer func() {
+		if err != nil {
+			if err2 := cniConfig.DelNetworkList(list, rt); err2 != nil {
+				logrus.Errorf("Error removing container %s from network %s: %v", ctr.ID(), name, err2)
+			}
+		}
+	}()
+
+	result, err := cnitypes.GetResult(res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing CNI plugin result %q", res.String())
+	}
+
+	// Add firewall rules to ensure the container has network access, as
+	// for the networks joined when the namespace was configured
+	firewallConf := &firewall.FirewallNetConf{
+		PrevResult: result,
+	}
+	if err = r.firewallBackend.Add(firewallConf); err != nil {
+		return nil, errors.Wrapf(err, "error adding firewall rules for container %s", ctr.ID())
+	}
+	return result, nil
+}
+
+// detachNetwork removes the interface of a single CNI network from the network
+// namespace of a container. result is the result of adding the network.
+func (r *Runtime) detachNetwork(ctr *Container, name string, result *cnitypes.Result) error {
+	list, err := r.getNetworkConfList(name)
+	if err != nil {
+		return err
+	}
+
+	firewallConf := &firewall.FirewallNetConf{
+		PrevResult: result,
+	}
+	if err := r.firewallBackend.Del(firewallConf); err != nil {
+		return errors.Wrapf(err, "error removing firewall rules for container %s", ctr.ID())
+	}
+
+	ifName := ctrInterfaceName(result, ctr.state.NetNS.Path())
+	if ifName == "" {
+		return errors.Wrapf(ErrInternal, "no interface of network %s found in container %s", name, ctr.ID())
+	}
+	rt := getNetworkRuntimeConf(ctr.ID(), ctr.Name(), ctr.state.NetNS.Path(), ifName, nil, networkRuntimeConfig{})
+	cniConfig := libcni.NewCNIConfig(r.config.CNIPluginDir, nil)
+
+	logrus.Debugf("Removing container %s from network %s on interface %s", ctr.ID(), name, ifName)
+	if err := cniConfig.DelNetworkList(list, rt); err != nil {
+		return errors.Wrapf(err, "error removing container %s from network %s", ctr.ID(), name)
+	}
+	return nil
+}
+
+// removeBridge removes a bridge interface left behind by a removed network
+func removeBridge(name string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+		return errors.Wrapf(err, "error retrieving bridge %s", name)
+	}
+	if link.Type() != "bridge" {
+		return errors.Errorf("interface %s is not a bridge", name)
+	}
+	return netlink.LinkDel(link)
+}
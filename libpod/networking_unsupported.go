@@ -3,6 +3,9 @@
 package libpod
 
 import (
+	"net"
+
+	cnitypes "github.com/containernetworking/cni/pkg/types/current"
 	"github.com/containers/libpod/pkg/inspect"
 )
 
@@ -25,3 +28,15 @@ func (r *Runtime) createNetNS(ctr *Container) (err error) {
 func (c *Container) getContainerNetworkInfo(data *inspect.ContainerInspectData) *inspect.ContainerInspectData {
 	return nil
 }
+
+func removeBridge(name string) error {
+	return ErrNotImplemented
+}
+
+func (r *Runtime) attachNetwork(ctr *Container, name string, ip net.IP) (*cnitypes.Result, error) {
+	return nil, ErrNotImplemented
+}
+
+func (r *Runtime) detachNetwork(ctr *Container, name string, result *cnitypes.Result) error {
+	return ErrNotImplemented
+}
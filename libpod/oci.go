@@ -256,7 +256,16 @@ func (r *OCIRuntime) createOCIContainer(ctr *Container, cgroupParent string, res
 	args = append(args, "-r", r.path)
 	args = append(args, "-b", ctr.bundlePath())
 	args = append(args, "-p", filepath.Join(ctr.state.RunDir, "pidfile"))
-	args = append(args, "-l", ctr.LogPath())
+	switch ctr.LogDriver() {
+	case JournaldLogging:
+		// conmon forwards the output to the journal, tagged with the
+		// container ID in the CONTAINER_ID_FULL field
+		args = append(args, "-l", fmt.Sprintf("%s:", JournaldLogging))
+	case NoLogging:
+		args = append(args, "-l", os.DevNull)
+	default:
+		args = append(args, "-l", ctr.LogPath())
+	}
 	args = append(args, "--exit-dir", r.exitsDir)
 	if ctr.config.ConmonPidFile != "" {
 		args = append(args, "--conmon-pidfile", ctr.config.ConmonPidFile)
@@ -730,11 +739,28 @@ func (r *OCIRuntime) unpauseContainer(ctr *Container) error {
 	return utils.ExecCmdWithStdStreams(os.Stdin, os.Stdout, os.Stderr, env, r.path, "resume", ctr.ID())
 }
 
+// updateContainer changes the resource limits of the given container with
+// the runtime's update command
+func (r *OCIRuntime) updateContainer(ctr *Container, resources *spec.LinuxResources) error {
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling resources of container %s to JSON", ctr.ID())
+	}
+	runtimeDir, err := util.GetRootlessRuntimeDir()
+	if err != nil {
+		return err
+	}
+	env := []string{fmt.Sprintf("XDG_RUNTIME_DIR=%s", runtimeDir)}
+	return utils.ExecCmdWithStdStreams(bytes.NewReader(resourcesJSON), os.Stdout, os.Stderr, env, r.path, "update", "--resources", "-", ctr.ID())
+}
+
 // execContainer executes a command in a running container
 // TODO: Add --detach support
 // TODO: Convert to use conmon
 // TODO: add --pid-file and use that to generate exec session tracking
-func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty bool, cwd, user, sessionID string) (*exec.Cmd, error) {
+// If streams is nil, the exec session is attached to the standard streams of
+// the current process.
+func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty bool, cwd, user, sessionID string, streams *AttachStreams) (*exec.Cmd, error) {
 	if len(cmd) == 0 {
 		return nil, errors.Wrapf(ErrInvalidArg, "must provide a command to execute")
 	}
@@ -790,9 +816,21 @@ func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty
 	logrus.Debugf("Starting runtime %s with following arguments: %v", r.path, args)
 
 	execCmd := exec.Command(r.path, args...)
-	execCmd.Stdout = os.Stdout
-	execCmd.Stderr = os.Stderr
-	execCmd.Stdin = os.Stdin
+	if streams != nil {
+		if streams.AttachOutput {
+			execCmd.Stdout = streams.OutputStream
+		}
+		if streams.AttachError {
+			execCmd.Stderr = streams.ErrorStream
+		}
+		if streams.AttachInput {
+			execCmd.Stdin = streams.InputStream
+		}
+	} else {
+		execCmd.Stdout = os.Stdout
+		execCmd.Stderr = os.Stderr
+		execCmd.Stdin = os.Stdin
+	}
 	execCmd.Env = append(execCmd.Env, fmt.Sprintf("XDG_RUNTIME_DIR=%s", runtimeDir))
 
 	if err := execCmd.Start(); err != nil {
@@ -802,26 +840,76 @@ func (r *OCIRuntime) execContainer(c *Container, cmd, capAdd, env []string, tty
 	return execCmd, nil
 }
 
+// execContainerDetached starts the command of a detached exec session through
+// conmon, using process as the process spec of the command. conmon forks and
+// keeps running until the command exits, and records its exit code in the exit
+// file of the session. The output of the command is discarded.
+func (r *OCIRuntime) execContainerDetached(c *Container, sessionID string, process *spec.Process) error {
+	runtimeDir, err := util.GetRootlessRuntimeDir()
+	if err != nil {
+		return err
+	}
+
+	bundlePath := c.execBundlePath(sessionID)
+	if err := os.MkdirAll(filepath.Dir(c.execExitFilePath(sessionID)), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for exec session %s", sessionID)
+	}
+	processJSON, err := json.Marshal(process)
+	if err != nil {
+		return errors.Wrapf(err, "error marshalling process spec of exec session %s", sessionID)
+	}
+	processFile := filepath.Join(bundlePath, "exec-process.json")
+	if err := ioutil.WriteFile(processFile, processJSON, 0600); err != nil {
+		return errors.Wrapf(err, "error writing process spec of exec session %s", sessionID)
+	}
+
+	args := []string{}
+	args = append(args, "-c", c.ID())
+	// conmon names the exit file after the container
+	args = append(args, "-u", c.ID())
+	args = append(args, "-r", r.path)
+	args = append(args, "-b", bundlePath)
+	args = append(args, "-p", c.execPidPath(sessionID))
+	args = append(args, "-l", os.DevNull)
+	args = append(args, "--exit-dir", filepath.Dir(c.execExitFilePath(sessionID)))
+	args = append(args, "--conmon-pidfile", c.execConmonPidPath(sessionID))
+	args = append(args, "--socket-dir-path", bundlePath)
+	args = append(args, "-e", "--exec-process-spec", processFile)
+	if process.Terminal {
+		args = append(args, "-t")
+	}
+	args = append(args, "--log-level", logrus.GetLevel().String())
+
+	logrus.WithFields(logrus.Fields{
+		"args": args,
+	}).Debugf("running conmon: %s", r.conmonPath)
+
+	cmd := exec.Command(r.conmonPath, args...)
+	cmd.Dir = bundlePath
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+	cmd.Env = append(r.conmonEnv, fmt.Sprintf("XDG_RUNTIME_DIR=%s", runtimeDir))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("_LIBPOD_USERNS_CONFIGURED=%s", os.Getenv("_LIBPOD_USERNS_CONFIGURED")))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("_LIBPOD_ROOTLESS_UID=%s", os.Getenv("_LIBPOD_ROOTLESS_UID")))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("HOME=%s", os.Getenv("HOME")))
+
+	// conmon exits once it forked
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "error running conmon for exec session %s", sessionID)
+	}
+	return nil
+}
+
 // execStopContainer stops all active exec sessions in a container
 // It will also stop all other processes in the container. It is only intended
 // to be used to assist in cleanup when removing a container.
 // SIGTERM is used by default to stop processes. If SIGTERM fails, SIGKILL will be used.
 func (r *OCIRuntime) execStopContainer(ctr *Container, timeout uint) error {
-	// Do we have active exec sessions?
-	if len(ctr.state.ExecSessions) == 0 {
-		return nil
-	}
-
 	// Get a list of active exec sessions
 	execSessions := []int{}
-	for _, session := range ctr.state.ExecSessions {
-		pid := session.PID
-		// Ping the PID with signal 0 to see if it still exists
-		if err := unix.Kill(pid, 0); err == unix.ESRCH {
-			continue
-		}
-
-		execSessions = append(execSessions, pid)
+	for _, session := range ctr.activeExecSessions() {
+		execSessions = append(execSessions, session.PID)
 	}
 
 	// All the sessions may be dead
@@ -7,7 +7,10 @@ import (
 	"regexp"
 	"syscall"
 
+	"github.com/containers/image/manifest"
 	"github.com/containers/libpod/pkg/namespaces"
+	"github.com/containers/libpod/pkg/rootlessport"
+	"github.com/containers/libpod/pkg/util"
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/cri-o/ocicni/pkg/ocicni"
@@ -256,6 +259,22 @@ func WithDefaultMountsFile(mountsFile string) RuntimeOption {
 	}
 }
 
+// WithVolumePluginDir sets the directory holding the sockets of volume
+// plugins.
+func WithVolumePluginDir(dir string) RuntimeOption {
+	return func(rt *Runtime) error {
+		if rt.valid {
+			return ErrRuntimeFinalized
+		}
+
+		if dir == "" {
+			return ErrInvalidArg
+		}
+		rt.config.VolumePluginDir = dir
+		return nil
+	}
+}
+
 // WithTmpDir sets the directory that temporary runtime files which are not
 // expected to survive across reboots will be stored.
 // This should be located on a tmpfs mount (/tmp or /var/run for example).
@@ -914,12 +933,133 @@ func WithStaticIP(ip net.IP) CtrCreateOption {
 			return errors.Wrapf(ErrInvalidArg, "cannot set a static IP if joining additional CNI networks")
 		}
 
+		if len(ctr.config.StaticIPs) != 0 {
+			return errors.Wrapf(ErrInvalidArg, "cannot set a static IP if static IPs of networks are set")
+		}
+
 		ctr.config.StaticIP = ip
 
 		return nil
 	}
 }
 
+// WithStaticMAC indicates that the container should request a static MAC
+// address for its interface in its first CNI network.
+// It cannot be set unless WithNetNS has already been passed.
+func WithStaticMAC(mac net.HardwareAddr) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		if !ctr.config.CreateNetNS {
+			return errors.Wrapf(ErrInvalidArg, "cannot set a static MAC address if the container is not creating a network namespace")
+		}
+
+		if len(mac) != 6 {
+			return errors.Wrapf(ErrInvalidArg, "%s is not an Ethernet MAC address", mac)
+		}
+
+		ctr.config.StaticMAC = mac
+
+		return nil
+	}
+}
+
+// WithStaticIPs indicates that the container should request static IPs from
+// the CNI plugins of a network. If network is empty, the IPs are requested from
+// the first network of the container. At most one IPv4 and one IPv6 address
+// can be requested from each network.
+// It cannot be set unless WithNetNS has already been passed.
+// Further, it cannot be set if WithStaticIP has been passed.
+func WithStaticIPs(network string, ips []net.IP) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		if !ctr.config.CreateNetNS {
+			return errors.Wrapf(ErrInvalidArg, "cannot set static IPs if the container is not creating a network namespace")
+		}
+
+		if ctr.config.StaticIP != nil {
+			return errors.Wrapf(ErrInvalidArg, "cannot set static IPs of networks if a static IP is set")
+		}
+
+		networks := ctr.networks()
+		if network == "" {
+			network = networks[0]
+		}
+		if !util.StringInSlice(network, networks) {
+			return errors.Wrapf(ErrInvalidArg, "cannot set static IPs of network %s, the container does not join it", network)
+		}
+
+		requested := append(append([]net.IP{}, ctr.config.StaticIPs[network]...), ips...)
+		have4, have6 := false, false
+		for _, ip := range requested {
+			switch {
+			case ip == nil:
+				return errors.Wrapf(ErrInvalidArg, "invalid static IP of network %s", network)
+			case ip.To4() != nil:
+				if have4 {
+					return errors.Wrapf(ErrInvalidArg, "cannot set more than one static IPv4 address for network %s", network)
+				}
+				have4 = true
+			default:
+				if have6 {
+					return errors.Wrapf(ErrInvalidArg, "cannot set more than one static IPv6 address for network %s", network)
+				}
+				have6 = true
+			}
+		}
+
+		if ctr.config.StaticIPs == nil {
+			ctr.config.StaticIPs = make(map[string][]net.IP)
+		}
+		ctr.config.StaticIPs[network] = requested
+
+		return nil
+	}
+}
+
+// WithPortForwarder sets the forwarder of the port mappings of a rootless
+// container, and how RootlessPortForwarder exposes the address of the peers of
+// forwarded connections to the container.
+// It cannot be set unless WithNetNS has already been passed with the
+// slirp4netns network mode.
+func WithPortForwarder(forwarder, peerAddress string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		if !ctr.config.CreateNetNS {
+			return errors.Wrapf(ErrInvalidArg, "cannot set a port forwarder if the container is not creating a network namespace")
+		}
+		if !ctr.config.NetMode.IsSlirp4netns() {
+			return errors.Wrapf(ErrInvalidArg, "cannot set a port forwarder if the container is not using slirp4netns")
+		}
+
+		switch forwarder {
+		case "", SlirpPortForwarder:
+			if peerAddress != "" && peerAddress != rootlessport.PeerAddressNone {
+				return errors.Wrapf(ErrInvalidArg, "the address of peers can only be exposed by the %s port forwarder", RootlessPortForwarder)
+			}
+		case RootlessPortForwarder:
+			if !rootlessport.ValidPeerAddress(peerAddress) {
+				return errors.Wrapf(ErrInvalidArg, "%q is not a valid way to expose the address of peers", peerAddress)
+			}
+		default:
+			return errors.Wrapf(ErrInvalidArg, "%q is not a supported port forwarder", forwarder)
+		}
+
+		ctr.config.PortForwarder = forwarder
+		ctr.config.PortPeerAddress = peerAddress
+
+		return nil
+	}
+}
+
 // WithLogPath sets the path to the log file.
 func WithLogPath(path string) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -936,6 +1076,28 @@ func WithLogPath(path string) CtrCreateOption {
 	}
 }
 
+// WithLogDriver sets the driver used to log the output of the container.
+func WithLogDriver(driver string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		switch driver {
+		case "":
+			return errors.Wrapf(ErrInvalidArg, "log driver must be set")
+		case KubernetesLogging, JSONLogging:
+			ctr.config.LogDriver = KubernetesLogging
+		case JournaldLogging, NoLogging:
+			ctr.config.LogDriver = driver
+		default:
+			return errors.Wrapf(ErrInvalidArg, "%q is not a supported log driver", driver)
+		}
+
+		return nil
+	}
+}
+
 // WithCgroupParent sets the Cgroup Parent of the new container.
 func WithCgroupParent(parent string) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -1146,6 +1308,62 @@ func WithCtrNamespace(ns string) CtrCreateOption {
 	}
 }
 
+// WithRestartPolicy sets the container's restart policy. Valid values are
+// "no", "on-failure", "always" and "unless-stopped". The empty string is
+// allowed and will be equivalent to "no".
+func WithRestartPolicy(policy string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		switch policy {
+		case RestartPolicyNone, RestartPolicyNo, RestartPolicyOnFailure, RestartPolicyAlways, RestartPolicyUnlessStopped:
+			ctr.config.RestartPolicy = policy
+		default:
+			return errors.Wrapf(ErrInvalidArg, "%q is not a valid restart policy", policy)
+		}
+
+		return nil
+	}
+}
+
+// WithRestartRetries sets the number of retries to use when restarting a
+// container with the "on-failure" restart policy.
+// 0 is an allowed value, and indicates infinite retries.
+func WithRestartRetries(tries uint) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		ctr.config.RestartRetries = tries
+
+		return nil
+	}
+}
+
+// WithHealthCheck adds a healthcheck to the container. The healthcheck is
+// run with `podman healthcheck run`.
+func WithHealthCheck(healthCheck *manifest.Schema2HealthConfig) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return ErrCtrFinalized
+		}
+
+		if healthCheck == nil {
+			return errors.Wrapf(ErrInvalidArg, "must provide a healthcheck")
+		}
+		if len(healthCheck.Test) == 0 {
+			return errors.Wrapf(ErrInvalidArg, "healthcheck must have a command")
+		}
+
+		ctr.config.HealthCheckConfig = healthCheck
+
+		return nil
+	}
+}
+
 // withIsInfra sets the container to be an infra container. This means the container will be sometimes hidden
 // and expected to be the first container in the pod.
 func withIsInfra() CtrCreateOption {
@@ -1224,6 +1442,24 @@ func WithVolumeOptions(options map[string]string) VolumeCreateOption {
 	}
 }
 
+// WithVolumePod makes the volume belong to a pod. The volume is removed when
+// the pod is removed.
+func WithVolumePod(pod *Pod) VolumeCreateOption {
+	return func(volume *Volume) error {
+		if volume.valid {
+			return ErrVolumeFinalized
+		}
+
+		if pod == nil {
+			return ErrInvalidArg
+		}
+
+		volume.config.Pod = pod.ID()
+
+		return nil
+	}
+}
+
 // Pod Creation Options
 
 // WithPodName sets the name of the pod.
@@ -0,0 +1,309 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Volume plugins are served over a unix socket named <plugin name>.sock in
+// the plugin directory, and speak the Docker volume plugin protocol: every
+// call is a POST of a JSON object to /<Interface>.<Method>, answered by a
+// JSON object with an Err field describing failures.
+
+const (
+	// volumePluginType is the interface a plugin must implement to be
+	// used as a volume driver
+	volumePluginType = "VolumeDriver"
+	// pluginSocketSuffix is the suffix of plugin sockets
+	pluginSocketSuffix = ".sock"
+	// pluginContentType is the content type of plugin requests
+	pluginContentType = "application/vnd.docker.plugins.v1+json"
+	// defaultTimeout is the time a plugin may take to answer a request.
+	// Mounting remote volumes can be slow, so this is generous.
+	defaultTimeout = 60 * time.Second
+
+	activatePath     = "/Plugin.Activate"
+	createPath       = "/VolumeDriver.Create"
+	removePath       = "/VolumeDriver.Remove"
+	mountPath        = "/VolumeDriver.Mount"
+	unmountPath      = "/VolumeDriver.Unmount"
+	pathPath         = "/VolumeDriver.Path"
+	getPath          = "/VolumeDriver.Get"
+	listPath         = "/VolumeDriver.List"
+	capabilitiesPath = "/VolumeDriver.Capabilities"
+)
+
+var (
+	// ErrNoSuchPlugin indicates that no plugin with the requested name has
+	// a socket in the plugin directory
+	ErrNoSuchPlugin = errors.New("no such volume plugin")
+	// ErrNotVolumePlugin indicates that a plugin does not implement the
+	// volume driver interface
+	ErrNotVolumePlugin = errors.New("plugin does not implement the volume driver interface")
+	// ErrPluginRequest indicates that a plugin failed to handle a request
+	ErrPluginRequest = errors.New("volume plugin request failed")
+)
+
+// VolumePlugin is a volume plugin that libpod can use as the driver of named
+// volumes
+type VolumePlugin struct {
+	// Name is the name of the plugin, which is the name of its socket
+	// without suffix
+	Name string `json:"name"`
+	// SocketPath is the path to the socket the plugin is served on
+	SocketPath string `json:"socketPath"`
+
+	client *http.Client
+}
+
+// Volume is a volume as reported by a volume plugin
+type Volume struct {
+	// Name is the name of the volume
+	Name string `json:"Name"`
+	// Mountpoint is the path the volume is mounted at on the host, if it
+	// is mounted
+	Mountpoint string `json:"Mountpoint,omitempty"`
+	// Status holds plugin specific information on the volume
+	Status map[string]interface{} `json:"Status,omitempty"`
+}
+
+// Capabilities describe the capabilities of a volume plugin
+type Capabilities struct {
+	// Scope is either "local" or "global". Global volumes are shared
+	// between hosts.
+	Scope string `json:"Scope"`
+}
+
+type activateResponse struct {
+	Implements []string `json:"Implements"`
+}
+
+type volumeRequest struct {
+	Name string            `json:"Name"`
+	Opts map[string]string `json:"Opts,omitempty"`
+	ID   string            `json:"ID,omitempty"`
+}
+
+type errorResponse struct {
+	Err string `json:"Err"`
+}
+
+type pathResponse struct {
+	Mountpoint string `json:"Mountpoint"`
+}
+
+type getResponse struct {
+	Volume *Volume `json:"Volume"`
+}
+
+type listResponse struct {
+	Volumes []*Volume `json:"Volumes"`
+}
+
+type capabilitiesResponse struct {
+	Capabilities Capabilities `json:"Capabilities"`
+}
+
+// GetVolumePlugin returns the volume plugin with the given name from the
+// plugin directory. The plugin is activated, which verifies that it is a
+// volume plugin.
+func GetVolumePlugin(name, dir string) (*VolumePlugin, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, errors.Wrapf(ErrNoSuchPlugin, "invalid plugin name %q", name)
+	}
+	socketPath := filepath.Join(dir, name+pluginSocketSuffix)
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrapf(ErrNoSuchPlugin, "no socket for plugin %s in %s", name, dir)
+		}
+		return nil, errors.Wrapf(err, "error accessing socket of plugin %s", name)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return nil, errors.Wrapf(ErrNoSuchPlugin, "%s is not a socket", socketPath)
+	}
+
+	plugin := newVolumePlugin(name, socketPath)
+	if err := plugin.activate(); err != nil {
+		return nil, err
+	}
+	return plugin, nil
+}
+
+// ListVolumePlugins returns the names of all plugins with a socket in the
+// plugin directory. The plugins are not activated, so they are not
+// guaranteed to be volume plugins.
+func ListVolumePlugins(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, errors.Wrapf(err, "error reading plugin directory %s", dir)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Mode()&os.ModeSocket == 0 || !strings.HasSuffix(entry.Name(), pluginSocketSuffix) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), pluginSocketSuffix))
+	}
+	return names, nil
+}
+
+func newVolumePlugin(name, socketPath string) *VolumePlugin {
+	return &VolumePlugin{
+		Name:       name,
+		SocketPath: socketPath,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+				DisableKeepAlives: true,
+			},
+			Timeout: defaultTimeout,
+		},
+	}
+}
+
+// activate activates the plugin and verifies that it implements the volume
+// driver interface
+func (p *VolumePlugin) activate() error {
+	response := new(activateResponse)
+	if err := p.sendRequest(activatePath, struct{}{}, response); err != nil {
+		return err
+	}
+	for _, implements := range response.Implements {
+		if implements == volumePluginType {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrNotVolumePlugin, "plugin %s implements %v", p.Name, response.Implements)
+}
+
+// sendRequest sends a request to the plugin and decodes its response into
+// response, which may be nil if the response only reports errors
+func (p *VolumePlugin) sendRequest(path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "error encoding request to plugin %s", p.Name)
+	}
+
+	logrus.Debugf("Sending %s request to volume plugin %s", path, p.Name)
+	req, err := http.NewRequest(http.MethodPost, "http://plugin"+path, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "error creating request to plugin %s", p.Name)
+	}
+	req.Header.Set("Accept", pluginContentType)
+	req.Header.Set("Content-Type", pluginContentType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error sending %s request to plugin %s", path, p.Name)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "error reading response of plugin %s", p.Name)
+	}
+
+	// Plugins report failures in the Err field, usually with an error
+	// status code, so check it first
+	errResp := new(errorResponse)
+	if err := json.Unmarshal(respBody, errResp); err == nil && errResp.Err != "" {
+		return errors.Wrapf(ErrPluginRequest, "plugin %s: %s", p.Name, errResp.Err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrapf(ErrPluginRequest, "plugin %s answered %s request with status %s", p.Name, path, resp.Status)
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(respBody, response); err != nil {
+			return errors.Wrapf(err, "error decoding response of plugin %s", p.Name)
+		}
+	}
+	return nil
+}
+
+// CreateVolume creates a volume with the given name and driver specific
+// options
+func (p *VolumePlugin) CreateVolume(name string, options map[string]string) error {
+	return p.sendRequest(createPath, &volumeRequest{Name: name, Opts: options}, nil)
+}
+
+// RemoveVolume removes a volume
+func (p *VolumePlugin) RemoveVolume(name string) error {
+	return p.sendRequest(removePath, &volumeRequest{Name: name}, nil)
+}
+
+// MountVolume mounts a volume on the host, and returns the path it is
+// mounted at. The ID identifies the user of the mount, and must be passed to
+// UnmountVolume once the volume is no longer used.
+func (p *VolumePlugin) MountVolume(name, id string) (string, error) {
+	response := new(pathResponse)
+	if err := p.sendRequest(mountPath, &volumeRequest{Name: name, ID: id}, response); err != nil {
+		return "", err
+	}
+	if response.Mountpoint == "" {
+		return "", errors.Wrapf(ErrPluginRequest, "plugin %s did not return a mountpoint for volume %s", p.Name, name)
+	}
+	return response.Mountpoint, nil
+}
+
+// UnmountVolume releases a mount of a volume made with MountVolume
+func (p *VolumePlugin) UnmountVolume(name, id string) error {
+	return p.sendRequest(unmountPath, &volumeRequest{Name: name, ID: id}, nil)
+}
+
+// GetVolumePath returns the path a volume is mounted at on the host
+func (p *VolumePlugin) GetVolumePath(name string) (string, error) {
+	response := new(pathResponse)
+	if err := p.sendRequest(pathPath, &volumeRequest{Name: name}, response); err != nil {
+		return "", err
+	}
+	return response.Mountpoint, nil
+}
+
+// GetVolume returns a volume of the plugin
+func (p *VolumePlugin) GetVolume(name string) (*Volume, error) {
+	response := new(getResponse)
+	if err := p.sendRequest(getPath, &volumeRequest{Name: name}, response); err != nil {
+		return nil, err
+	}
+	if response.Volume == nil {
+		return nil, errors.Wrapf(ErrPluginRequest, "plugin %s did not return volume %s", p.Name, name)
+	}
+	return response.Volume, nil
+}
+
+// ListVolumes returns all volumes of the plugin
+func (p *VolumePlugin) ListVolumes() ([]*Volume, error) {
+	response := new(listResponse)
+	if err := p.sendRequest(listPath, struct{}{}, response); err != nil {
+		return nil, err
+	}
+	return response.Volumes, nil
+}
+
+// Capabilities returns the capabilities of the plugin
+func (p *VolumePlugin) Capabilities() (Capabilities, error) {
+	response := new(capabilitiesResponse)
+	if err := p.sendRequest(capabilitiesPath, struct{}{}, response); err != nil {
+		return Capabilities{}, err
+	}
+	return response.Capabilities, nil
+}
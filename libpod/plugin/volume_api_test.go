@@ -0,0 +1,207 @@
+package plugin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVolumePlugin implements the volume plugin protocol, keeping its volumes
+// in memory
+type fakeVolumePlugin struct {
+	lock       sync.Mutex
+	implements []string
+	volumes    map[string]map[string]string
+	mounts     map[string]map[string]bool
+}
+
+func (f *fakeVolumePlugin) handle(handler func(req *volumeRequest) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		req := new(volumeRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response, err := handler(req)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response = &errorResponse{Err: err.Error()}
+		}
+		w.Header().Set("Content-Type", pluginContentType)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (f *fakeVolumePlugin) serve(t *testing.T, socketPath string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(activatePath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		return &activateResponse{Implements: f.implements}, nil
+	}))
+	mux.HandleFunc(createPath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		if _, ok := f.volumes[req.Name]; ok {
+			return nil, errors.Errorf("volume %s exists", req.Name)
+		}
+		f.volumes[req.Name] = req.Opts
+		f.mounts[req.Name] = make(map[string]bool)
+		return struct{}{}, nil
+	}))
+	mux.HandleFunc(removePath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		if _, ok := f.volumes[req.Name]; !ok {
+			return nil, errors.Errorf("no volume %s", req.Name)
+		}
+		delete(f.volumes, req.Name)
+		return struct{}{}, nil
+	}))
+	mux.HandleFunc(mountPath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		if _, ok := f.volumes[req.Name]; !ok {
+			return nil, errors.Errorf("no volume %s", req.Name)
+		}
+		f.mounts[req.Name][req.ID] = true
+		return &pathResponse{Mountpoint: "/mnt/" + req.Name}, nil
+	}))
+	mux.HandleFunc(unmountPath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		delete(f.mounts[req.Name], req.ID)
+		return struct{}{}, nil
+	}))
+	mux.HandleFunc(pathPath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		if len(f.mounts[req.Name]) == 0 {
+			return &pathResponse{}, nil
+		}
+		return &pathResponse{Mountpoint: "/mnt/" + req.Name}, nil
+	}))
+	mux.HandleFunc(getPath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		if _, ok := f.volumes[req.Name]; !ok {
+			return nil, errors.Errorf("no volume %s", req.Name)
+		}
+		return &getResponse{Volume: &Volume{Name: req.Name}}, nil
+	}))
+	mux.HandleFunc(listPath, f.handle(func(req *volumeRequest) (interface{}, error) {
+		response := &listResponse{}
+		for name := range f.volumes {
+			response.Volumes = append(response.Volumes, &Volume{Name: name})
+		}
+		return response, nil
+	}))
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	go http.Serve(listener, mux)
+}
+
+func startFakeVolumePlugin(t *testing.T, dir, name string, implements ...string) *fakeVolumePlugin {
+	f := &fakeVolumePlugin{
+		implements: implements,
+		volumes:    make(map[string]map[string]string),
+		mounts:     make(map[string]map[string]bool),
+	}
+	f.serve(t, filepath.Join(dir, name+pluginSocketSuffix))
+	return f
+}
+
+func TestVolumePluginLifecycle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volume-plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fake := startFakeVolumePlugin(t, dir, "fake", volumePluginType)
+
+	plugin, err := GetVolumePlugin("fake", dir)
+	require.NoError(t, err)
+	assert.Equal(t, "fake", plugin.Name)
+
+	err = plugin.CreateVolume("vol", map[string]string{"size": "1G"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"size": "1G"}, fake.volumes["vol"])
+
+	err = plugin.CreateVolume("vol", nil)
+	assert.True(t, errors.Cause(err) == ErrPluginRequest)
+
+	path, err := plugin.GetVolumePath("vol")
+	require.NoError(t, err)
+	assert.Equal(t, "", path)
+
+	path, err = plugin.MountVolume("vol", "ctr1")
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/vol", path)
+	assert.True(t, fake.mounts["vol"]["ctr1"])
+
+	path, err = plugin.GetVolumePath("vol")
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/vol", path)
+
+	err = plugin.UnmountVolume("vol", "ctr1")
+	require.NoError(t, err)
+	assert.Empty(t, fake.mounts["vol"])
+
+	volume, err := plugin.GetVolume("vol")
+	require.NoError(t, err)
+	assert.Equal(t, "vol", volume.Name)
+
+	volumes, err := plugin.ListVolumes()
+	require.NoError(t, err)
+	require.Len(t, volumes, 1)
+	assert.Equal(t, "vol", volumes[0].Name)
+
+	err = plugin.RemoveVolume("vol")
+	require.NoError(t, err)
+
+	_, err = plugin.MountVolume("vol", "ctr1")
+	assert.True(t, errors.Cause(err) == ErrPluginRequest)
+
+	// The fake plugin does not implement capabilities
+	_, err = plugin.Capabilities()
+	assert.Error(t, err)
+}
+
+func TestGetVolumePluginErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volume-plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	startFakeVolumePlugin(t, dir, "authz", "authz")
+	err = ioutil.WriteFile(filepath.Join(dir, "file.sock"), []byte{}, 0644)
+	require.NoError(t, err)
+
+	_, err = GetVolumePlugin("missing", dir)
+	assert.True(t, errors.Cause(err) == ErrNoSuchPlugin)
+
+	_, err = GetVolumePlugin("file", dir)
+	assert.True(t, errors.Cause(err) == ErrNoSuchPlugin)
+
+	_, err = GetVolumePlugin("../authz", dir)
+	assert.True(t, errors.Cause(err) == ErrNoSuchPlugin)
+
+	_, err = GetVolumePlugin("authz", dir)
+	assert.True(t, errors.Cause(err) == ErrNotVolumePlugin)
+}
+
+func TestListVolumePlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volume-plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	names, err := ListVolumePlugins(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	startFakeVolumePlugin(t, dir, "one", volumePluginType)
+	startFakeVolumePlugin(t, dir, "two", volumePluginType)
+	err = ioutil.WriteFile(filepath.Join(dir, "file.sock"), []byte{}, 0644)
+	require.NoError(t, err)
+
+	names, err = ListVolumePlugins(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, names)
+}
@@ -228,6 +228,37 @@ func (p *Pod) allContainers() ([]*Container, error) {
 	return p.runtime.state.PodContainers(p)
 }
 
+// ContainerDependencies returns the IDs of all the containers in the pod
+// mapped to the IDs of the containers they directly depend on, as given by the
+// dependency graph used to start and stop the pod
+func (p *Pod) ContainerDependencies() (map[string][]string, error) {
+	if !p.valid {
+		return nil, ErrPodRemoved
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	allCtrs, err := p.allContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := buildContainerGraph(allCtrs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error generating dependency graph for pod %s", p.ID())
+	}
+
+	deps := make(map[string][]string, len(graph.nodes))
+	for id, node := range graph.nodes {
+		ctrDeps := make([]string, 0, len(node.dependsOn))
+		for _, dep := range node.dependsOn {
+			ctrDeps = append(ctrDeps, dep.id)
+		}
+		deps[id] = ctrDeps
+	}
+	return deps, nil
+}
+
 // HasInfraContainer returns whether the pod will create an infra container
 func (p *Pod) HasInfraContainer() bool {
 	return p.config.InfraContainer.HasInfraContainer
@@ -3,6 +3,7 @@ package libpod
 import (
 	"context"
 
+	"github.com/containers/libpod/libpod/events"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/ulule/deepcopier"
@@ -59,6 +60,7 @@ func (p *Pod) Start(ctx context.Context) (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error starting some containers")
 	}
 
+	p.newPodEvent(events.Start)
 	return nil, nil
 }
 
@@ -139,6 +141,7 @@ func (p *Pod) StopWithTimeout(ctx context.Context, cleanup bool, timeout int) (m
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error stopping some containers")
 	}
 
+	p.newPodEvent(events.Stop)
 	return nil, nil
 }
 
@@ -198,6 +201,7 @@ func (p *Pod) Pause() (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error pausing some containers")
 	}
 
+	p.newPodEvent(events.Pause)
 	return nil, nil
 }
 
@@ -257,6 +261,7 @@ func (p *Pod) Unpause() (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error unpausing some containers")
 	}
 
+	p.newPodEvent(events.Unpause)
 	return nil, nil
 }
 
@@ -310,6 +315,7 @@ func (p *Pod) Restart(ctx context.Context) (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error stopping some containers")
 	}
 
+	p.newPodEvent(events.Restart)
 	return nil, nil
 }
 
@@ -368,6 +374,183 @@ func (p *Pod) Kill(signal uint) (map[string]error, error) {
 		return ctrErrors, errors.Wrapf(ErrCtrExists, "error killing some containers")
 	}
 
+	p.newPodEvent(events.Kill)
+	return nil, nil
+}
+
+// Checkpoint checkpoints all running containers within a pod, including its
+// infra container and the network namespace it holds
+// As the containers share namespaces, all of them are frozen before any of them
+// is dumped, so that the checkpoint of the pod is consistent. The containers
+// are then dumped in reverse order of their dependencies, so the infra
+// container is dumped last.
+// Paused, stopped, or created containers will be ignored.
+// Dumping stops at the first container that fails to be checkpointed, and the
+// remaining containers are thawed.
+// An error and a map[string]error are returned
+// If the error is not nil and the map is nil, an error was encountered before
+// any containers were checkpointed
+// If map is not nil, an error was encountered when checkpointing one or more
+// containers. The container ID is mapped to the error encountered. The error is
+// set to ErrCtrExists
+// If both error and the map are nil, all containers were checkpointed
+// successfully
+func (p *Pod) Checkpoint(ctx context.Context, options ContainerCheckpointOptions) (map[string]error, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.valid {
+		return nil, ErrPodRemoved
+	}
+
+	allCtrs, err := p.runtime.state.PodContainers(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build a dependency graph of containers in the pod
+	graph, err := buildContainerGraph(allCtrs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error generating dependency graph for pod %s", p.ID())
+	}
+	ordered := graph.dependencyOrder()
+
+	// We need to lock all the containers, so none of them changes state
+	// while the pod is frozen
+	for _, ctr := range allCtrs {
+		ctr.lock.Lock()
+		defer ctr.lock.Unlock()
+	}
+
+	var running []*Container
+	for _, ctr := range ordered {
+		if err := ctr.syncContainer(); err != nil {
+			return nil, err
+		}
+		if ctr.state.State == ContainerStateRunning {
+			running = append(running, ctr)
+		}
+	}
+	if len(running) == 0 {
+		return nil, errors.Wrapf(ErrCtrStateInvalid, "no containers in pod %s are running, cannot checkpoint", p.ID())
+	}
+
+	ctrErrors := make(map[string]error)
+
+	// Whatever happens, do not leave containers we froze behind
+	defer func() {
+		for _, ctr := range running {
+			if ctr.state.State != ContainerStatePaused {
+				continue
+			}
+			if err := ctr.unpause(); err != nil {
+				logrus.Errorf("Error unpausing container %s after checkpointing pod %s: %v", ctr.ID(), p.ID(), err)
+			}
+		}
+	}()
+
+	// Freeze all containers first
+	for _, ctr := range running {
+		if err := ctr.pause(); err != nil {
+			ctrErrors[ctr.ID()] = err
+			return ctrErrors, errors.Wrapf(ErrCtrExists, "error freezing containers of pod %s", p.ID())
+		}
+	}
+
+	// Dump containers before the containers they depend on
+	for i := len(running) - 1; i >= 0; i-- {
+		ctr := running[i]
+		if err := ctr.checkpoint(ctx, options); err != nil {
+			ctrErrors[ctr.ID()] = err
+			return ctrErrors, errors.Wrapf(ErrCtrExists, "error checkpointing some containers")
+		}
+	}
+
+	p.newPodEvent(events.Checkpoint)
+	return nil, nil
+}
+
+// Restore restores all containers within a pod that have been checkpointed
+// The containers are restored in order of their dependencies, so the infra
+// container and the namespaces it holds are restored first.
+// Running containers and containers without a checkpoint will be ignored.
+// If a container fails to be restored, the containers depending on it will not
+// be restored either.
+// An error and a map[string]error are returned
+// If the error is not nil and the map is nil, an error was encountered before
+// any containers were restored
+// If map is not nil, an error was encountered when restoring one or more
+// containers. The container ID is mapped to the error encountered. The error is
+// set to ErrCtrExists
+// If both error and the map are nil, all containers were restored successfully
+func (p *Pod) Restore(ctx context.Context, options ContainerCheckpointOptions) (map[string]error, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.valid {
+		return nil, ErrPodRemoved
+	}
+
+	allCtrs, err := p.runtime.state.PodContainers(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build a dependency graph of containers in the pod
+	graph, err := buildContainerGraph(allCtrs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error generating dependency graph for pod %s", p.ID())
+	}
+
+	ctrErrors := make(map[string]error)
+	restored := 0
+
+	for _, ctr := range graph.dependencyOrder() {
+		// Do not try to restore containers whose dependencies failed
+		for _, dep := range ctr.Dependencies() {
+			if _, ok := ctrErrors[dep]; ok {
+				ctrErrors[ctr.ID()] = errors.Wrapf(ErrCtrStateInvalid, "a dependency of container %s failed to restore", ctr.ID())
+				break
+			}
+		}
+		if _, ok := ctrErrors[ctr.ID()]; ok {
+			continue
+		}
+
+		// Containers are locked one at a time, as restoring a container
+		// looks up the namespaces of the containers it depends on
+		ctr.lock.Lock()
+
+		if err := ctr.syncContainer(); err != nil {
+			ctr.lock.Unlock()
+			ctrErrors[ctr.ID()] = err
+			continue
+		}
+
+		// Ignore containers that are running or were not checkpointed
+		if ctr.state.State == ContainerStateRunning || ctr.state.State == ContainerStatePaused || !ctr.hasCheckpoint() {
+			ctr.lock.Unlock()
+			continue
+		}
+
+		if err := ctr.restore(ctx, options); err != nil {
+			ctr.lock.Unlock()
+			ctrErrors[ctr.ID()] = err
+			continue
+		}
+		restored++
+
+		ctr.lock.Unlock()
+	}
+
+	if len(ctrErrors) > 0 {
+		return ctrErrors, errors.Wrapf(ErrCtrExists, "error restoring some containers")
+	}
+	if restored == 0 {
+		return nil, errors.Wrapf(ErrCtrStateInvalid, "no containers in pod %s have a checkpoint, cannot restore", p.ID())
+	}
+
+	p.newPodEvent(events.Restore)
 	return nil, nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/BurntSushi/toml"
 	is "github.com/containers/image/storage"
 	"github.com/containers/image/types"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/libpod/image"
 	"github.com/containers/libpod/libpod/lock"
 	"github.com/containers/libpod/pkg/firewall"
@@ -71,6 +72,10 @@ const (
 	DefaultSHMLockPath = "/libpod_lock"
 	// DefaultRootlessSHMLockPath is the default path for rootless SHM locks
 	DefaultRootlessSHMLockPath = "/libpod_rootless_lock"
+
+	// DefaultVolumePluginDir is the default directory holding the sockets
+	// of volume plugins
+	DefaultVolumePluginDir = "/run/containers/plugins"
 )
 
 // A RuntimeOption is a functional option which alters the Runtime created by
@@ -94,6 +99,12 @@ type Runtime struct {
 	firewallBackend firewall.FirewallBackend
 	lockManager     lock.Manager
 	configuredFrom  *runtimeConfiguredFrom
+	eventer         events.Eventer
+
+	// execProcesses tracks the runtime processes of the exec sessions
+	// started by this runtime
+	execProcesses     map[string]*execProcess
+	execProcessesLock sync.Mutex
 }
 
 // OCIRuntimePath contains information about an OCI runtime.
@@ -162,6 +173,11 @@ type RuntimeConfig struct {
 	// CNIDefaultNetwork is the network name of the default CNI network
 	// to attach pods to
 	CNIDefaultNetwork string `toml:"cni_default_network,omitempty"`
+	// FirewallBackend is the firewall backend managing the rules of
+	// container networks. Valid values are firewalld, iptables, nftables
+	// and none. If it is not set, a backend is selected based on what is
+	// available on the system.
+	FirewallBackend string `toml:"firewall_backend,omitempty"`
 	// HooksDir holds paths to the directories containing hooks
 	// configuration files. When the same filename is present in in
 	// multiple directories, the file in the directory listed last in
@@ -199,6 +215,18 @@ type RuntimeConfig struct {
 	// NumLocks is the number of locks to make available for containers and
 	// pods.
 	NumLocks uint32 `toml:"num_locks,omitempty"`
+
+	// EventsLogFilePath is where the events log is stored.
+	// If not set, it defaults to events/events.log in TmpDir.
+	EventsLogFilePath string `toml:"events_logfile_path,omitempty"`
+	// EventsJournald indicates whether events are also sent to the
+	// systemd journal, in addition to the events log file
+	EventsJournald bool `toml:"events_journald"`
+
+	// VolumePluginDir is the directory holding the sockets of volume
+	// plugins. A volume created with a driver other than "local" is
+	// managed by the plugin whose socket is named after the driver.
+	VolumePluginDir string `toml:"volume_plugin_dir"`
 }
 
 // runtimeConfiguredFrom is a struct used during early runtime init to help
@@ -258,6 +286,7 @@ var (
 		EnablePortReservation: true,
 		EnableLabeling:        true,
 		NumLocks:              2048,
+		VolumePluginDir:       DefaultVolumePluginDir,
 	}
 )
 
@@ -603,6 +632,19 @@ func makeRuntime(runtime *Runtime) (err error) {
 	logrus.Debugf("Using static dir %s", runtime.config.StaticDir)
 	logrus.Debugf("Using tmp dir %s", runtime.config.TmpDir)
 
+	// Set up the eventer, now that we know where the tmp dir is
+	if runtime.config.EventsLogFilePath == "" {
+		runtime.config.EventsLogFilePath = filepath.Join(runtime.config.TmpDir, "events", "events.log")
+	}
+	eventer, err := events.NewEventer(events.EventerOptions{
+		LogFilePath: runtime.config.EventsLogFilePath,
+		Journald:    runtime.config.EventsJournald,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error setting up events")
+	}
+	runtime.eventer = eventer
+
 	// Validate our config against the database, now that we've set our
 	// final storage configuration
 	if err := runtime.state.ValidateDBConfig(runtime); err != nil {
@@ -635,6 +677,7 @@ func makeRuntime(runtime *Runtime) (err error) {
 	}
 
 	runtime.imageRuntime = ir
+	ir.Eventer = runtime.eventer
 
 	// Setting signaturepolicypath
 	ir.SignaturePolicyPath = runtime.config.SignaturePolicyPath
@@ -700,7 +743,7 @@ func makeRuntime(runtime *Runtime) (err error) {
 	}
 
 	// Set up a firewall backend
-	backendType := ""
+	backendType := runtime.config.FirewallBackend
 	if rootless.IsRootless() {
 		backendType = "none"
 	}
@@ -1,7 +1,9 @@
 package libpod
 
 import (
+	"archive/tar"
 	"context"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
@@ -10,7 +12,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/rootless"
+	"github.com/containers/storage/pkg/archive"
 	"github.com/containers/storage/pkg/stringid"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
@@ -79,6 +83,137 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 		}
 	}
 
+	return r.setupContainer(ctx, ctr)
+}
+
+// RestoreContainer creates a container from the configuration of a container
+// checkpointed on another host, as returned by CheckpointArchiveConfig.
+// If the ID in the configuration is empty, a new one is generated.
+// The checkpoint itself is restored by calling Restore on the new container
+// with the archive as TargetFile.
+func (r *Runtime) RestoreContainer(ctx context.Context, config *ContainerConfig) (c *Container, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	if config == nil || config.Spec == nil {
+		return nil, errors.Wrapf(ErrInvalidArg, "must provide a valid container config to restore a container")
+	}
+
+	ctr := new(Container)
+	ctr.config = new(ContainerConfig)
+	ctr.state = new(ContainerState)
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error copying container config")
+	}
+	if err := json.Unmarshal(configJSON, ctr.config); err != nil {
+		return nil, errors.Wrapf(err, "error copying container config")
+	}
+
+	if ctr.config.Pod != "" || len(ctr.Dependencies()) > 0 {
+		return nil, errors.Wrapf(ErrInvalidArg, "containers in pods or depending on other containers cannot be restored from a checkpoint archive")
+	}
+
+	oldID := ctr.config.ID
+	if ctr.config.ID == "" {
+		ctr.config.ID = stringid.GenerateNonCryptoID()
+	}
+	if n := len(ctr.config.ExitCommand); n > 0 && ctr.config.ExitCommand[n-1] == oldID {
+		ctr.config.ExitCommand[n-1] = ctr.config.ID
+	}
+
+	// Anything that lived in the directories of the original container is
+	// recreated in the directories of the new one
+	oldDirs := []string{filepath.Join(r.config.TmpDir, "containers-root", oldID)}
+	if ctr.config.StaticDir != "" {
+		oldDirs = append(oldDirs, ctr.config.StaticDir)
+	}
+	inOldDirs := func(path string) bool {
+		for _, dir := range oldDirs {
+			if strings.HasPrefix(path, dir) {
+				return true
+			}
+		}
+		return false
+	}
+	if inOldDirs(ctr.config.LogPath) {
+		ctr.config.LogPath = ""
+	}
+	if ctr.config.ShmDir != "" && inOldDirs(ctr.config.ShmDir) {
+		mounts := make([]string, 0, len(ctr.config.Mounts))
+		for _, mount := range ctr.config.Mounts {
+			if mount != ctr.config.ShmDir {
+				mounts = append(mounts, mount)
+			}
+		}
+		ctr.config.Mounts = mounts
+		ctr.config.ShmDir = ""
+	}
+	if strings.HasPrefix(ctr.config.ConmonPidFile, r.store.RunRoot()) {
+		ctr.config.ConmonPidFile = ""
+	}
+	ctr.config.StaticDir = ""
+
+	ctr.config.CreatedTime = time.Now()
+	ctr.config.OCIRuntime = r.config.OCIRuntime
+	if r.config.Namespace != "" {
+		ctr.config.Namespace = r.config.Namespace
+	}
+
+	ctr.state.BindMounts = make(map[string]string)
+	ctr.runtime = r
+
+	return r.setupContainer(ctx, ctr)
+}
+
+// CheckpointArchiveConfig reads the configuration of the container a
+// checkpoint archive, as written when checkpointing with a TargetFile, was
+// taken from
+func CheckpointArchiveConfig(input string) (*ContainerConfig, error) {
+	archiveFile, err := os.Open(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening checkpoint archive %s", input)
+	}
+	defer archiveFile.Close()
+
+	stream, err := archive.DecompressStream(archiveFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading checkpoint archive %s", input)
+	}
+	defer stream.Close()
+
+	tr := tar.NewReader(stream)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.Wrapf(ErrInvalidArg, "checkpoint archive %s does not contain a container config", input)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading checkpoint archive %s", input)
+		}
+		if filepath.Clean(hdr.Name) != checkpointConfigFile {
+			continue
+		}
+		config := new(ContainerConfig)
+		if err := json.NewDecoder(tr).Decode(config); err != nil {
+			return nil, errors.Wrapf(err, "error decoding container config in checkpoint archive %s", input)
+		}
+		return config, nil
+	}
+}
+
+// setupContainer validates a container whose config has been populated,
+// allocates its lock and storage, and adds it to the state
+func (r *Runtime) setupContainer(ctx context.Context, ctr *Container) (c *Container, err error) {
+	// Restart retries are only meaningful for the on-failure policy
+	if ctr.config.RestartRetries > 0 && ctr.config.RestartPolicy != RestartPolicyOnFailure {
+		return nil, errors.Wrapf(ErrInvalidArg, "cannot set restart retries unless restart policy is %s", RestartPolicyOnFailure)
+	}
+
 	// Allocate a lock for the container
 	lock, err := r.lockManager.AllocateLock()
 	if err != nil {
@@ -162,7 +297,9 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 		}
 	}()
 
-	if rootless.IsRootless() && ctr.config.ConmonPidFile == "" {
+	// Always have conmon write its PID, so the container can be managed
+	// by systemd units generated with podman generate systemd
+	if ctr.config.ConmonPidFile == "" {
 		ctr.config.ConmonPidFile = filepath.Join(ctr.state.RunDir, "conmon.pid")
 	}
 
@@ -177,10 +314,10 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 				if err != nil {
 					logrus.Errorf("error creating named volume %q: %v", vol.Source, err)
 				}
-				ctr.config.Spec.Mounts[i].Source = newVol.MountPoint()
+				ctr.config.Spec.Mounts[i].Source = newVol.MountSource()
 				continue
 			}
-			ctr.config.Spec.Mounts[i].Source = volInfo.MountPoint()
+			ctr.config.Spec.Mounts[i].Source = volInfo.MountSource()
 		}
 	}
 
@@ -218,6 +355,7 @@ func (r *Runtime) newContainer(ctx context.Context, rSpec *spec.Spec, options ..
 			return nil, err
 		}
 	}
+	ctr.newContainerEvent(events.Create)
 	return ctr, nil
 }
 
@@ -311,7 +449,7 @@ func (r *Runtime) removeContainer(ctx context.Context, c *Container, force bool)
 	}
 
 	// Check that all of our exec sessions have finished
-	if len(c.state.ExecSessions) != 0 {
+	if len(c.activeExecSessions()) != 0 {
 		if force {
 			if err := r.ociRuntime.execStopContainer(c, c.StopTimeout()); err != nil {
 				return err
@@ -395,9 +533,23 @@ func (r *Runtime) removeContainer(ctx context.Context, c *Container, force bool)
 		}
 	}
 
+	c.newContainerEvent(events.Remove)
 	return cleanupErr
 }
 
+// RenameContainer changes the name of the given container
+// The new name must not be in use by another container or pod
+func (r *Runtime) RenameContainer(ctr *Container, newName string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return ErrRuntimeStopped
+	}
+
+	return ctr.Rename(newName)
+}
+
 // GetContainer retrieves a container by its ID
 func (r *Runtime) GetContainer(id string) (*Container, error) {
 	r.lock.RLock()
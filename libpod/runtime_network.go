@@ -0,0 +1,159 @@
+package libpod
+
+import (
+	"context"
+	"strings"
+
+	"github.com/containers/libpod/pkg/network"
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Contains the public Runtime API for CNI networks
+
+// CreateNetwork writes the configuration of a new CNI network to the CNI
+// configuration directory of the runtime
+func (r *Runtime) CreateNetwork(options network.CreateOptions) (*network.Network, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	return network.Create(r.config.CNIConfigDir, options)
+}
+
+// GetNetwork retrieves a CNI network by its name
+func (r *Runtime) GetNetwork(name string) (*network.Network, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	return network.Get(r.config.CNIConfigDir, name)
+}
+
+// Networks retrieves all CNI networks configured in the CNI configuration
+// directory of the runtime
+func (r *Runtime) Networks() ([]*network.Network, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if !r.valid {
+		return nil, ErrRuntimeStopped
+	}
+
+	return network.List(r.config.CNIConfigDir)
+}
+
+// RemoveNetwork removes the configuration of a CNI network
+// If force is specified, containers using the network are removed first, along
+// with their pods if they are infra containers. Otherwise, a network used by
+// containers will not be removed.
+func (r *Runtime) RemoveNetwork(ctx context.Context, name string, force bool) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.valid {
+		return ErrRuntimeStopped
+	}
+
+	removed, err := network.Get(r.config.CNIConfigDir, name)
+	if err != nil {
+		return err
+	}
+
+	users, err := r.networkUsers(name)
+	if err != nil {
+		return err
+	}
+	if len(users) != 0 {
+		names := make([]string, 0, len(users))
+		for _, ctr := range users {
+			names = append(names, ctr.ID())
+		}
+		if !force {
+			return errors.Wrapf(ErrNetworkBeingUsed, "network %s is being used by the following container(s): %s", name, strings.Join(names, ", "))
+		}
+		for _, ctr := range users {
+			if ctr.IsInfra() {
+				pod, err := r.state.Pod(ctr.PodID())
+				if err != nil {
+					return errors.Wrapf(err, "error retrieving pod of infra container %s", ctr.ID())
+				}
+				logrus.Debugf("Removing pod %s using network %s", pod.ID(), name)
+				pod.lock.Lock()
+				err = r.removePod(ctx, pod, true, true)
+				pod.lock.Unlock()
+				if err != nil {
+					return errors.Wrapf(err, "error removing pod %s using network %s", pod.ID(), name)
+				}
+				continue
+			}
+			logrus.Debugf("Removing container %s using network %s", ctr.ID(), name)
+			if err := r.removeContainer(ctx, ctr, true); err != nil {
+				return errors.Wrapf(err, "error removing container %s using network %s", ctr.ID(), name)
+			}
+		}
+	}
+
+	if err := network.Remove(r.config.CNIConfigDir, name); err != nil {
+		return err
+	}
+
+	// Bridges are left behind by the bridge plugin once the last
+	// container leaves them
+	remaining, err := network.List(r.config.CNIConfigDir)
+	if err != nil {
+		return err
+	}
+	inUse := make(map[string]bool)
+	for _, other := range remaining {
+		for _, bridge := range other.Bridges() {
+			inUse[bridge] = true
+		}
+	}
+	for _, bridge := range removed.Bridges() {
+		if inUse[bridge] {
+			continue
+		}
+		if err := removeBridge(bridge); err != nil {
+			logrus.Warnf("Error removing bridge %s of network %s: %v", bridge, name, err)
+		}
+	}
+	return nil
+}
+
+// networkUsers returns the containers attached to the CNI network with the
+// given name. Containers without explicit networks are attached to the
+// default network, unless they are rootless containers networked by
+// slirp4netns instead of CNI.
+func (r *Runtime) networkUsers(name string) ([]*Container, error) {
+	ctrs, err := r.state.AllContainers()
+	if err != nil {
+		return nil, err
+	}
+	var users []*Container
+	for _, ctr := range ctrs {
+		if !ctr.config.CreateNetNS || ctr.config.NetMode == "slirp4netns" {
+			continue
+		}
+		if util.StringInSlice(name, ctr.networks()) {
+			users = append(users, ctr)
+		}
+	}
+	return users, nil
+}
+
+// defaultNetwork returns the name of the network containers that are not
+// given any networks are attached to
+func (r *Runtime) defaultNetwork() string {
+	if r.netPlugin != nil {
+		return r.netPlugin.GetDefaultNetworkName()
+	}
+	return r.config.CNIDefaultNetwork
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/containerd/cgroups"
+	"github.com/containers/libpod/libpod/events"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -102,6 +103,7 @@ func (r *Runtime) NewPod(ctx context.Context, options ...PodCreateOption) (*Pod,
 	if err := r.state.AddPod(pod); err != nil {
 		return nil, errors.Wrapf(err, "error adding pod to state")
 	}
+	pod.newPodEvent(events.Create)
 
 	if pod.HasInfraContainer() {
 		ctr, err := r.createInfraContainer(ctx, pod)
@@ -179,7 +181,7 @@ func (r *Runtime) removePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 		}
 
 		// If the container has active exec sessions and force is not set we can't do anything
-		if len(ctr.state.ExecSessions) != 0 && !force {
+		if len(ctr.activeExecSessions()) != 0 && !force {
 			return errors.Wrapf(ErrCtrStateInvalid, "pod %s contains container %s which has active exec sessions", p.ID(), ctr.ID())
 		}
 
@@ -217,7 +219,7 @@ func (r *Runtime) removePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 				}
 			}
 			// If the container has active exec sessions, stop them now
-			if len(ctr.state.ExecSessions) != 0 {
+			if len(ctr.activeExecSessions()) != 0 {
 				if err := r.ociRuntime.execStopContainer(ctr, ctr.StopTimeout()); err != nil {
 					return err
 				}
@@ -259,6 +261,23 @@ func (r *Runtime) removePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 		return err
 	}
 
+	// The removed containers no longer use their named volumes
+	for _, ctr := range ctrs {
+		for _, m := range ctr.config.Spec.Mounts {
+			volumeName, _, ok := ctr.namedVolumeFromSource(m.Source)
+			if !ok {
+				continue
+			}
+			vol, err := r.state.Volume(volumeName)
+			if err == nil {
+				err = r.state.RemoveVolCtrDep(vol, ctr.ID())
+			}
+			if err != nil && errors.Cause(err) != ErrNoSuchVolume {
+				logrus.Errorf("Error removing container %s from the users of volume %s: %v", ctr.ID(), volumeName, err)
+			}
+		}
+	}
+
 	// Mark containers invalid
 	for _, ctr := range ctrs {
 		ctr.valid = false
@@ -313,6 +332,26 @@ func (r *Runtime) removePod(ctx context.Context, p *Pod, removeCtrs, force bool)
 
 	// Mark pod invalid
 	p.valid = false
+	p.newPodEvent(events.Remove)
+
+	// Remove the volumes of the pod, now that its containers are gone.
+	// The pod is already removed, so errors are only logged.
+	volumes, err := r.state.AllVolumes()
+	if err != nil {
+		logrus.Errorf("Error retrieving volumes of pod %s: %v", p.ID(), err)
+		return nil
+	}
+	for _, vol := range volumes {
+		if vol.config.Pod != p.ID() {
+			continue
+		}
+		vol.lock.Lock()
+		err := r.removeVolume(ctx, vol, false, false)
+		vol.lock.Unlock()
+		if err != nil {
+			logrus.Errorf("Error removing volume %s of pod %s: %v", vol.Name(), p.ID(), err)
+		}
+	}
 
 	return nil
 }
@@ -2,6 +2,8 @@ package libpod
 
 import (
 	"context"
+
+	"github.com/containers/libpod/libpod/plugin"
 )
 
 // Contains the public Runtime API for volumes
@@ -15,6 +17,11 @@ type VolumeCreateOption func(*Volume) error
 // include the volume, a false return will exclude it.
 type VolumeFilter func(*Volume) bool
 
+// getVolumePlugin returns the volume plugin with the given name
+func (r *Runtime) getVolumePlugin(name string) (*plugin.VolumePlugin, error) {
+	return plugin.GetVolumePlugin(name, r.config.VolumePluginDir)
+}
+
 // RemoveVolume removes a volumes
 func (r *Runtime) RemoveVolume(ctx context.Context, v *Volume, force, prune bool) error {
 	r.lock.Lock()
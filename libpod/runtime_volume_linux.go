@@ -7,7 +7,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/storage/pkg/stringid"
 	"github.com/opencontainers/selinux/go-selinux/label"
 	"github.com/pkg/errors"
@@ -41,29 +43,61 @@ func (r *Runtime) newVolume(ctx context.Context, options ...VolumeCreateOption)
 	if volume.config.Name == "" {
 		volume.config.Name = stringid.GenerateNonCryptoID()
 	}
-	// TODO: support for other volume drivers
+	volume.config.CreatedTime = time.Now()
 	if volume.config.Driver == "" {
-		volume.config.Driver = "local"
+		volume.config.Driver = LocalVolumeDriver
 	}
 	// TODO: determine when the scope is global and set it to that
 	if volume.config.Scope == "" {
 		volume.config.Scope = "local"
 	}
 
-	// Create the mountpoint of this volume
+	// Volumes of plugins are created by the plugin, and only mounted when
+	// a container uses them. Their mountpoint under the volume path is
+	// never created, but it identifies the volume in container mounts.
+	// The path the plugin reports for them is recorded in their state.
 	fullVolPath := filepath.Join(r.config.VolumePath, volume.config.Name, "_data")
-	if err := os.MkdirAll(fullVolPath, 0755); err != nil {
-		return nil, errors.Wrapf(err, "error creating volume directory %q", fullVolPath)
-	}
-	_, mountLabel, err := label.InitLabels([]string{})
-	if err != nil {
-		return nil, errors.Wrapf(err, "error getting default mountlabels")
-	}
-	if err := label.ReleaseLabel(mountLabel); err != nil {
-		return nil, errors.Wrapf(err, "error releasing label %q", mountLabel)
-	}
-	if err := label.Relabel(fullVolPath, mountLabel, true); err != nil {
-		return nil, errors.Wrapf(err, "error setting selinux label to %q", fullVolPath)
+	if volume.UsesPlugin() {
+		volPlugin, err := volume.plugin()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error retrieving plugin for volume driver %s", volume.config.Driver)
+		}
+		if caps, err := volPlugin.Capabilities(); err == nil && caps.Scope != "" {
+			volume.config.Scope = caps.Scope
+		}
+		if err := volPlugin.CreateVolume(volume.config.Name, volume.config.Options); err != nil {
+			return nil, errors.Wrapf(err, "error creating volume %s", volume.config.Name)
+		}
+		if path, err := volPlugin.GetVolumePath(volume.config.Name); err == nil {
+			volume.state.PluginMountPoint = path
+		} else {
+			logrus.Debugf("Error getting path of volume %s from plugin %s: %v", volume.config.Name, volPlugin.Name, err)
+		}
+		defer func() {
+			if !volume.valid {
+				if err := volPlugin.RemoveVolume(volume.config.Name); err != nil {
+					logrus.Errorf("Error removing volume %s from plugin %s: %v", volume.config.Name, volPlugin.Name, err)
+				}
+			}
+		}()
+	} else {
+		if err := volume.validateLocalOptions(); err != nil {
+			return nil, err
+		}
+		// Create the mountpoint of this volume
+		if err := os.MkdirAll(fullVolPath, 0755); err != nil {
+			return nil, errors.Wrapf(err, "error creating volume directory %q", fullVolPath)
+		}
+		_, mountLabel, err := label.InitLabels([]string{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting default mountlabels")
+		}
+		if err := label.ReleaseLabel(mountLabel); err != nil {
+			return nil, errors.Wrapf(err, "error releasing label %q", mountLabel)
+		}
+		if err := label.Relabel(fullVolPath, mountLabel, true); err != nil {
+			return nil, errors.Wrapf(err, "error setting selinux label to %q", fullVolPath)
+		}
 	}
 	volume.config.MountPoint = fullVolPath
 
@@ -78,8 +112,10 @@ func (r *Runtime) newVolume(ctx context.Context, options ...VolumeCreateOption)
 
 	// Add the volume to state
 	if err := r.state.AddVolume(volume); err != nil {
+		volume.valid = false
 		return nil, errors.Wrapf(err, "error adding volume to state")
 	}
+	volume.newVolumeEvent(events.Create)
 
 	return volume, nil
 }
@@ -112,9 +148,31 @@ func (r *Runtime) removeVolume(ctx context.Context, v *Volume, force, prune bool
 		}
 	}
 
-	// Delete the mountpoint path of the volume, that is delete the volume from /var/lib/containers/storage/volumes
-	if err := v.teardownStorage(); err != nil {
-		return errors.Wrapf(err, "error cleaning up volume storage for %q", v.Name())
+	if v.UsesPlugin() {
+		// The plugin owns the storage of the volume
+		volPlugin, err := v.plugin()
+		if err == nil {
+			err = volPlugin.RemoveVolume(v.Name())
+		}
+		if err != nil {
+			if !force {
+				return errors.Wrapf(err, "error removing volume %s from driver %s", v.Name(), v.Driver())
+			}
+			logrus.Errorf("Error removing volume %s from driver %s: %v", v.Name(), v.Driver(), err)
+		}
+	} else {
+		// Forcibly removed volumes may still be mounted for their
+		// former users. Removing the storage of a mounted volume would
+		// remove the contents of the device it was mounted from.
+		if v.needsMount() {
+			if err := v.unmountLocal(); err != nil {
+				return err
+			}
+		}
+		// Delete the mountpoint path of the volume, that is delete the volume from /var/lib/containers/storage/volumes
+		if err := v.teardownStorage(); err != nil {
+			return errors.Wrapf(err, "error cleaning up volume storage for %q", v.Name())
+		}
 	}
 
 	// Remove the volume from the state
@@ -124,6 +182,7 @@ func (r *Runtime) removeVolume(ctx context.Context, v *Volume, force, prune bool
 
 	// Set volume as invalid so it can no longer be used
 	v.valid = false
+	v.newVolumeEvent(events.Remove)
 
 	return nil
 }
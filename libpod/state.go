@@ -1,5 +1,7 @@
 package libpod
 
+import "github.com/pkg/errors"
+
 // DBConfig is a set of Libpod runtime configuration settings that are saved
 // in a State when it is first created, and can subsequently be retrieved.
 type DBConfig struct {
@@ -28,7 +30,7 @@ type State interface {
 	// connections) that may be required
 	Close() error
 
-	// Refresh clears container and pod states after a reboot
+	// Refresh clears container, pod and volume states after a reboot
 	Refresh() error
 
 	// GetDBConfig retrieves several paths configured within the database
@@ -84,6 +86,21 @@ type State interface {
 	// SaveContainer saves a container's current state to the backing store.
 	// The container must be part of the set namespace.
 	SaveContainer(ctr *Container) error
+	// RewriteContainerConfig replaces the configuration of the given
+	// container with newCfg.
+	// This is intended for the few settings that may change after a
+	// container is created, such as its resource limits. The ID, name,
+	// namespace and pod of the container must not be changed with it.
+	// The container must be part of the set namespace.
+	RewriteContainerConfig(ctr *Container, newCfg *ContainerConfig) error
+	// RenameContainer changes the name of the given container to newName.
+	// The new name must be globally unique - as with AddContainer, pod
+	// names conflict with container names.
+	// The container's configuration is updated with the new name on
+	// success. The rename is atomic: on failure, the container keeps its
+	// old name.
+	// The container must be part of the set namespace.
+	RenameContainer(ctr *Container, newName string) error
 	// ContainerInUse checks if other containers depend upon a given
 	// container.
 	// It returns a slice of the IDs of containers which depend on the given
@@ -174,6 +191,22 @@ type State interface {
 	// RemoveVolume removes the specified volume.
 	// Only volumes that have no container dependencies can be removed
 	RemoveVolume(volume *Volume) error
+	// UpdateVolume updates a volume's state from the database.
+	UpdateVolume(volume *Volume) error
+	// SaveVolume saves a volume's state to the database.
+	SaveVolume(volume *Volume) error
 	// AllVolumes returns all the volumes available in the state
 	AllVolumes() ([]*Volume, error)
 }
+
+// checkConfigRewrite verifies that a rewrite of a container's configuration
+// does not change any of the fields the state indexes containers by
+func checkConfigRewrite(oldCfg, newCfg *ContainerConfig) error {
+	if newCfg == nil {
+		return errors.Wrapf(ErrInvalidArg, "must provide a new configuration for container %s", oldCfg.ID)
+	}
+	if newCfg.ID != oldCfg.ID || newCfg.Name != oldCfg.Name || newCfg.Namespace != oldCfg.Namespace || newCfg.Pod != oldCfg.Pod {
+		return errors.Wrapf(ErrInvalidArg, "cannot change the ID, name, namespace or pod of container %s when rewriting its configuration", oldCfg.ID)
+	}
+	return nil
+}
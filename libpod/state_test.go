@@ -813,6 +813,153 @@ func TestSaveContainerNotInNamespaceReturnsError(t *testing.T) {
 	})
 }
 
+func TestRenameContainer(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testCtr, err := getTestCtr1(manager)
+		assert.NoError(t, err)
+
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+
+		err = state.RenameContainer(testCtr, "renamed")
+		assert.NoError(t, err)
+		assert.Equal(t, "renamed", testCtr.Name())
+
+		_, err = state.LookupContainer("test1")
+		assert.Error(t, err)
+
+		retrievedCtr, err := state.LookupContainer("renamed")
+		require.NoError(t, err)
+		assert.Equal(t, testCtr.ID(), retrievedCtr.ID())
+		assert.Equal(t, "renamed", retrievedCtr.Name())
+	})
+}
+
+func TestRenameContainerToNameInUseFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testCtr1, err := getTestCtr1(manager)
+		assert.NoError(t, err)
+		testCtr2, err := getTestCtr2(manager)
+		assert.NoError(t, err)
+
+		err = state.AddContainer(testCtr1)
+		assert.NoError(t, err)
+		err = state.AddContainer(testCtr2)
+		assert.NoError(t, err)
+
+		err = state.RenameContainer(testCtr1, testCtr2.Name())
+		assert.Error(t, err)
+		assert.Equal(t, "test1", testCtr1.Name())
+
+		retrievedCtr, err := state.LookupContainer("test1")
+		require.NoError(t, err)
+		assert.Equal(t, testCtr1.ID(), retrievedCtr.ID())
+	})
+}
+
+func TestRenameContainerToPodNameFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testCtr, err := getTestCtr1(manager)
+		assert.NoError(t, err)
+		testPod, err := getTestPod2(manager)
+		assert.NoError(t, err)
+
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+		err = state.AddPod(testPod)
+		assert.NoError(t, err)
+
+		err = state.RenameContainer(testCtr, testPod.Name())
+		assert.Error(t, err)
+		assert.Equal(t, "test1", testCtr.Name())
+	})
+}
+
+func TestRenameContainerInPod(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testPod, err := getTestPod1(manager)
+		assert.NoError(t, err)
+
+		testCtr, err := getTestCtr2(manager)
+		assert.NoError(t, err)
+		testCtr.config.Pod = testPod.ID()
+
+		err = state.AddPod(testPod)
+		assert.NoError(t, err)
+
+		err = state.AddContainerToPod(testPod, testCtr)
+		assert.NoError(t, err)
+
+		err = state.RenameContainer(testCtr, "renamed")
+		assert.NoError(t, err)
+
+		ctrs, err := state.PodContainers(testPod)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(ctrs))
+		assert.Equal(t, "renamed", ctrs[0].Name())
+
+		// The old name must be free for reuse once the container is removed
+		err = state.RemoveContainerFromPod(testPod, testCtr)
+		assert.NoError(t, err)
+
+		_, err = state.LookupContainer("renamed")
+		assert.Error(t, err)
+	})
+}
+
+func TestRenameContainerInNamespace(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testCtr, err := getTestCtr1(manager)
+		assert.NoError(t, err)
+
+		testCtr.config.Namespace = "test1"
+
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+
+		state.SetNamespace("test1")
+
+		err = state.RenameContainer(testCtr, "renamed")
+		assert.NoError(t, err)
+
+		retrievedCtr, err := state.LookupContainer("renamed")
+		require.NoError(t, err)
+		assert.Equal(t, testCtr.ID(), retrievedCtr.ID())
+
+		_, err = state.LookupContainer("test1")
+		assert.Error(t, err)
+	})
+}
+
+func TestRenameContainerNotInNamespaceFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testCtr, err := getTestCtr1(manager)
+		assert.NoError(t, err)
+
+		testCtr.config.Namespace = "test1"
+
+		err = state.AddContainer(testCtr)
+		assert.NoError(t, err)
+
+		state.SetNamespace("test2")
+
+		err = state.RenameContainer(testCtr, "renamed")
+		assert.Error(t, err)
+		assert.Equal(t, "test1", testCtr.Name())
+	})
+}
+
+func TestRenameContainerNotInStateFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testCtr, err := getTestCtr1(manager)
+		assert.NoError(t, err)
+
+		err = state.RenameContainer(testCtr, "renamed")
+		assert.Error(t, err)
+		assert.False(t, testCtr.valid)
+	})
+}
+
 func TestRemoveContainer(t *testing.T) {
 	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
 		testCtr, err := getTestCtr1(manager)
@@ -3526,3 +3673,47 @@ func TestSaveAndUpdatePodSameNamespace(t *testing.T) {
 		testPodsEqual(t, testPod, statePod, false)
 	})
 }
+
+func TestUpdateVolumeNotInStateFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testVol, err := getTestVolume("test", manager)
+		assert.NoError(t, err)
+
+		err = state.UpdateVolume(testVol)
+		assert.Error(t, err)
+	})
+}
+
+func TestSaveVolumeNotInStateFails(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testVol, err := getTestVolume("test", manager)
+		assert.NoError(t, err)
+
+		err = state.SaveVolume(testVol)
+		assert.Error(t, err)
+	})
+}
+
+func TestSaveAndUpdateVolume(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		testVol, err := getTestVolume("test", manager)
+		assert.NoError(t, err)
+
+		err = state.AddVolume(testVol)
+		assert.NoError(t, err)
+
+		stateVol, err := state.Volume(testVol.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, testVol.config, stateVol.config)
+		assert.Equal(t, testVol.state, stateVol.state)
+
+		testVol.state.MountCount = 2
+
+		err = state.SaveVolume(testVol)
+		assert.NoError(t, err)
+
+		err = state.UpdateVolume(stateVol)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), stateVol.state.MountCount)
+	})
+}
@@ -1,11 +1,21 @@
 package libpod
 
-import "github.com/containers/libpod/libpod/lock"
+import (
+	"time"
+
+	"github.com/containers/libpod/libpod/lock"
+)
+
+// LocalVolumeDriver is the driver of volumes that are directories under the
+// runtime's volume path. Volumes with any other driver are managed by the
+// volume plugin of that name.
+const LocalVolumeDriver = "local"
 
 // Volume is the type used to create named volumes
 // TODO: all volumes should be created using this and the Volume API
 type Volume struct {
 	config *VolumeConfig
+	state  *VolumeState
 
 	valid   bool
 	runtime *Runtime
@@ -25,6 +35,26 @@ type VolumeConfig struct {
 	Driver     string            `json:"driver"`
 	Options    map[string]string `json:"options"`
 	Scope      string            `json:"scope"`
+	// Time the volume was created. Volumes created by older versions
+	// have no creation time.
+	CreatedTime time.Time `json:"createdAt"`
+	// Pod is the ID of the pod the volume belongs to, if any. The volume
+	// is removed along with the pod.
+	Pod string `json:"pod,omitempty"`
+}
+
+// VolumeState holds the volume's mutable state
+// easyjson:json
+type VolumeState struct {
+	// MountCount is the number of containers the volume is mounted for.
+	// Only volumes that need mounting before use, that is volumes of
+	// plugins and local volumes with a filesystem type, are counted. The
+	// volume is unmounted when the count drops to zero.
+	MountCount uint `json:"mountCount"`
+	// PluginMountPoint is the path the plugin of the volume last reported
+	// for its contents. Plugins may only report it while the volume is
+	// mounted.
+	PluginMountPoint string `json:"pluginMountPoint,omitempty"`
 }
 
 // Name retrieves the volume's name
@@ -41,8 +71,20 @@ func (v *Volume) Labels() map[string]string {
 	return labels
 }
 
-// MountPoint returns the volume's mountpoint on the host
+// MountPoint returns the volume's mountpoint on the host. Volumes of plugins
+// are at the path their plugin reported, which is empty if the plugin did not
+// report any.
 func (v *Volume) MountPoint() string {
+	if v.UsesPlugin() {
+		return v.state.PluginMountPoint
+	}
+	return v.config.MountPoint
+}
+
+// MountSource returns the path containers mount the volume from. Volumes of
+// plugins are mounted by their plugin when a container using them starts, so
+// the path only identifies the volume and does not exist on the host.
+func (v *Volume) MountSource() string {
 	return v.config.MountPoint
 }
 
@@ -65,3 +107,67 @@ func (v *Volume) Options() map[string]string {
 func (v *Volume) Scope() string {
 	return v.config.Scope
 }
+
+// CreatedTime returns the time the volume was created
+func (v *Volume) CreatedTime() time.Time {
+	return v.config.CreatedTime
+}
+
+// Pod returns the ID of the pod the volume belongs to, or "" if it belongs to
+// no pod
+func (v *Volume) Pod() string {
+	return v.config.Pod
+}
+
+// MountCount returns the number of containers the volume is mounted for
+func (v *Volume) MountCount() (uint, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if err := v.update(); err != nil {
+		return 0, err
+	}
+	return v.state.MountCount, nil
+}
+
+// Mounted returns whether the volume is mounted
+func (v *Volume) Mounted() (bool, error) {
+	count, err := v.MountCount()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UsesPlugin returns whether the volume is managed by a volume plugin
+func (v *Volume) UsesPlugin() bool {
+	return v.config.Driver != "" && v.config.Driver != LocalVolumeDriver
+}
+
+// UsedBy returns the IDs of the containers using the volume
+func (v *Volume) UsedBy() ([]string, error) {
+	if !v.valid {
+		return nil, ErrNoSuchVolume
+	}
+	return v.runtime.state.VolumeInUse(v)
+}
+
+// Export writes an uncompressed tar archive of the contents of the volume to
+// the given path. The ownership and extended attributes of the files,
+// including their SELinux labels, are kept in the archive.
+func (v *Volume) Export(path string) error {
+	if !v.valid {
+		return ErrNoSuchVolume
+	}
+	return v.export(path)
+}
+
+// Import extracts the tar archive at the given path into the volume,
+// restoring the ownership and extended attributes stored in the archive.
+// Files of the volume that are not in the archive are kept.
+func (v *Volume) Import(path string) error {
+	if !v.valid {
+		return ErrNoSuchVolume
+	}
+	return v.importArchive(path)
+}
@@ -0,0 +1,121 @@
+package libpod
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Filters for use with Runtime.Volumes. All of them can be combined, as
+// volumes are only returned when they match every filter given.
+
+// VolumeNameFilter matches volumes whose name contains the given string
+func VolumeNameFilter(name string) VolumeFilter {
+	return func(v *Volume) bool {
+		return strings.Contains(v.Name(), name)
+	}
+}
+
+// VolumeDriverFilter matches volumes with the given driver
+func VolumeDriverFilter(driver string) VolumeFilter {
+	return func(v *Volume) bool {
+		return v.Driver() == driver
+	}
+}
+
+// VolumeScopeFilter matches volumes with the given scope
+func VolumeScopeFilter(scope string) VolumeFilter {
+	return func(v *Volume) bool {
+		return v.Scope() == scope
+	}
+}
+
+// VolumeLabelFilter matches volumes with the given label. The label is given
+// as key or key=value; without a value, volumes with any value match.
+func VolumeLabelFilter(label string) VolumeFilter {
+	return keyValueFilter(label, (*Volume).Labels)
+}
+
+// VolumeOptionFilter matches volumes with the given driver option. The option
+// is given as key or key=value; without a value, volumes with any value match.
+func VolumeOptionFilter(option string) VolumeFilter {
+	return keyValueFilter(option, (*Volume).Options)
+}
+
+// VolumeDanglingFilter matches volumes that are not used by any container if
+// dangling is true, and volumes that are used if it is false
+func VolumeDanglingFilter(dangling bool) VolumeFilter {
+	return func(v *Volume) bool {
+		users, err := v.UsedBy()
+		if err != nil {
+			logrus.Errorf("Error getting the containers using volume %s: %v", v.Name(), err)
+			return false
+		}
+		return (len(users) == 0) == dangling
+	}
+}
+
+// VolumeCreatedBeforeFilter matches volumes created before the given time.
+// Volumes without a creation time never match, as their age is unknown.
+func VolumeCreatedBeforeFilter(createTime time.Time) VolumeFilter {
+	return func(v *Volume) bool {
+		created := v.CreatedTime()
+		return !created.IsZero() && created.Before(createTime)
+	}
+}
+
+// GenerateVolumeFilter returns the volume filter described by a filter of the
+// form key=value, as given on the command line. The supported keys are name,
+// driver, scope, label, opt, dangling and until, which takes a timestamp or a
+// duration before the current time.
+func GenerateVolumeFilter(filter string) (VolumeFilter, error) {
+	filterSplit := strings.SplitN(filter, "=", 2)
+	if len(filterSplit) < 2 {
+		return nil, errors.Wrapf(ErrInvalidArg, "filter input must be in the form of filter=value: %s is invalid", filter)
+	}
+	key, value := filterSplit[0], filterSplit[1]
+
+	switch key {
+	case "name":
+		return VolumeNameFilter(value), nil
+	case "driver":
+		return VolumeDriverFilter(value), nil
+	case "scope":
+		return VolumeScopeFilter(value), nil
+	case "label":
+		return VolumeLabelFilter(value), nil
+	case "opt":
+		return VolumeOptionFilter(value), nil
+	case "dangling":
+		dangling, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidArg, "dangling filter value must be a boolean: %s is invalid", value)
+		}
+		return VolumeDanglingFilter(dangling), nil
+	case "until":
+		until, err := util.ParseInputTime(value)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidArg, "until filter value must be a timestamp or duration: %s is invalid", value)
+		}
+		return VolumeCreatedBeforeFilter(until), nil
+	}
+	return nil, errors.Wrapf(ErrInvalidArg, "%s is an invalid volume filter", key)
+}
+
+// keyValueFilter matches volumes whose map returned by get has the key given
+// in filter, which is of the form key or key=value
+func keyValueFilter(filter string, get func(*Volume) map[string]string) VolumeFilter {
+	filterSplit := strings.SplitN(filter, "=", 2)
+	key := filterSplit[0]
+	return func(v *Volume) bool {
+		value, ok := get(v)[key]
+		if !ok {
+			return false
+		}
+		return len(filterSplit) == 1 || filterSplit[1] == "" || value == filterSplit[1]
+	}
+}
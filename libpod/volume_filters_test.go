@@ -0,0 +1,65 @@
+package libpod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getFilterTestVolume(name string) *Volume {
+	return &Volume{
+		config: &VolumeConfig{
+			Name:        name,
+			Labels:      map[string]string{"app": "web", "tier": ""},
+			Driver:      LocalVolumeDriver,
+			Options:     map[string]string{"type": "tmpfs"},
+			Scope:       "local",
+			CreatedTime: time.Now().Add(-time.Hour),
+		},
+		valid: true,
+	}
+}
+
+func TestGenerateVolumeFilter(t *testing.T) {
+	vol := getFilterTestVolume("myvol")
+
+	for _, test := range []struct {
+		filter string
+		match  bool
+	}{
+		{"name=myvol", true},
+		{"name=vol", true},
+		{"name=other", false},
+		{"driver=local", true},
+		{"driver=plugin", false},
+		{"scope=local", true},
+		{"label=app", true},
+		{"label=app=web", true},
+		{"label=app=db", false},
+		{"label=tier", true},
+		{"label=missing", false},
+		{"opt=type=tmpfs", true},
+		{"opt=device", false},
+		{"until=30m", true},
+		{"until=2h", false},
+	} {
+		filter, err := GenerateVolumeFilter(test.filter)
+		assert.NoError(t, err, test.filter)
+		assert.Equal(t, test.match, filter(vol), test.filter)
+	}
+}
+
+func TestGenerateVolumeFilterInvalid(t *testing.T) {
+	for _, filter := range []string{"name", "unknown=value", "dangling=maybe", "until=yesterday"} {
+		_, err := GenerateVolumeFilter(filter)
+		assert.Error(t, err, filter)
+	}
+}
+
+func TestVolumeCreatedBeforeFilterWithoutCreationTime(t *testing.T) {
+	vol := getFilterTestVolume("myvol")
+	vol.config.CreatedTime = time.Time{}
+
+	assert.False(t, VolumeCreatedBeforeFilter(time.Now())(vol))
+}
@@ -3,6 +3,21 @@ package libpod
 import (
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/containers/libpod/libpod/plugin"
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/pkg/errors"
+)
+
+// Options understood by the local volume driver
+const (
+	// volumeOptType is the filesystem type the volume is mounted with
+	volumeOptType = "type"
+	// volumeOptDevice is the device the volume is mounted from
+	volumeOptDevice = "device"
+	// volumeOptMount holds the mount options of the volume
+	volumeOptMount = "o"
 )
 
 // VolumePath is the path under which all volumes that are created using the
@@ -16,6 +31,7 @@ func newVolume(runtime *Runtime) (*Volume, error) {
 	volume.runtime = runtime
 	volume.config.Labels = make(map[string]string)
 	volume.config.Options = make(map[string]string)
+	volume.state = new(VolumeState)
 
 	return volume, nil
 }
@@ -27,3 +43,74 @@ func (v *Volume) teardownStorage() error {
 	}
 	return os.RemoveAll(filepath.Join(v.runtime.config.VolumePath, v.Name()))
 }
+
+// plugin returns the volume plugin managing the volume
+func (v *Volume) plugin() (*plugin.VolumePlugin, error) {
+	return v.runtime.getVolumePlugin(v.config.Driver)
+}
+
+// update retrieves the volume's state from the database
+func (v *Volume) update() error {
+	return v.runtime.state.UpdateVolume(v)
+}
+
+// save saves the volume's state to the database
+func (v *Volume) save() error {
+	if err := v.runtime.state.SaveVolume(v); err != nil {
+		return errors.Wrapf(err, "error saving volume %s state", v.Name())
+	}
+	return nil
+}
+
+// needsMount returns whether the volume must be mounted before containers
+// can use it
+func (v *Volume) needsMount() bool {
+	return v.UsesPlugin() || v.config.Options[volumeOptType] != ""
+}
+
+// validateLocalOptions verifies that the options of a volume of the local
+// driver describe a mount the driver can make
+func (v *Volume) validateLocalOptions() error {
+	for key := range v.config.Options {
+		switch key {
+		case volumeOptType, volumeOptDevice, volumeOptMount:
+		default:
+			return errors.Wrapf(ErrInvalidArg, "invalid option %q for volume driver %s, valid options are type, device and o", key, LocalVolumeDriver)
+		}
+	}
+
+	fsType := v.config.Options[volumeOptType]
+	device := v.config.Options[volumeOptDevice]
+	if fsType == "" {
+		if len(v.config.Options) != 0 {
+			return errors.Wrapf(ErrInvalidArg, "volume options require a filesystem type")
+		}
+		return nil
+	}
+	// Rootless podman creates a new mount namespace for every command, so
+	// a volume mounted by one command would not be seen by the next
+	if rootless.IsRootless() {
+		return errors.Wrapf(ErrNotImplemented, "volumes of type %s for rootless users", fsType)
+	}
+	switch fsType {
+	case "tmpfs":
+	case "none":
+		if device == "" || !filepath.IsAbs(device) {
+			return errors.Wrapf(ErrInvalidArg, "volumes of type none must have an absolute path as device")
+		}
+		bind := false
+		for _, opt := range strings.Split(v.config.Options[volumeOptMount], ",") {
+			if opt == "bind" || opt == "rbind" {
+				bind = true
+			}
+		}
+		if !bind {
+			return errors.Wrapf(ErrInvalidArg, "volumes of type none must be mounted with the bind or rbind option")
+		}
+	default:
+		if device == "" {
+			return errors.Wrapf(ErrInvalidArg, "volumes of type %s must have a device", fsType)
+		}
+	}
+	return nil
+}
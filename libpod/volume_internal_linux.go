@@ -0,0 +1,345 @@
+// +build linux
+
+package libpod
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/containers/libpod/pkg/rootless"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/containers/storage/pkg/mount"
+	"github.com/containers/storage/pkg/stringid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// mount mounts the volume for the container with the given ID, and returns
+// the path the contents of the volume are at on the host.
+// Local volumes are mounted on their mountpoint by their first user, and stay
+// mounted until their last user unmounts them. Volumes of plugins are mounted
+// by the plugin, which keeps track of their users itself.
+func (v *Volume) mount(ctrID string) (string, error) {
+	if !v.needsMount() {
+		return v.config.MountPoint, nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if err := v.update(); err != nil {
+		return "", err
+	}
+
+	mountPoint := v.config.MountPoint
+	if v.UsesPlugin() {
+		volPlugin, err := v.plugin()
+		if err != nil {
+			return "", errors.Wrapf(err, "error retrieving plugin for volume %s", v.Name())
+		}
+		mountPoint, err = volPlugin.MountVolume(v.Name(), ctrID)
+		if err != nil {
+			return "", errors.Wrapf(err, "error mounting volume %s", v.Name())
+		}
+		v.state.PluginMountPoint = mountPoint
+	} else if v.state.MountCount == 0 {
+		if err := v.mountLocal(); err != nil {
+			return "", err
+		}
+	}
+
+	v.state.MountCount++
+	if err := v.save(); err != nil {
+		v.state.MountCount--
+		if err2 := v.release(ctrID); err2 != nil {
+			logrus.Errorf("Error unmounting volume %s: %v", v.Name(), err2)
+		}
+		return "", err
+	}
+	logrus.Debugf("Mounted volume %s for container %s at %s", v.Name(), ctrID, mountPoint)
+
+	return mountPoint, nil
+}
+
+// unmount releases the mount of the volume made for the container with the
+// given ID
+func (v *Volume) unmount(ctrID string) error {
+	if !v.needsMount() {
+		return nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if err := v.update(); err != nil {
+		return err
+	}
+
+	// The mount is forgotten even if unmounting fails, as the count could
+	// otherwise never drop to zero again
+	if v.state.MountCount > 0 {
+		v.state.MountCount--
+	}
+	if err := v.save(); err != nil {
+		return err
+	}
+	return v.release(ctrID)
+}
+
+// release unmounts the volume for the container with the given ID after its
+// mount count was decremented. Local volumes are only unmounted once their
+// count dropped to zero.
+// Must be called with the volume locked.
+func (v *Volume) release(ctrID string) error {
+	if v.UsesPlugin() {
+		volPlugin, err := v.plugin()
+		if err != nil {
+			return errors.Wrapf(err, "error retrieving plugin for volume %s", v.Name())
+		}
+		if err := volPlugin.UnmountVolume(v.Name(), ctrID); err != nil {
+			return errors.Wrapf(err, "error unmounting volume %s", v.Name())
+		}
+		return nil
+	}
+	if v.state.MountCount > 0 {
+		return nil
+	}
+	return v.unmountLocal()
+}
+
+// mountLocal mounts a volume of the local driver on its mountpoint, as
+// described by its options
+func (v *Volume) mountLocal() error {
+	if rootless.IsRootless() {
+		return errors.Wrapf(ErrNotImplemented, "mounting volume %s for rootless users", v.Name())
+	}
+	fsType := v.config.Options[volumeOptType]
+	device := v.config.Options[volumeOptDevice]
+	if device == "" && fsType == "tmpfs" {
+		device = "tmpfs"
+	}
+	if err := mount.Mount(device, v.config.MountPoint, fsType, v.config.Options[volumeOptMount]); err != nil {
+		return errors.Wrapf(err, "error mounting %s of type %s on %s for volume %s", device, fsType, v.config.MountPoint, v.Name())
+	}
+	return nil
+}
+
+// unmountLocal unmounts a volume of the local driver from its mountpoint
+func (v *Volume) unmountLocal() error {
+	if err := mount.Unmount(v.config.MountPoint); err != nil {
+		return errors.Wrapf(err, "error unmounting volume %s from %s", v.Name(), v.config.MountPoint)
+	}
+	logrus.Debugf("Unmounted volume %s from %s", v.Name(), v.config.MountPoint)
+	return nil
+}
+
+// mountForTransfer mounts the volume to access its contents outside of a
+// container. It returns the path of the contents, and a function releasing
+// the mount.
+func (v *Volume) mountForTransfer() (string, func(), error) {
+	// Plugins track the users of their volumes by ID, so give the
+	// transfer one of its own
+	id := "transfer-" + stringid.GenerateNonCryptoID()
+	mountPoint, err := v.mount(id)
+	if err != nil {
+		return "", nil, err
+	}
+	release := func() {
+		if err := v.unmount(id); err != nil {
+			logrus.Errorf("Error unmounting volume %s: %v", v.Name(), err)
+		}
+	}
+	return mountPoint, release, nil
+}
+
+// export writes a tar archive of the contents of the volume to path
+func (v *Volume) export(path string) error {
+	mountPoint, release, err := v.mountForTransfer()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	outFile, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file %q", path)
+	}
+	defer outFile.Close()
+
+	if err := writeVolumeArchive(mountPoint, outFile); err != nil {
+		return errors.Wrapf(err, "error exporting volume %s", v.Name())
+	}
+	return nil
+}
+
+// importArchive extracts the tar archive at path into the volume
+func (v *Volume) importArchive(path string) error {
+	mountPoint, release, err := v.mountForTransfer()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	inFile, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "error opening archive %q", path)
+	}
+	defer inFile.Close()
+
+	// Untar skips the root of the archive, its header is applied to the
+	// root of the volume once the rest is extracted. The archive may be a
+	// pipe, so the bytes read for the header are replayed to Untar.
+	var hdrBytes bytes.Buffer
+	rootHdr, err := tar.NewReader(io.TeeReader(inFile, &hdrBytes)).Next()
+	if err != nil && err != io.EOF {
+		return errors.Wrapf(err, "error reading archive %q", path)
+	}
+	if rootHdr != nil && filepath.Clean(rootHdr.Name) != "." {
+		rootHdr = nil
+	}
+
+	// Untar restores ownership and all extended attributes in the archive
+	if err := archive.Untar(io.MultiReader(&hdrBytes, inFile), mountPoint, &archive.TarOptions{}); err != nil {
+		return errors.Wrapf(err, "error importing archive %q into volume %s", path, v.Name())
+	}
+	if rootHdr != nil {
+		if err := applyRootHeader(mountPoint, rootHdr); err != nil {
+			return errors.Wrapf(err, "error importing archive %q into volume %s", path, v.Name())
+		}
+	}
+	return nil
+}
+
+// applyRootHeader applies the owner, mode, extended attributes and times in
+// the header of the root of an archive to dir, as Untar does for the other
+// files of the archive
+func applyRootHeader(dir string, hdr *tar.Header) error {
+	if err := os.Lchown(dir, hdr.Uid, hdr.Gid); err != nil {
+		return err
+	}
+	for key, value := range hdr.Xattrs {
+		if err := unix.Lsetxattr(dir, key, []byte(value), 0); err != nil {
+			if err == unix.ENOTSUP {
+				logrus.Warnf("Ignoring extended attribute %s of the root of the archive: %v", key, err)
+				continue
+			}
+			return errors.Wrapf(err, "error setting extended attribute %s of %q", key, dir)
+		}
+	}
+	// Chmod after chown, which can clear setuid and setgid bits
+	if err := os.Chmod(dir, hdr.FileInfo().Mode()); err != nil {
+		return err
+	}
+	accessTime := hdr.AccessTime
+	if accessTime.Before(hdr.ModTime) {
+		accessTime = hdr.ModTime
+	}
+	return os.Chtimes(dir, accessTime, hdr.ModTime)
+}
+
+// writeVolumeArchive writes a tar archive of the contents of dir to w.
+// Unlike the archives of containers/storage, which only keep the
+// security.capability attribute, the archive keeps all extended attributes
+// of the files. The archive starts with a header for dir itself, named "./",
+// so the owner, mode and extended attributes of the root of the volume are
+// kept too.
+func writeVolumeArchive(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	// Maps device and inode of files with multiple links to the name of
+	// the first link in the archive
+	links := make(map[[2]uint64]string)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && info.Mode().IsRegular() && stat.Nlink > 1 {
+			key := [2]uint64{uint64(stat.Dev), stat.Ino}
+			if target, ok := links[key]; ok {
+				hdr.Typeflag = tar.TypeLink
+				hdr.Linkname = target
+				hdr.Size = 0
+			} else {
+				links[key] = hdr.Name
+			}
+		}
+		if hdr.Xattrs, err = readXattrs(path); err != nil {
+			return errors.Wrapf(err, "error reading extended attributes of %q", path)
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.CopyN(tw, file, hdr.Size)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// readXattrs returns the extended attributes of the file at path, without
+// following symlinks
+func readXattrs(path string) (map[string]string, error) {
+	size, err := unix.Llistxattr(path, nil)
+	if err != nil {
+		if err == unix.ENOTSUP {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	names := make([]byte, size)
+	if size, err = unix.Llistxattr(path, names); err != nil {
+		return nil, err
+	}
+
+	xattrs := make(map[string]string)
+	for _, name := range strings.Split(strings.TrimRight(string(names[:size]), "\x00"), "\x00") {
+		size, err := unix.Lgetxattr(path, name, nil)
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, size)
+		if size, err = unix.Lgetxattr(path, name, value); err != nil {
+			return nil, err
+		}
+		xattrs[name] = string(value[:size])
+	}
+	return xattrs, nil
+}
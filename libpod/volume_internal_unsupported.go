@@ -0,0 +1,19 @@
+// +build !linux
+
+package libpod
+
+func (v *Volume) mount(ctrID string) (string, error) {
+	return "", ErrNotImplemented
+}
+
+func (v *Volume) unmount(ctrID string) error {
+	return ErrNotImplemented
+}
+
+func (v *Volume) export(path string) error {
+	return ErrNotImplemented
+}
+
+func (v *Volume) importArchive(path string) error {
+	return ErrNotImplemented
+}
@@ -22,15 +22,19 @@ import (
 
 // GetBackend retrieves a firewall backend for adding or removing firewall rules
 // on the system.
-// Valid backend names are firewalld, iptables, and none.
+// Valid backend names are firewalld, iptables, nftables, and none.
 // If the empty string is given, a firewalld backend will be returned if
-// firewalld is running, and an iptables backend will be returned otherwise.
+// firewalld is running, an nftables backend will be returned if the iptables
+// commands are not installed and the kernel supports nftables, and an iptables
+// backend will be returned otherwise.
 func GetBackend(backend string) (FirewallBackend, error) {
 	switch backend {
 	case "firewalld":
 		return newFirewalldBackend()
 	case "iptables":
 		return newIptablesBackend()
+	case "nftables":
+		return newNftablesBackend()
 	case "none":
 		return newNoneBackend()
 	case "":
@@ -39,6 +43,11 @@ func GetBackend(backend string) (FirewallBackend, error) {
 			return newFirewalldBackend()
 		}
 
+		// Then nftables, if it's the only thing available
+		if !isIptablesAvailable() && isNftablesAvailable() {
+			return newNftablesBackend()
+		}
+
 		// Otherwise iptables
 		return newIptablesBackend()
 	default:
@@ -22,6 +22,7 @@ package firewall
 import (
 	"fmt"
 	"net"
+	"os/exec"
 
 	"github.com/coreos/go-iptables/iptables"
 )
@@ -154,6 +155,17 @@ type iptablesBackend struct {
 // iptablesBackend implements the FirewallBackend interface
 var _ FirewallBackend = &iptablesBackend{}
 
+// isIptablesAvailable returns whether the iptables commands of both protocols
+// are installed
+func isIptablesAvailable() bool {
+	for _, cmd := range []string{"iptables", "ip6tables"} {
+		if _, err := exec.LookPath(cmd); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
 func newIptablesBackend() (FirewallBackend, error) {
 	adminChainName := "CNI-ADMIN"
 
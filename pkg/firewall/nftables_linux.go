@@ -0,0 +1,634 @@
+// +build linux
+
+package firewall
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// nfAccept is the verdict code accepting a packet
+	nfAccept = 1
+	// nftUdataRuleComment is the type nft stores the comment of a rule with
+	// in the user data of the rule
+	nftUdataRuleComment = 0
+	// ctStateEstablished and ctStateRelated are the bits of the conntrack
+	// states loaded by the ct expression
+	ctStateEstablished = 1 << 1
+	ctStateRelated     = 1 << 2
+	// nftAckTimeout is the number of seconds to wait for the kernel to
+	// acknowledge a batch
+	nftAckTimeout = 10
+	// nftJumpComment is the comment of the rules jumping to the private
+	// and admin chains, as the iptables backend sets it
+	nftJumpComment = "CNI firewall plugin rules"
+)
+
+// nftRule is a rule of the nftables backend. The comment of a rule identifies
+// it in its chain.
+type nftRule struct {
+	comment string
+	exprs   []*nl.RtAttr
+}
+
+// getPrivChainNftRules returns the rules of the private chain accepting the
+// traffic of ip, the equivalent of the rules getPrivChainRules returns
+func getPrivChainNftRules(family uint8, ip net.IPNet) []nftRule {
+	addr := []byte(ip.IP.To4())
+	saddrOffset, daddrOffset := uint32(12), uint32(16)
+	if family == unix.NFPROTO_IPV6 {
+		addr = []byte(ip.IP.To16())
+		saddrOffset, daddrOffset = 8, 24
+	}
+	ipStr := ipString(ip)
+
+	return []nftRule{
+		{
+			// ip daddr IP ct state related,established accept
+			comment: "CNI forward to " + ipStr,
+			exprs: []*nl.RtAttr{
+				nftPayloadExpr(daddrOffset, uint32(len(addr))),
+				nftCmpExpr(unix.NFT_CMP_EQ, addr),
+				nftCtStateExpr(),
+				nftBitwiseExpr(nftNativeUint32(ctStateEstablished | ctStateRelated)),
+				nftCmpExpr(unix.NFT_CMP_NEQ, nftNativeUint32(0)),
+				nftVerdictExpr(nfAccept, ""),
+			},
+		},
+		{
+			// ip saddr IP accept
+			comment: "CNI forward from " + ipStr,
+			exprs: []*nl.RtAttr{
+				nftPayloadExpr(saddrOffset, uint32(len(addr))),
+				nftCmpExpr(unix.NFT_CMP_EQ, addr),
+				nftVerdictExpr(nfAccept, ""),
+			},
+		},
+	}
+}
+
+// generateJumpNftRule returns the rule jumping to chain, the equivalent of the
+// rule generateFilterRule returns
+func generateJumpNftRule(chain string) nftRule {
+	return nftRule{
+		comment: nftJumpComment,
+		exprs:   []*nl.RtAttr{nftVerdictExpr(unix.NFT_JUMP, chain)},
+	}
+}
+
+func familyForIP(ip net.IPNet) uint8 {
+	if ip.IP.To4() != nil {
+		return unix.NFPROTO_IPV4
+	}
+	return unix.NFPROTO_IPV6
+}
+
+// setupChains adds the table of the backend and its chains to batch, along
+// with the rules jumping from the forward chain to the private chain and from
+// the private chain to the admin chain if they are missing. It returns the
+// handles of the rules of the private chain by comment.
+func (nb *nftablesBackend) setupChains(batch *nftBatch, family uint8) (map[string][]uint64, error) {
+	fwdRules, err := nftListRules(family, nb.tableName, nb.forwardChainName)
+	if err != nil {
+		return nil, err
+	}
+	privRules, err := nftListRules(family, nb.tableName, nb.privChainName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Creating an existing table or chain is not an error without
+	// NLM_F_EXCL
+	batch.addTable(family, nb.tableName)
+	batch.addBaseChain(family, nb.tableName, nb.forwardChainName, unix.NF_INET_FORWARD, 0)
+	batch.addChain(family, nb.tableName, nb.privChainName)
+	batch.addChain(family, nb.tableName, nb.adminChainName)
+
+	// Ensure our forward chain jumps to our private chain, and our private
+	// chain to our admin override chain, before anything else
+	if _, ok := fwdRules[nftJumpComment]; !ok {
+		batch.addRule(family, nb.tableName, nb.forwardChainName, generateJumpNftRule(nb.privChainName), false)
+	}
+	if _, ok := privRules[nftJumpComment]; !ok {
+		batch.addRule(family, nb.tableName, nb.privChainName, generateJumpNftRule(nb.adminChainName), false)
+	}
+
+	return privRules, nil
+}
+
+func (nb *nftablesBackend) addRules(conf *FirewallNetConf, family uint8) error {
+	var rules []nftRule
+	for _, ip := range conf.PrevResult.IPs {
+		if familyForIP(ip.Address) == family {
+			rules = append(rules, getPrivChainNftRules(family, ip.Address)...)
+		}
+	}
+
+	if len(rules) > 0 {
+		// The batch is applied atomically, nothing needs to be cleaned
+		// up on errors
+		batch := &nftBatch{}
+		existing, err := nb.setupChains(batch, family)
+		if err != nil {
+			return fmt.Errorf("failed to list nftables rules: %v", err)
+		}
+		for _, rule := range rules {
+			if _, ok := existing[rule.comment]; !ok {
+				batch.addRule(family, nb.tableName, nb.privChainName, rule, true)
+			}
+		}
+		if err := batch.send(); err != nil {
+			return fmt.Errorf("failed to add nftables rules: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (nb *nftablesBackend) delRules(conf *FirewallNetConf, family uint8) error {
+	var rules []nftRule
+	for _, ip := range conf.PrevResult.IPs {
+		if familyForIP(ip.Address) == family {
+			rules = append(rules, getPrivChainNftRules(family, ip.Address)...)
+		}
+	}
+
+	if len(rules) > 0 {
+		existing, err := nftListRules(family, nb.tableName, nb.privChainName)
+		if err != nil {
+			return fmt.Errorf("failed to list nftables rules: %v", err)
+		}
+		batch := &nftBatch{}
+		for _, rule := range rules {
+			for _, handle := range existing[rule.comment] {
+				batch.delRule(family, nb.tableName, nb.privChainName, handle)
+			}
+		}
+		if err := batch.send(); err != nil {
+			return fmt.Errorf("failed to delete nftables rules: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// nftablesBackend manages the rules of containers in a table of its own in the
+// ip and ip6 families, through the nf_tables netlink interface. The table holds
+// a base chain hooked into forwarding, which jumps to a private chain holding
+// the rules of the containers, which first jumps to an admin override chain,
+// like the chains of the iptables backend.
+// Verdicts only end the evaluation of a packet in the table they are issued in,
+// so the rules cannot accept packets another table drops, such as the filter
+// table firewall managers other than firewalld set up. A warning lists the
+// other chains filtering forwarded packets when the backend is set up.
+// The table and its chains are kept when the last rules are deleted, as other
+// processes may be adding rules, and the admin chain may hold rules of the
+// administrator.
+type nftablesBackend struct {
+	families         []uint8
+	tableName        string
+	forwardChainName string
+	privChainName    string
+	adminChainName   string
+}
+
+// nftablesBackend implements the FirewallBackend interface
+var _ FirewallBackend = &nftablesBackend{}
+
+func newNftablesBackend() (FirewallBackend, error) {
+	if _, err := nftListTables(unix.NFPROTO_IPV4); err != nil {
+		return nil, fmt.Errorf("could not initialize nftables: %v", err)
+	}
+
+	backend := &nftablesBackend{
+		families:         []uint8{unix.NFPROTO_IPV4, unix.NFPROTO_IPV6},
+		tableName:        "podman",
+		forwardChainName: "forward",
+		privChainName:    "CNI-FORWARD",
+		adminChainName:   "CNI-ADMIN",
+	}
+
+	chains, err := nftListForwardChains(backend.tableName)
+	if err != nil {
+		logrus.Debugf("Error listing nftables chains filtering forwarded packets: %v", err)
+	} else if len(chains) > 0 {
+		logrus.Warnf("nftables chains %s also filter forwarded packets, the rules of table %s cannot accept the packets they drop", strings.Join(chains, ", "), backend.tableName)
+	}
+	return backend, nil
+}
+
+// isNftablesAvailable returns whether the kernel supports nftables
+func isNftablesAvailable() bool {
+	_, err := nftListTables(unix.NFPROTO_IPV4)
+	return err == nil
+}
+
+func (nb *nftablesBackend) Add(conf *FirewallNetConf) error {
+	for _, family := range nb.families {
+		if err := nb.addRules(conf, family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (nb *nftablesBackend) Del(conf *FirewallNetConf) error {
+	var lastErr error
+	for _, family := range nb.families {
+		if err := nb.delRules(conf, family); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// nfgenmsg is the header of nfnetlink messages
+type nfgenmsg struct {
+	family uint8
+	resID  uint16
+}
+
+func (m *nfgenmsg) Len() int {
+	return 4
+}
+
+func (m *nfgenmsg) Serialize() []byte {
+	b := []byte{m.family, unix.NFNETLINK_V0, 0, 0}
+	binary.BigEndian.PutUint16(b[2:], m.resID)
+	return b
+}
+
+func nftMsgType(msg int) int {
+	return unix.NFNL_SUBSYS_NFTABLES<<8 | msg
+}
+
+// nftBatch is a list of nftables messages the kernel applies atomically
+type nftBatch struct {
+	msgs []*nl.NetlinkRequest
+}
+
+func (b *nftBatch) add(msg, flags int, family uint8, attrs ...*nl.RtAttr) {
+	req := nl.NewNetlinkRequest(nftMsgType(msg), unix.NLM_F_ACK|flags)
+	req.AddData(&nfgenmsg{family: family})
+	for _, attr := range attrs {
+		req.AddData(attr)
+	}
+	b.msgs = append(b.msgs, req)
+}
+
+func (b *nftBatch) addTable(family uint8, table string) {
+	b.add(unix.NFT_MSG_NEWTABLE, unix.NLM_F_CREATE, family,
+		nl.NewRtAttr(unix.NFTA_TABLE_NAME, nl.ZeroTerminated(table)))
+}
+
+func (b *nftBatch) addChain(family uint8, table, chain string) {
+	b.add(unix.NFT_MSG_NEWCHAIN, unix.NLM_F_CREATE, family,
+		nl.NewRtAttr(unix.NFTA_CHAIN_TABLE, nl.ZeroTerminated(table)),
+		nl.NewRtAttr(unix.NFTA_CHAIN_NAME, nl.ZeroTerminated(chain)))
+}
+
+// addBaseChain adds a filter chain accepting packets by default, hooked into
+// hook with priority
+func (b *nftBatch) addBaseChain(family uint8, table, chain string, hook uint32, priority int32) {
+	hookAttr := nl.NewRtAttr(unix.NLA_F_NESTED|unix.NFTA_CHAIN_HOOK, nil)
+	hookAttr.AddChild(nl.NewRtAttr(unix.NFTA_HOOK_HOOKNUM, nftUint32(hook)))
+	hookAttr.AddChild(nl.NewRtAttr(unix.NFTA_HOOK_PRIORITY, nftUint32(uint32(priority))))
+	b.add(unix.NFT_MSG_NEWCHAIN, unix.NLM_F_CREATE, family,
+		nl.NewRtAttr(unix.NFTA_CHAIN_TABLE, nl.ZeroTerminated(table)),
+		nl.NewRtAttr(unix.NFTA_CHAIN_NAME, nl.ZeroTerminated(chain)),
+		hookAttr,
+		nl.NewRtAttr(unix.NFTA_CHAIN_POLICY, nftUint32(nfAccept)),
+		nl.NewRtAttr(unix.NFTA_CHAIN_TYPE, nl.ZeroTerminated("filter")))
+}
+
+// addRule appends rule to chain, or inserts it at the start of the chain
+func (b *nftBatch) addRule(family uint8, table, chain string, rule nftRule, appendRule bool) {
+	flags := unix.NLM_F_CREATE
+	if appendRule {
+		flags |= unix.NLM_F_APPEND
+	}
+	exprs := nl.NewRtAttr(unix.NLA_F_NESTED|unix.NFTA_RULE_EXPRESSIONS, nil)
+	for _, expr := range rule.exprs {
+		exprs.AddChild(expr)
+	}
+	b.add(unix.NFT_MSG_NEWRULE, flags, family,
+		nl.NewRtAttr(unix.NFTA_RULE_TABLE, nl.ZeroTerminated(table)),
+		nl.NewRtAttr(unix.NFTA_RULE_CHAIN, nl.ZeroTerminated(chain)),
+		exprs,
+		nl.NewRtAttr(unix.NFTA_RULE_USERDATA, nftComment(rule.comment)))
+}
+
+func (b *nftBatch) delRule(family uint8, table, chain string, handle uint64) {
+	h := make([]byte, 8)
+	binary.BigEndian.PutUint64(h, handle)
+	b.add(unix.NFT_MSG_DELRULE, 0, family,
+		nl.NewRtAttr(unix.NFTA_RULE_TABLE, nl.ZeroTerminated(table)),
+		nl.NewRtAttr(unix.NFTA_RULE_CHAIN, nl.ZeroTerminated(chain)),
+		nl.NewRtAttr(unix.NFTA_RULE_HANDLE, h))
+}
+
+// send applies the messages of the batch, and returns the first error the
+// kernel reported
+func (b *nftBatch) send() error {
+	if len(b.msgs) == 0 {
+		return nil
+	}
+
+	s, err := nl.Subscribe(unix.NETLINK_NETFILTER)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	if err := s.SetReceiveTimeout(&unix.Timeval{Sec: nftAckTimeout}); err != nil {
+		return err
+	}
+
+	begin := nl.NewNetlinkRequest(unix.NFNL_MSG_BATCH_BEGIN, 0)
+	begin.AddData(&nfgenmsg{family: unix.AF_UNSPEC, resID: unix.NFNL_SUBSYS_NFTABLES})
+	end := nl.NewNetlinkRequest(unix.NFNL_MSG_BATCH_END, 0)
+	end.AddData(&nfgenmsg{family: unix.AF_UNSPEC, resID: unix.NFNL_SUBSYS_NFTABLES})
+
+	// Every message of the batch is acknowledged
+	pending := make(map[uint32]bool)
+	buf := begin.Serialize()
+	for _, msg := range b.msgs {
+		buf = append(buf, msg.Serialize()...)
+		pending[msg.Seq] = true
+	}
+	buf = append(buf, end.Serialize()...)
+	if err := unix.Sendto(s.GetFd(), buf, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return err
+	}
+
+	var batchErr error
+	for len(pending) > 0 {
+		msgs, err := s.Receive()
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Header.Type != unix.NLMSG_ERROR || len(m.Data) < 4 {
+				continue
+			}
+			code := int32(nl.NativeEndian().Uint32(m.Data[0:4]))
+			// Errors of the batch itself are reported on its
+			// first message
+			if m.Header.Seq == begin.Seq && code != 0 {
+				return syscall.Errno(-code)
+			}
+			if !pending[m.Header.Seq] {
+				continue
+			}
+			delete(pending, m.Header.Seq)
+			if code != 0 && batchErr == nil {
+				batchErr = syscall.Errno(-code)
+			}
+		}
+	}
+	return batchErr
+}
+
+// nftListTables returns the names of the tables of family
+func nftListTables(family uint8) ([]string, error) {
+	req := nl.NewNetlinkRequest(nftMsgType(unix.NFT_MSG_GETTABLE), unix.NLM_F_DUMP)
+	req.AddData(&nfgenmsg{family: family})
+	msgs, err := req.Execute(unix.NETLINK_NETFILTER, uint16(nftMsgType(unix.NFT_MSG_NEWTABLE)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	for _, msg := range msgs {
+		attrs, err := parseNftAttrs(msg)
+		if err != nil {
+			return nil, err
+		}
+		if name, ok := attrs[unix.NFTA_TABLE_NAME]; ok {
+			tables = append(tables, nftString(name))
+		}
+	}
+	return tables, nil
+}
+
+// nftFamilyNames are the names nft gives the families of tables filtering
+// forwarded packets
+var nftFamilyNames = map[uint8]string{
+	unix.NFPROTO_INET: "inet",
+	unix.NFPROTO_IPV4: "ip",
+	unix.NFPROTO_IPV6: "ip6",
+}
+
+// nftListForwardChains returns the base chains hooked into forwarding of the
+// tables other than skipTable, as "family table chain"
+func nftListForwardChains(skipTable string) ([]string, error) {
+	var chains []string
+	for _, family := range []uint8{unix.NFPROTO_INET, unix.NFPROTO_IPV4, unix.NFPROTO_IPV6} {
+		req := nl.NewNetlinkRequest(nftMsgType(unix.NFT_MSG_GETCHAIN), unix.NLM_F_DUMP)
+		req.AddData(&nfgenmsg{family: family})
+		msgs, err := req.Execute(unix.NETLINK_NETFILTER, uint16(nftMsgType(unix.NFT_MSG_NEWCHAIN)))
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			// Dumps of some kernels hold the chains of all families
+			if len(msg) < 1 || msg[0] != family {
+				continue
+			}
+			attrs, err := parseNftAttrs(msg)
+			if err != nil {
+				return nil, err
+			}
+			table := nftString(attrs[unix.NFTA_CHAIN_TABLE])
+			if table == skipTable {
+				continue
+			}
+			if hook, ok := nftChainHook(attrs); ok && hook == unix.NF_INET_FORWARD {
+				chains = append(chains, fmt.Sprintf("%s %s %s", nftFamilyNames[family], table, nftString(attrs[unix.NFTA_CHAIN_NAME])))
+			}
+		}
+	}
+	return chains, nil
+}
+
+// nftChainHook returns the hook of a base chain, given the attributes of the
+// chain
+func nftChainHook(attrs map[uint16][]byte) (uint32, bool) {
+	hook, ok := attrs[unix.NFTA_CHAIN_HOOK]
+	if !ok {
+		return 0, false
+	}
+	hookAttrs, err := nl.ParseRouteAttr(hook)
+	if err != nil {
+		return 0, false
+	}
+	for _, attr := range hookAttrs {
+		if attr.Attr.Type&^unix.NLA_F_NESTED == unix.NFTA_HOOK_HOOKNUM && len(attr.Value) == 4 {
+			return binary.BigEndian.Uint32(attr.Value), true
+		}
+	}
+	return 0, false
+}
+
+// nftListRules returns the handles of the rules of a chain by comment. A chain
+// that does not exist has no rules.
+func nftListRules(family uint8, table, chain string) (map[string][]uint64, error) {
+	req := nl.NewNetlinkRequest(nftMsgType(unix.NFT_MSG_GETRULE), unix.NLM_F_DUMP)
+	req.AddData(&nfgenmsg{family: family})
+	req.AddData(nl.NewRtAttr(unix.NFTA_RULE_TABLE, nl.ZeroTerminated(table)))
+	req.AddData(nl.NewRtAttr(unix.NFTA_RULE_CHAIN, nl.ZeroTerminated(chain)))
+	msgs, err := req.Execute(unix.NETLINK_NETFILTER, uint16(nftMsgType(unix.NFT_MSG_NEWRULE)))
+	if err != nil {
+		if err == syscall.ENOENT {
+			return map[string][]uint64{}, nil
+		}
+		return nil, err
+	}
+
+	rules := make(map[string][]uint64)
+	for _, msg := range msgs {
+		attrs, err := parseNftAttrs(msg)
+		if err != nil {
+			return nil, err
+		}
+		// Older kernels dump the rules of all chains
+		if nftString(attrs[unix.NFTA_RULE_TABLE]) != table || nftString(attrs[unix.NFTA_RULE_CHAIN]) != chain {
+			continue
+		}
+		handle, ok := attrs[unix.NFTA_RULE_HANDLE]
+		if !ok || len(handle) != 8 {
+			continue
+		}
+		comment := parseNftComment(attrs[unix.NFTA_RULE_USERDATA])
+		rules[comment] = append(rules[comment], binary.BigEndian.Uint64(handle))
+	}
+	return rules, nil
+}
+
+// parseNftAttrs returns the attributes of an nftables message by type
+func parseNftAttrs(msg []byte) (map[uint16][]byte, error) {
+	if len(msg) < 4 {
+		return nil, fmt.Errorf("short nftables message")
+	}
+	attrs, err := nl.ParseRouteAttr(msg[4:])
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[uint16][]byte, len(attrs))
+	for _, attr := range attrs {
+		values[attr.Attr.Type&^(unix.NLA_F_NESTED|unix.NLA_F_NET_BYTEORDER)] = attr.Value
+	}
+	return values, nil
+}
+
+func nftString(b []byte) string {
+	return strings.TrimRight(string(b), "\x00")
+}
+
+// nftComment returns the user data of a rule holding comment, as nft stores it
+func nftComment(comment string) []byte {
+	value := nl.ZeroTerminated(comment)
+	return append([]byte{nftUdataRuleComment, byte(len(value))}, value...)
+}
+
+// parseNftComment returns the comment held in the user data of a rule
+func parseNftComment(udata []byte) string {
+	for len(udata) >= 2 {
+		typ, length := udata[0], int(udata[1])
+		if len(udata) < 2+length {
+			break
+		}
+		if typ == nftUdataRuleComment {
+			return nftString(udata[2 : 2+length])
+		}
+		udata = udata[2+length:]
+	}
+	return ""
+}
+
+func nftUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+// nftNativeUint32 encodes v in host byte order, as conntrack states are
+func nftNativeUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	nl.NativeEndian().PutUint32(b, v)
+	return b
+}
+
+func nftExpr(name string, data ...*nl.RtAttr) *nl.RtAttr {
+	expr := nl.NewRtAttr(unix.NLA_F_NESTED|unix.NFTA_LIST_ELEM, nil)
+	expr.AddChild(nl.NewRtAttr(unix.NFTA_EXPR_NAME, nl.ZeroTerminated(name)))
+	exprData := nl.NewRtAttr(unix.NLA_F_NESTED|unix.NFTA_EXPR_DATA, nil)
+	for _, d := range data {
+		exprData.AddChild(d)
+	}
+	expr.AddChild(exprData)
+	return expr
+}
+
+func nftData(attrType int, value []byte) *nl.RtAttr {
+	data := nl.NewRtAttr(unix.NLA_F_NESTED|attrType, nil)
+	data.AddChild(nl.NewRtAttr(unix.NFTA_DATA_VALUE, value))
+	return data
+}
+
+// nftPayloadExpr loads length bytes at offset of the network header into the
+// first register
+func nftPayloadExpr(offset, length uint32) *nl.RtAttr {
+	return nftExpr("payload",
+		nl.NewRtAttr(unix.NFTA_PAYLOAD_DREG, nftUint32(unix.NFT_REG_1)),
+		nl.NewRtAttr(unix.NFTA_PAYLOAD_BASE, nftUint32(unix.NFT_PAYLOAD_NETWORK_HEADER)),
+		nl.NewRtAttr(unix.NFTA_PAYLOAD_OFFSET, nftUint32(offset)),
+		nl.NewRtAttr(unix.NFTA_PAYLOAD_LEN, nftUint32(length)))
+}
+
+// nftCmpExpr compares the first register with value
+func nftCmpExpr(op uint32, value []byte) *nl.RtAttr {
+	return nftExpr("cmp",
+		nl.NewRtAttr(unix.NFTA_CMP_SREG, nftUint32(unix.NFT_REG_1)),
+		nl.NewRtAttr(unix.NFTA_CMP_OP, nftUint32(op)),
+		nftData(unix.NFTA_CMP_DATA, value))
+}
+
+// nftCtStateExpr loads the conntrack state of the packet into the first
+// register
+func nftCtStateExpr() *nl.RtAttr {
+	return nftExpr("ct",
+		nl.NewRtAttr(unix.NFTA_CT_DREG, nftUint32(unix.NFT_REG_1)),
+		nl.NewRtAttr(unix.NFTA_CT_KEY, nftUint32(unix.NFT_CT_STATE)))
+}
+
+// nftBitwiseExpr masks the first register with mask
+func nftBitwiseExpr(mask []byte) *nl.RtAttr {
+	return nftExpr("bitwise",
+		nl.NewRtAttr(unix.NFTA_BITWISE_SREG, nftUint32(unix.NFT_REG_1)),
+		nl.NewRtAttr(unix.NFTA_BITWISE_DREG, nftUint32(unix.NFT_REG_1)),
+		nl.NewRtAttr(unix.NFTA_BITWISE_LEN, nftUint32(uint32(len(mask)))),
+		nftData(unix.NFTA_BITWISE_MASK, mask),
+		nftData(unix.NFTA_BITWISE_XOR, make([]byte, len(mask))))
+}
+
+// nftVerdictExpr sets the verdict of the rule. chain is the target of jumps.
+func nftVerdictExpr(code int32, chain string) *nl.RtAttr {
+	verdict := nl.NewRtAttr(unix.NLA_F_NESTED|unix.NFTA_DATA_VERDICT, nil)
+	verdict.AddChild(nl.NewRtAttr(unix.NFTA_VERDICT_CODE, nftUint32(uint32(code))))
+	if chain != "" {
+		verdict.AddChild(nl.NewRtAttr(unix.NFTA_VERDICT_CHAIN, nl.ZeroTerminated(chain)))
+	}
+	data := nl.NewRtAttr(unix.NLA_F_NESTED|unix.NFTA_IMMEDIATE_DATA, nil)
+	data.AddChild(verdict)
+	return nftExpr("immediate",
+		nl.NewRtAttr(unix.NFTA_IMMEDIATE_DREG, nftUint32(unix.NFT_REG_VERDICT)),
+		data)
+}
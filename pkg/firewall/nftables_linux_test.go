@@ -0,0 +1,78 @@
+// +build linux
+
+package firewall
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestNftComment(t *testing.T) {
+	udata := nftComment("CNI forward to 10.88.0.2/32")
+	assert.Equal(t, byte(nftUdataRuleComment), udata[0])
+	assert.Equal(t, "CNI forward to 10.88.0.2/32", parseNftComment(udata))
+
+	// Comments follow other user data
+	assert.Equal(t, "comment", parseNftComment(append([]byte{1, 2, 0, 0}, nftComment("comment")...)))
+	assert.Equal(t, "", parseNftComment([]byte{nftUdataRuleComment, 10, 'a'}))
+	assert.Equal(t, "", parseNftComment(nil))
+}
+
+func TestGetPrivChainNftRules(t *testing.T) {
+	ip4 := net.IPNet{IP: net.ParseIP("10.88.0.2"), Mask: net.CIDRMask(16, 32)}
+	assert.Equal(t, uint8(unix.NFPROTO_IPV4), familyForIP(ip4))
+	rules := getPrivChainNftRules(familyForIP(ip4), ip4)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "CNI forward to 10.88.0.2/32", rules[0].comment)
+	assert.Equal(t, "CNI forward from 10.88.0.2/32", rules[1].comment)
+
+	ip6 := net.IPNet{IP: net.ParseIP("fd00::2"), Mask: net.CIDRMask(64, 128)}
+	assert.Equal(t, uint8(unix.NFPROTO_IPV6), familyForIP(ip6))
+	rules = getPrivChainNftRules(familyForIP(ip6), ip6)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "CNI forward from fd00::2/128", rules[1].comment)
+}
+
+func TestNftBatchAddRule(t *testing.T) {
+	ip := net.IPNet{IP: net.ParseIP("10.88.0.2"), Mask: net.CIDRMask(16, 32)}
+	rule := getPrivChainNftRules(unix.NFPROTO_IPV4, ip)[1]
+
+	batch := &nftBatch{}
+	batch.addRule(unix.NFPROTO_IPV4, "podman", "CNI-FORWARD", rule, true)
+	require.Len(t, batch.msgs, 1)
+
+	msg := batch.msgs[0].Serialize()
+	assert.Equal(t, uint16(nftMsgType(unix.NFT_MSG_NEWRULE)), batch.msgs[0].Type)
+	assert.Equal(t, uint16(unix.NLM_F_REQUEST|unix.NLM_F_ACK|unix.NLM_F_CREATE|unix.NLM_F_APPEND), batch.msgs[0].Flags)
+	assert.Equal(t, byte(unix.NFPROTO_IPV4), msg[unix.SizeofNlMsghdr])
+
+	attrs, err := parseNftAttrs(msg[unix.SizeofNlMsghdr:])
+	require.NoError(t, err)
+	assert.Equal(t, "podman", nftString(attrs[unix.NFTA_RULE_TABLE]))
+	assert.Equal(t, "CNI-FORWARD", nftString(attrs[unix.NFTA_RULE_CHAIN]))
+	assert.Equal(t, rule.comment, parseNftComment(attrs[unix.NFTA_RULE_USERDATA]))
+	assert.Contains(t, attrs, uint16(unix.NFTA_RULE_EXPRESSIONS))
+}
+
+func TestNftChainHook(t *testing.T) {
+	batch := &nftBatch{}
+	batch.addBaseChain(unix.NFPROTO_IPV4, "podman", "forward", unix.NF_INET_FORWARD, 0)
+	batch.addChain(unix.NFPROTO_IPV4, "podman", "CNI-FORWARD")
+	require.Len(t, batch.msgs, 2)
+
+	attrs, err := parseNftAttrs(batch.msgs[0].Serialize()[unix.SizeofNlMsghdr:])
+	require.NoError(t, err)
+	hook, ok := nftChainHook(attrs)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(unix.NF_INET_FORWARD), hook)
+
+	// Regular chains are not hooked
+	attrs, err = parseNftAttrs(batch.msgs[1].Serialize()[unix.SizeofNlMsghdr:])
+	require.NoError(t, err)
+	_, ok = nftChainHook(attrs)
+	assert.False(t, ok)
+}
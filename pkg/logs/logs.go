@@ -143,8 +143,17 @@ func parseCRILog(log []byte, msg *logMessage) error {
 	return nil
 }
 
-// ReadLogs reads in the logs from the logPath
+// ReadLogs reads in the logs from the logPath, or from the systemd journal
+// if the container uses the journald log driver
 func ReadLogs(logPath string, ctr *libpod.Container, opts *LogOptions) error {
+	switch ctr.LogDriver() {
+	case libpod.JournaldLogging:
+		opts.bytes = -1
+		return readJournald(ctr, newLogWriter(opts), opts)
+	case libpod.NoLogging:
+		return errors.Errorf("container %s does not log its output, it uses the %q log driver", ctr.ID(), libpod.NoLogging)
+	}
+
 	file, err := os.Open(logPath)
 	if err != nil {
 		return errors.Wrapf(err, "failed to open log file %q", logPath)
@@ -0,0 +1,112 @@
+// +build linux,systemd
+
+package logs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/containers/libpod/libpod"
+	"github.com/coreos/go-systemd/sdjournal"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// journaldCtrIDField is the journal field conmon stores the full ID
+	// of the container in
+	journaldCtrIDField = "CONTAINER_ID_FULL"
+	// journaldPartialField is set by conmon on entries that do not end
+	// with a newline
+	journaldPartialField = "CONTAINER_PARTIAL_MESSAGE"
+	// journaldStderrPriority is the priority conmon uses for entries
+	// written to stderr, all other entries come from stdout
+	journaldStderrPriority = "3"
+)
+
+// readJournald reads the logs of a container using the journald log driver
+// from the systemd journal
+func readJournald(ctr *libpod.Container, writer *logWriter, opts *LogOptions) error {
+	journal, err := sdjournal.NewJournal()
+	if err != nil {
+		return errors.Wrapf(err, "unable to open the journal")
+	}
+	defer journal.Close()
+
+	if err := journal.AddMatch(fmt.Sprintf("%s=%s", journaldCtrIDField, ctr.ID())); err != nil {
+		return errors.Wrapf(err, "unable to filter the journal on container %s", ctr.ID())
+	}
+
+	// readCurrent is set when the journal is already positioned on the
+	// first entry to print
+	readCurrent := false
+	switch {
+	case opts.Tail > 0:
+		if err := journal.SeekTail(); err != nil {
+			return errors.Wrapf(err, "unable to seek to the end of the journal")
+		}
+		skipped, err := journal.PreviousSkip(opts.Tail)
+		if err != nil {
+			return errors.Wrapf(err, "unable to seek in the journal")
+		}
+		readCurrent = skipped > 0
+	case !opts.Since.IsZero():
+		if err := journal.SeekRealtimeUsec(uint64(opts.Since.UnixNano() / int64(time.Microsecond))); err != nil {
+			return errors.Wrapf(err, "unable to seek to %s in the journal", opts.Since)
+		}
+	default:
+		if err := journal.SeekHead(); err != nil {
+			return errors.Wrapf(err, "unable to seek to the start of the journal")
+		}
+	}
+
+	msg := &logMessage{}
+	for {
+		if !readCurrent {
+			n, err := journal.Next()
+			if err != nil {
+				return errors.Wrapf(err, "unable to read the journal")
+			}
+			if n == 0 {
+				if !opts.Follow {
+					return nil
+				}
+				// Check if container is still running or paused
+				state, err := ctr.State()
+				if err != nil {
+					return err
+				}
+				if state != libpod.ContainerStateRunning && state != libpod.ContainerStatePaused {
+					return nil
+				}
+				journal.Wait(time.Second)
+				continue
+			}
+		}
+		readCurrent = false
+
+		entry, err := journal.GetEntry()
+		if err != nil {
+			return errors.Wrapf(err, "unable to read journal entry")
+		}
+		msg.reset()
+		msg.timestamp = time.Unix(0, int64(entry.RealtimeTimestamp)*int64(time.Microsecond))
+		msg.stream = Stdout
+		if entry.Fields["PRIORITY"] == journaldStderrPriority {
+			msg.stream = Stderr
+		}
+		msg.log = []byte(entry.Fields["MESSAGE"])
+		if entry.Fields[journaldPartialField] != "true" {
+			msg.log = append(msg.log, eol...)
+		}
+		// Write the log line into the stream.
+		if err := writer.write(msg); err != nil {
+			if err == errMaximumWrite {
+				logrus.Infof("Finish reading the journal of container %s, hit bytes limit %d(bytes)", ctr.ID(), opts.bytes)
+				return nil
+			}
+			logrus.Errorf("Failed with err %v when writing log for container %s: %+v", err, ctr.ID(), msg)
+			return err
+		}
+	}
+}
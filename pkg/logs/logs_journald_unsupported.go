@@ -0,0 +1,13 @@
+// +build !linux !systemd
+
+package logs
+
+import (
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+)
+
+// readJournald is only supported when podman is built with the systemd tag
+func readJournald(ctr *libpod.Container, writer *logWriter, opts *LogOptions) error {
+	return errors.Errorf("reading the journald logs of container %s requires podman to be built with systemd support", ctr.ID())
+}
@@ -0,0 +1,305 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/containers/libpod/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BridgeDriver creates networks on a bridge on the host, with
+	// masquerading and port mapping
+	BridgeDriver = "bridge"
+	// MacVLANDriver creates networks of macvlan interfaces on a parent
+	// interface of the host
+	MacVLANDriver = "macvlan"
+
+	// bridgePrefix is the prefix of the names of generated bridges
+	bridgePrefix = "cni-podman"
+)
+
+var (
+	// nameRegex matches valid network names
+	nameRegex = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9_.-]*$")
+
+	// defaultSubnetPool is the range subnets of new networks are chosen
+	// from when no subnet is given. The default network of podman uses
+	// 10.88.0.0/16, which precedes the pool.
+	defaultSubnetPool = &net.IPNet{IP: net.IPv4(10, 89, 0, 0).To4(), Mask: net.CIDRMask(16, 32)}
+	// defaultSubnetSize is the prefix length of chosen subnets
+	defaultSubnetSize = 24
+
+	// driverOptions are the driver options each driver accepts
+	driverOptions = map[string][]string{
+		BridgeDriver:  {"mtu"},
+		MacVLANDriver: {"mtu", "parent", "mode"},
+	}
+	// macvlanModes are the modes of macvlan interfaces
+	macvlanModes = []string{"bridge", "private", "vepa", "passthru"}
+)
+
+// CreateOptions describe a network to create
+type CreateOptions struct {
+	// Name is the name of the network
+	Name string
+	// Driver is the driver of the network, BridgeDriver if empty
+	Driver string
+	// Subnet is the subnet addresses are allocated from. If nil, a subnet
+	// that does not overlap with other networks or host interfaces is
+	// chosen. Required for macvlan networks.
+	Subnet *net.IPNet
+	// Gateway is the gateway of the network, by default the first address
+	// of the subnet
+	Gateway net.IP
+	// IPRange restricts the addresses allocated to containers to a range
+	// within the subnet
+	IPRange *net.IPNet
+	// Internal networks have no route to the outside of the host and
+	// no port mapping
+	Internal bool
+	// Labels are arbitrary labels of the network, stored in its
+	// configuration
+	Labels map[string]string
+	// Options are driver specific options
+	Options map[string]string
+}
+
+// The plugin configurations written for created networks
+type bridgeConfig struct {
+	Type      string      `json:"type"`
+	Bridge    string      `json:"bridge"`
+	IsGateway bool        `json:"isGateway"`
+	IPMasq    bool        `json:"ipMasq"`
+	MTU       int         `json:"mtu,omitempty"`
+	IPAM      *ipamConfig `json:"ipam"`
+	// Capabilities let the static addresses of containers through to the
+	// plugin and its IPAM plugin
+	Capabilities map[string]bool `json:"capabilities,omitempty"`
+}
+
+type macvlanConfig struct {
+	Type   string      `json:"type"`
+	Master string      `json:"master"`
+	Mode   string      `json:"mode,omitempty"`
+	MTU    int         `json:"mtu,omitempty"`
+	IPAM   *ipamConfig `json:"ipam"`
+	// Capabilities as in bridgeConfig
+	Capabilities map[string]bool `json:"capabilities,omitempty"`
+}
+
+type portMapConfig struct {
+	Type         string          `json:"type"`
+	Capabilities map[string]bool `json:"capabilities"`
+}
+
+type confList struct {
+	CNIVersion string                       `json:"cniVersion"`
+	Name       string                       `json:"name"`
+	Plugins    []interface{}                `json:"plugins"`
+	Args       map[string]map[string]string `json:"args,omitempty"`
+}
+
+// Create generates the configuration of a network and writes it to the CNI
+// configuration directory
+func Create(configDir string, options CreateOptions) (*Network, error) {
+	if !nameRegex.MatchString(options.Name) {
+		return nil, errors.Errorf("invalid network name %q: names must match %s", options.Name, nameRegex.String())
+	}
+	if options.Driver == "" {
+		options.Driver = BridgeDriver
+	}
+	allowed, ok := driverOptions[options.Driver]
+	if !ok {
+		return nil, errors.Errorf("unknown network driver %q, must be %s or %s", options.Driver, BridgeDriver, MacVLANDriver)
+	}
+	for key := range options.Options {
+		if !util.StringInSlice(key, allowed) {
+			return nil, errors.Errorf("invalid option %q for driver %s, valid options are %v", key, options.Driver, allowed)
+		}
+	}
+	mtu := 0
+	if value, ok := options.Options["mtu"]; ok {
+		var err error
+		if mtu, err = strconv.Atoi(value); err != nil || mtu <= 0 {
+			return nil, errors.Errorf("invalid mtu %q", value)
+		}
+	}
+
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error creating CNI configuration directory %s", configDir)
+	}
+	networks, err := List(configDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networks {
+		if network.Name == options.Name {
+			return nil, errors.Wrapf(ErrNetworkExists, "network %s is configured in %s", options.Name, network.Path)
+		}
+	}
+	file := filepath.Join(configDir, options.Name+confListExtension)
+	if _, err := os.Stat(file); err == nil {
+		return nil, errors.Wrapf(ErrNetworkExists, "configuration file %s exists", file)
+	}
+	usedSubnets, usedBridges := usedResources(networks)
+
+	if options.Subnet == nil {
+		if options.Driver == MacVLANDriver {
+			return nil, errors.Errorf("macvlan networks require the subnet of the parent interface")
+		}
+		if options.Gateway != nil || options.IPRange != nil {
+			return nil, errors.Errorf("a gateway or IP range requires a subnet")
+		}
+		hostSubnets, err := hostSubnets()
+		if err != nil {
+			return nil, err
+		}
+		if options.Subnet, err = freeSubnet(append(usedSubnets, hostSubnets...)); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, used := range usedSubnets {
+			if overlaps(options.Subnet, used) {
+				return nil, errors.Errorf("subnet %s overlaps with subnet %s of another network", options.Subnet, used)
+			}
+		}
+	}
+	ipam, err := newIPAMConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	list := confList{
+		CNIVersion: cniVersion,
+		Name:       options.Name,
+	}
+	if len(options.Labels) > 0 {
+		list.Args = map[string]map[string]string{labelsArgKey: options.Labels}
+	}
+	switch options.Driver {
+	case BridgeDriver:
+		bridge, err := freeBridgeName(usedBridges)
+		if err != nil {
+			return nil, err
+		}
+		list.Plugins = append(list.Plugins, &bridgeConfig{
+			Type:         "bridge",
+			Bridge:       bridge,
+			IsGateway:    !options.Internal,
+			IPMasq:       !options.Internal,
+			MTU:          mtu,
+			IPAM:         ipam,
+			Capabilities: staticAddressCapabilities(),
+		})
+		if !options.Internal {
+			list.Plugins = append(list.Plugins, &portMapConfig{
+				Type:         "portmap",
+				Capabilities: map[string]bool{"portMappings": true},
+			})
+		}
+	case MacVLANDriver:
+		parent := options.Options["parent"]
+		if parent == "" {
+			return nil, errors.Errorf("macvlan networks require a parent interface")
+		}
+		if _, err := net.InterfaceByName(parent); err != nil {
+			return nil, errors.Wrapf(err, "invalid parent interface %q", parent)
+		}
+		mode := options.Options["mode"]
+		if mode != "" && !util.StringInSlice(mode, macvlanModes) {
+			return nil, errors.Errorf("invalid macvlan mode %q, must be one of %v", mode, macvlanModes)
+		}
+		list.Plugins = append(list.Plugins, &macvlanConfig{
+			Type:         "macvlan",
+			Master:       parent,
+			Mode:         mode,
+			MTU:          mtu,
+			IPAM:         ipam,
+			Capabilities: staticAddressCapabilities(),
+		})
+	}
+
+	if err := writeConfList(file, list); err != nil {
+		return nil, err
+	}
+	return loadNetwork(file)
+}
+
+// staticAddressCapabilities returns the capabilities of the main plugin of a
+// network, receiving the static IP and MAC addresses of containers as runtime
+// configuration
+func staticAddressCapabilities() map[string]bool {
+	return map[string]bool{"ips": true, "mac": true}
+}
+
+// newIPAMConfig returns the host-local IPAM configuration of a network,
+// validating the subnet, gateway and range of the options
+func newIPAMConfig(options CreateOptions) (*ipamConfig, error) {
+	subnet := options.Subnet
+	ones, bits := subnet.Mask.Size()
+	if bits-ones < 2 {
+		return nil, errors.Errorf("subnet %s is too small", subnet)
+	}
+	addrRange := ipamRange{Subnet: subnet.String()}
+
+	gateway := options.Gateway
+	if gateway == nil {
+		gateway = nextIP(subnet.IP)
+	} else if !subnet.Contains(gateway) || gateway.Equal(subnet.IP) || gateway.Equal(lastIP(subnet)) {
+		return nil, errors.Errorf("gateway %s is not a host address of subnet %s", gateway, subnet)
+	}
+	addrRange.Gateway = gateway.String()
+
+	if options.IPRange != nil {
+		rangeOnes, _ := options.IPRange.Mask.Size()
+		if !subnet.Contains(options.IPRange.IP) || rangeOnes < ones {
+			return nil, errors.Errorf("IP range %s is not in subnet %s", options.IPRange, subnet)
+		}
+		// The network and broadcast addresses of the subnet cannot
+		// be allocated
+		start, end := options.IPRange.IP, lastIP(options.IPRange)
+		if start.Equal(subnet.IP) {
+			start = nextIP(start)
+		}
+		if end.Equal(lastIP(subnet)) {
+			end = prevIP(end)
+		}
+		addrRange.RangeStart = start.String()
+		addrRange.RangeEnd = end.String()
+	}
+
+	ipam := &ipamConfig{
+		Type:   "host-local",
+		Ranges: [][]ipamRange{{addrRange}},
+	}
+	if !options.Internal {
+		dst := "0.0.0.0/0"
+		if subnet.IP.To4() == nil {
+			dst = "::/0"
+		}
+		ipam.Routes = []ipamRoute{{Dst: dst}}
+	}
+	return ipam, nil
+}
+
+// freeBridgeName returns the first bridge name that is neither used by a
+// network nor an interface of the host
+func freeBridgeName(usedBridges map[string]bool) (string, error) {
+	for i := 1; i < 1000; i++ {
+		name := fmt.Sprintf("%s%d", bridgePrefix, i)
+		if usedBridges[name] {
+			continue
+		}
+		if _, err := net.InterfaceByName(name); err == nil {
+			continue
+		}
+		return name, nil
+	}
+	return "", errors.Errorf("no free bridge name")
+}
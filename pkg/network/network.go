@@ -0,0 +1,309 @@
+package network
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/containers/storage/pkg/ioutils"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Networks are defined by CNI configuration files in the CNI configuration
+// directory. Networks created by podman are written as <name>.conflist, but
+// podman manages every valid configuration file found in the directory, as
+// ocicni does when attaching containers.
+
+const (
+	// cniVersion is the CNI specification version of generated networks
+	cniVersion = "0.3.1"
+	// confListExtension is the extension of generated network files
+	confListExtension = ".conflist"
+	// labelsArgKey is the key of the network labels in the args of a
+	// configuration list
+	labelsArgKey = "podman_labels"
+)
+
+// ipamDataDir is the directory the host-local IPAM plugin records the
+// addresses it allocated in, per network
+var ipamDataDir = "/var/lib/cni/networks"
+
+var (
+	// ErrNetworkNotFound indicates that no network with the requested name
+	// is configured
+	ErrNetworkNotFound = errors.New("no such network")
+	// ErrNetworkExists indicates that a network with the same name is
+	// already configured
+	ErrNetworkExists = errors.New("network already exists")
+)
+
+// Network is a CNI network configured in the CNI configuration directory
+type Network struct {
+	// Name is the name of the network
+	Name string `json:"name"`
+	// CNIVersion is the CNI specification version of the configuration
+	CNIVersion string `json:"cniVersion"`
+	// Plugins are the types of the plugins of the network, in the order
+	// they are invoked
+	Plugins []string `json:"plugins"`
+	// Subnets are the subnets addresses are allocated from by the
+	// host-local IPAM plugin
+	Subnets []string `json:"subnets"`
+	// Labels are the labels given to the network when it was created
+	Labels map[string]string `json:"labels"`
+	// Path is the path of the configuration file of the network
+	Path string `json:"path"`
+	// Config is the configuration list of the network as stored in its
+	// file. Configuration files holding a single plugin are converted to
+	// a list.
+	Config json.RawMessage `json:"-"`
+
+	ipNets  []*net.IPNet
+	bridges []string
+}
+
+// Bridges returns the bridge interfaces the network attaches containers to
+func (n *Network) Bridges() []string {
+	return n.bridges
+}
+
+// Driver returns the type of the main plugin of the network
+func (n *Network) Driver() string {
+	if len(n.Plugins) == 0 {
+		return ""
+	}
+	return n.Plugins[0]
+}
+
+// ipamConfig is the subset of the configuration of the host-local IPAM
+// plugin podman reads and generates
+type ipamConfig struct {
+	Type   string        `json:"type"`
+	Subnet string        `json:"subnet,omitempty"`
+	Ranges [][]ipamRange `json:"ranges,omitempty"`
+	Routes []ipamRoute   `json:"routes,omitempty"`
+}
+
+type ipamRange struct {
+	Subnet     string `json:"subnet"`
+	RangeStart string `json:"rangeStart,omitempty"`
+	RangeEnd   string `json:"rangeEnd,omitempty"`
+	Gateway    string `json:"gateway,omitempty"`
+}
+
+type ipamRoute struct {
+	Dst string `json:"dst"`
+}
+
+// pluginConfig is the subset of the configuration of a plugin podman reads
+type pluginConfig struct {
+	Type   string      `json:"type"`
+	Bridge string      `json:"bridge,omitempty"`
+	IPAM   *ipamConfig `json:"ipam,omitempty"`
+}
+
+// confListArgs holds the args of a configuration list
+type confListArgs struct {
+	Args struct {
+		Labels map[string]string `json:"podman_labels"`
+	} `json:"args"`
+}
+
+// List returns all valid networks in the CNI configuration directory, sorted
+// by the name of their files. Invalid configuration files are skipped.
+func List(configDir string) ([]*Network, error) {
+	files, err := libcni.ConfFiles(configDir, []string{".conf", ".conflist", ".json"})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading CNI configuration directory %s", configDir)
+	}
+	sort.Strings(files)
+
+	networks := make([]*Network, 0, len(files))
+	for _, file := range files {
+		network, err := loadNetwork(file)
+		if err != nil {
+			logrus.Warnf("Skipping invalid CNI configuration %s: %v", file, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// Get returns the network with the given name
+func Get(configDir, name string) (*Network, error) {
+	networks, err := List(configDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networks {
+		if network.Name == name {
+			return network, nil
+		}
+	}
+	return nil, errors.Wrapf(ErrNetworkNotFound, "no network named %s in %s", name, configDir)
+}
+
+// Remove removes the configuration file of the network with the given name,
+// and the addresses the host-local IPAM plugin recorded for it
+func Remove(configDir, name string) error {
+	network, err := Get(configDir, name)
+	if err != nil {
+		return err
+	}
+	// The name is read from a configuration file, and names the directory
+	// of the IPAM data that is removed
+	if err := validateIPAMDataName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(network.Path); err != nil {
+		return errors.Wrapf(err, "error removing configuration of network %s", name)
+	}
+	if err := os.RemoveAll(filepath.Join(ipamDataDir, name)); err != nil {
+		logrus.Warnf("Error removing IPAM data of network %s: %v", name, err)
+	}
+	return nil
+}
+
+// validateIPAMDataName verifies that the name of a network can be used as the
+// name of its directory in the IPAM data directory
+func validateIPAMDataName(name string) error {
+	if !nameRegex.MatchString(name) || strings.ContainsRune(name, os.PathSeparator) || name == "." || name == ".." {
+		return errors.Errorf("invalid network name %q: names must match %s", name, nameRegex.String())
+	}
+	return nil
+}
+
+// NewFromConfig describes the network configured by a configuration list, as
+// stored in the Config of a Network
+func NewFromConfig(path string, config []byte) (*Network, error) {
+	list, err := libcni.ConfListFromBytes(config)
+	if err != nil {
+		return nil, err
+	}
+	return newNetwork(path, list)
+}
+
+// loadNetwork reads and validates a CNI configuration file
+func loadNetwork(file string) (*Network, error) {
+	var (
+		list *libcni.NetworkConfigList
+		err  error
+	)
+	if strings.HasSuffix(file, ".conflist") {
+		list, err = libcni.ConfListFromFile(file)
+	} else {
+		var conf *libcni.NetworkConfig
+		conf, err = libcni.ConfFromFile(file)
+		if err == nil {
+			if conf.Network.Type == "" {
+				return nil, errors.Errorf("no plugin type, perhaps this is a configuration list")
+			}
+			list, err = libcni.ConfListFromConf(conf)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if list.Name == "" {
+		return nil, errors.Errorf("no network name")
+	}
+	return newNetwork(file, list)
+}
+
+// newNetwork describes a validated configuration list
+func newNetwork(file string, list *libcni.NetworkConfigList) (*Network, error) {
+	network := &Network{
+		Name:       list.Name,
+		CNIVersion: list.CNIVersion,
+		Plugins:    make([]string, 0, len(list.Plugins)),
+		Subnets:    []string{},
+		Labels:     map[string]string{},
+		Path:       file,
+		Config:     list.Bytes,
+	}
+
+	args := new(confListArgs)
+	if err := json.Unmarshal(list.Bytes, args); err == nil && args.Args.Labels != nil {
+		network.Labels = args.Args.Labels
+	}
+
+	for _, plugin := range list.Plugins {
+		config := new(pluginConfig)
+		if err := json.Unmarshal(plugin.Bytes, config); err != nil {
+			return nil, errors.Wrapf(err, "error parsing configuration of plugin %s", plugin.Network.Type)
+		}
+		network.Plugins = append(network.Plugins, config.Type)
+		for _, subnet := range config.subnets() {
+			network.ipNets = append(network.ipNets, subnet)
+			network.Subnets = append(network.Subnets, subnet.String())
+		}
+		if config.Type == "bridge" {
+			bridge := config.Bridge
+			if bridge == "" {
+				// The default of the bridge plugin
+				bridge = "cni0"
+			}
+			network.bridges = append(network.bridges, bridge)
+		}
+	}
+	return network, nil
+}
+
+// subnets returns the subnets addresses are allocated from by the host-local
+// IPAM plugin configured for the plugin
+func (c *pluginConfig) subnets() []*net.IPNet {
+	if c.IPAM == nil || c.IPAM.Type != "host-local" {
+		return nil
+	}
+	var subnets []*net.IPNet
+	add := func(subnet string) {
+		if _, ipNet, err := net.ParseCIDR(subnet); err == nil {
+			subnets = append(subnets, ipNet)
+		}
+	}
+	if c.IPAM.Subnet != "" {
+		add(c.IPAM.Subnet)
+	}
+	for _, rangeSet := range c.IPAM.Ranges {
+		for _, r := range rangeSet {
+			add(r.Subnet)
+		}
+	}
+	return subnets
+}
+
+// usedResources returns the subnets and bridge interfaces used by the
+// configured networks
+func usedResources(networks []*Network) ([]*net.IPNet, map[string]bool) {
+	var subnets []*net.IPNet
+	bridges := make(map[string]bool)
+	for _, network := range networks {
+		subnets = append(subnets, network.ipNets...)
+		for _, bridge := range network.bridges {
+			bridges[bridge] = true
+		}
+	}
+	return subnets, bridges
+}
+
+// writeConfList validates and writes a generated configuration list
+func writeConfList(file string, config interface{}) error {
+	data, err := json.MarshalIndent(config, "", "   ")
+	if err != nil {
+		return errors.Wrapf(err, "error encoding network configuration")
+	}
+	if _, err := libcni.ConfListFromBytes(data); err != nil {
+		return errors.Wrapf(err, "generated invalid network configuration")
+	}
+	// Write atomically, as ocicni watches the directory for new networks
+	if err := ioutils.AtomicWriteFile(file, data, 0644); err != nil {
+		return errors.Wrapf(err, "error writing network configuration %s", file)
+	}
+	return nil
+}
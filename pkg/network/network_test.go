@@ -0,0 +1,186 @@
+package network
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseSubnet(t *testing.T, subnet string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	require.NoError(t, err)
+	return ipNet
+}
+
+func TestCreateListRemove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ipamDataDir = filepath.Join(dir, "ipam")
+
+	network, err := Create(dir, CreateOptions{
+		Name:    "net1",
+		Subnet:  parseSubnet(t, "10.100.0.0/24"),
+		IPRange: parseSubnet(t, "10.100.0.128/25"),
+		Labels:  map[string]string{"app": "web"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "net1", network.Name)
+	assert.Equal(t, filepath.Join(dir, "net1.conflist"), network.Path)
+	assert.Equal(t, []string{"bridge", "portmap"}, network.Plugins)
+	assert.Equal(t, []string{"10.100.0.0/24"}, network.Subnets)
+	assert.Equal(t, map[string]string{"app": "web"}, network.Labels)
+	require.Len(t, network.Bridges(), 1)
+
+	config := struct {
+		Plugins []struct {
+			IsGateway bool `json:"isGateway"`
+			IPAM      struct {
+				Ranges [][]ipamRange `json:"ranges"`
+				Routes []ipamRoute   `json:"routes"`
+			} `json:"ipam"`
+		} `json:"plugins"`
+	}{}
+	require.NoError(t, json.Unmarshal(network.Config, &config))
+	assert.True(t, config.Plugins[0].IsGateway)
+	assert.Equal(t, [][]ipamRange{{{
+		Subnet:     "10.100.0.0/24",
+		RangeStart: "10.100.0.128",
+		RangeEnd:   "10.100.0.254",
+		Gateway:    "10.100.0.1",
+	}}}, config.Plugins[0].IPAM.Ranges)
+	assert.Equal(t, []ipamRoute{{Dst: "0.0.0.0/0"}}, config.Plugins[0].IPAM.Routes)
+
+	internal, err := Create(dir, CreateOptions{
+		Name:     "net2",
+		Subnet:   parseSubnet(t, "10.100.1.0/24"),
+		Gateway:  net.ParseIP("10.100.1.254"),
+		Internal: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bridge"}, internal.Plugins)
+	assert.NotEqual(t, network.Bridges(), internal.Bridges())
+
+	_, err = Create(dir, CreateOptions{Name: "net1", Subnet: parseSubnet(t, "10.100.2.0/24")})
+	assert.True(t, errors.Cause(err) == ErrNetworkExists)
+
+	networks, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+	assert.Equal(t, "net1", networks[0].Name)
+	assert.Equal(t, "net2", networks[1].Name)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(ipamDataDir, "net1"), 0755))
+	require.NoError(t, Remove(dir, "net1"))
+	_, err = os.Stat(filepath.Join(ipamDataDir, "net1"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = Get(dir, "net1")
+	assert.True(t, errors.Cause(err) == ErrNetworkNotFound)
+	err = Remove(dir, "net1")
+	assert.True(t, errors.Cause(err) == ErrNetworkNotFound)
+}
+
+func TestCreateInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = Create(dir, CreateOptions{Name: "net1", Subnet: parseSubnet(t, "10.100.0.0/16")})
+	require.NoError(t, err)
+
+	for _, options := range []CreateOptions{
+		{Name: "bad/name"},
+		{Name: "net2", Driver: "overlay"},
+		{Name: "net2", Options: map[string]string{"parent": "eth0"}},
+		{Name: "net2", Options: map[string]string{"mtu": "big"}},
+		{Name: "net2", Subnet: parseSubnet(t, "10.100.5.0/24")},
+		{Name: "net2", Subnet: parseSubnet(t, "10.0.0.0/8")},
+		{Name: "net2", Subnet: parseSubnet(t, "10.101.0.0/24"), Gateway: net.ParseIP("10.102.0.1")},
+		{Name: "net2", Subnet: parseSubnet(t, "10.101.0.0/24"), Gateway: net.ParseIP("10.101.0.0")},
+		{Name: "net2", Subnet: parseSubnet(t, "10.101.0.0/24"), IPRange: parseSubnet(t, "10.101.0.0/16")},
+		{Name: "net2", Gateway: net.ParseIP("10.101.0.1")},
+		{Name: "net2", Driver: MacVLANDriver, Options: map[string]string{"parent": "lo"}},
+		{Name: "net2", Driver: MacVLANDriver, Subnet: parseSubnet(t, "10.101.0.0/24")},
+	} {
+		_, err := Create(dir, options)
+		assert.Error(t, err, "options %+v", options)
+	}
+
+	networks, err := List(dir)
+	require.NoError(t, err)
+	assert.Len(t, networks, 1)
+}
+
+func TestListSkipsInvalidConfigs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"10-single.conf":     `{"cniVersion": "0.3.0", "name": "single", "type": "bridge", "bridge": "br0", "ipam": {"type": "host-local", "subnet": "10.90.0.0/16"}}`,
+		"20-broken.conflist": `{"name": "broken"`,
+		"30-empty.conflist":  `{"cniVersion": "0.3.0", "name": "empty", "plugins": []}`,
+		"40-notes.txt":       `not a network`,
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	networks, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, networks, 1)
+	assert.Equal(t, "single", networks[0].Name)
+	assert.Equal(t, "bridge", networks[0].Driver())
+	assert.Equal(t, []string{"10.90.0.0/16"}, networks[0].Subnets)
+	assert.Equal(t, []string{"br0"}, networks[0].Bridges())
+	assert.Empty(t, networks[0].Labels)
+
+	networks, err = List(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, networks)
+}
+
+func TestFreeSubnet(t *testing.T) {
+	subnet, err := freeSubnet(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.89.0.0/24", subnet.String())
+
+	subnet, err = freeSubnet([]*net.IPNet{
+		parseSubnet(t, "10.89.0.0/23"),
+		parseSubnet(t, "10.89.2.128/25"),
+		parseSubnet(t, "192.168.0.0/16"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "10.89.3.0/24", subnet.String())
+
+	_, err = freeSubnet([]*net.IPNet{parseSubnet(t, "10.0.0.0/8")})
+	assert.Error(t, err)
+}
+
+func TestRemoveInvalidName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cni-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ipamDataDir = filepath.Join(dir, "ipam")
+	require.NoError(t, os.MkdirAll(ipamDataDir, 0755))
+
+	for _, name := range []string{"..", "../x", ".hidden"} {
+		config := `{"cniVersion": "0.3.0", "name": "` + name + `", "plugins": [{"type": "bridge"}]}`
+		path := filepath.Join(dir, "10-bad.conflist")
+		require.NoError(t, ioutil.WriteFile(path, []byte(config), 0644))
+
+		assert.Error(t, Remove(dir, name), "name %q", name)
+		_, err := os.Stat(path)
+		assert.NoError(t, err, "name %q", name)
+		_, err = os.Stat(ipamDataDir)
+		assert.NoError(t, err, "name %q", name)
+	}
+}
@@ -0,0 +1,100 @@
+package network
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// ParseSubnet parses a subnet in CIDR notation. Unlike net.ParseCIDR, it
+// refuses addresses with host bits set, which are likely typos.
+func ParseSubnet(subnet string) (*net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid subnet %q", subnet)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return nil, errors.Errorf("invalid subnet %q, it should be %s", subnet, ipNet)
+	}
+	return ipNet, nil
+}
+
+// hostSubnets returns the subnets of the addresses of the host interfaces
+func hostSubnets() ([]*net.IPNet, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading addresses of host interfaces")
+	}
+	var subnets []*net.IPNet
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok {
+			subnets = append(subnets, &net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Mask: ipNet.Mask})
+		}
+	}
+	return subnets, nil
+}
+
+// freeSubnet returns the first subnet of the default pool that overlaps with
+// none of the used subnets
+func freeSubnet(used []*net.IPNet) (*net.IPNet, error) {
+	poolOnes, bits := defaultSubnetPool.Mask.Size()
+	count := 1 << uint(defaultSubnetSize-poolOnes)
+	mask := net.CIDRMask(defaultSubnetSize, bits)
+
+	candidate := &net.IPNet{IP: defaultSubnetPool.IP, Mask: mask}
+	for i := 0; i < count; i++ {
+		free := true
+		for _, subnet := range used {
+			if overlaps(candidate, subnet) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return candidate, nil
+		}
+		candidate = &net.IPNet{IP: nextIP(lastIP(candidate)), Mask: mask}
+	}
+	return nil, errors.Errorf("no free subnet in %s, please specify a subnet", defaultSubnetPool)
+}
+
+// overlaps returns whether two subnets have addresses in common
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// nextIP returns the address following ip
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// prevIP returns the address preceding ip
+func prevIP(ip net.IP) net.IP {
+	prev := make(net.IP, len(ip))
+	copy(prev, ip)
+	for i := len(prev) - 1; i >= 0; i-- {
+		prev[i]--
+		if prev[i] != 0xff {
+			break
+		}
+	}
+	return prev
+}
+
+// lastIP returns the last address of a subnet
+func lastIP(subnet *net.IPNet) net.IP {
+	ip := subnet.IP.Mask(subnet.Mask)
+	last := make(net.IP, len(ip))
+	for i := range ip {
+		last[i] = ip[i] | ^subnet.Mask[i]
+	}
+	return last
+}
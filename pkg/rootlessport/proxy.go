@@ -0,0 +1,58 @@
+package rootlessport
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// proxySignature starts every version 2 PROXY protocol header
+var proxySignature = []byte{0x0d, 0x0a, 0x0d, 0x0a, 0x00, 0x0d, 0x0a, 0x51, 0x55, 0x49, 0x54, 0x0a}
+
+const (
+	// proxyVersionCommand is version 2 with the PROXY command
+	proxyVersionCommand = 0x21
+	proxyFamilyInet     = 0x10
+	proxyFamilyInet6    = 0x20
+	proxyStream         = 0x01
+	proxyDatagram       = 0x02
+)
+
+// proxyHeader returns the version 2 PROXY protocol header describing a
+// connection from src to dst. udp selects the datagram transport. Addresses
+// of both families are described as IPv6 addresses.
+func proxyHeader(src, dst net.IP, srcPort, dstPort int, udp bool) []byte {
+	transport := byte(proxyStream)
+	if udp {
+		transport = proxyDatagram
+	}
+
+	var family byte
+	var addrs []byte
+	src4, dst4 := src.To4(), dst.To4()
+	if src4 != nil && dst4 != nil {
+		family = proxyFamilyInet
+		addrs = append(append(addrs, src4...), dst4...)
+	} else {
+		family = proxyFamilyInet6
+		addrs = append(append(addrs, to16(src)...), to16(dst)...)
+	}
+	ports := make([]byte, 4)
+	binary.BigEndian.PutUint16(ports[0:2], uint16(srcPort))
+	binary.BigEndian.PutUint16(ports[2:4], uint16(dstPort))
+	addrs = append(addrs, ports...)
+
+	header := make([]byte, 0, len(proxySignature)+4+len(addrs))
+	header = append(header, proxySignature...)
+	header = append(header, proxyVersionCommand, family|transport, 0, 0)
+	binary.BigEndian.PutUint16(header[len(header)-2:], uint16(len(addrs)))
+	return append(header, addrs...)
+}
+
+// to16 returns the 16 byte form of ip, the unspecified address if ip is not
+// set
+func to16(ip net.IP) net.IP {
+	if ip16 := ip.To16(); ip16 != nil {
+		return ip16
+	}
+	return net.IPv6zero
+}
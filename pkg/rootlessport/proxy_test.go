@@ -0,0 +1,47 @@
+package rootlessport
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyHeaderTCP4(t *testing.T) {
+	header := proxyHeader(net.ParseIP("192.0.2.10"), net.ParseIP("198.51.100.1"), 40000, 8080, false)
+	expected := append(append([]byte{}, proxySignature...),
+		0x21, 0x11, 0x00, 0x0c,
+		192, 0, 2, 10,
+		198, 51, 100, 1,
+		0x9c, 0x40,
+		0x1f, 0x90,
+	)
+	assert.Equal(t, expected, header)
+}
+
+func TestProxyHeaderUDP6(t *testing.T) {
+	src := net.ParseIP("2001:db8::10")
+	dst := net.ParseIP("2001:db8::1")
+	header := proxyHeader(src, dst, 5353, 53, true)
+	assert.Equal(t, proxySignature, header[:12])
+	assert.Equal(t, []byte{0x21, 0x22, 0x00, 0x24}, header[12:16])
+	assert.Equal(t, []byte(src.To16()), header[16:32])
+	assert.Equal(t, []byte(dst.To16()), header[32:48])
+	assert.Equal(t, []byte{0x14, 0xe9, 0x00, 0x35}, header[48:])
+}
+
+func TestProxyHeaderMixedFamilies(t *testing.T) {
+	// A peer connecting over IPv4 to a host address reported as IPv6 is
+	// described with IPv6 addresses
+	header := proxyHeader(net.ParseIP("192.0.2.10"), net.ParseIP("::1"), 40000, 8080, false)
+	assert.Equal(t, byte(0x21), header[13])
+	assert.Len(t, header, 16+36)
+	assert.Equal(t, []byte(net.ParseIP("192.0.2.10").To16()), header[16:32])
+}
+
+func TestValidPeerAddress(t *testing.T) {
+	for _, mode := range []string{"", PeerAddressNone, PeerAddressProxyProtocol, PeerAddressSocket} {
+		assert.True(t, ValidPeerAddress(mode), mode)
+	}
+	assert.False(t, ValidPeerAddress("transparent"))
+}
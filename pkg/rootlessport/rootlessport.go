@@ -0,0 +1,56 @@
+// Package rootlessport forwards the published ports of rootless containers.
+//
+// slirp4netns forwards every published port with a separate call to its API,
+// and containers see its gateway as the address of all clients. rootlessport
+// is a helper process, started by re-executing podman, that listens on the
+// host ports and splices the connections it accepts into the network
+// namespace of the container. TCP connections are forwarded as they are
+// accepted; UDP datagrams are forwarded through a session per peer.
+//
+// The address of the peers of forwarded connections can be exposed to the
+// container with a PROXY protocol header, or by making the connections in
+// the namespace of the container originate from the address of the peer.
+package rootlessport
+
+import (
+	"github.com/cri-o/ocicni/pkg/ocicni"
+)
+
+const (
+	// PeerAddressNone does not expose the address of the peers to the
+	// container, connections originate from the helper
+	PeerAddressNone = "none"
+	// PeerAddressProxyProtocol sends a version 2 PROXY protocol header
+	// holding the address of the peer at the start of every TCP
+	// connection, and in front of every UDP datagram
+	PeerAddressProxyProtocol = "proxy-protocol"
+	// PeerAddressSocket makes the connections in the network namespace
+	// of the container originate from the IPv4 address and port of the
+	// peer. The address of the peer is routed locally in the namespace
+	// while it has connections.
+	PeerAddressSocket = "socket"
+)
+
+// Config is the configuration of the helper process
+type Config struct {
+	// Mappings are the ports to forward
+	Mappings []ocicni.PortMapping `json:"mappings"`
+	// NetNSPath is the path of the network namespace of the container
+	NetNSPath string `json:"netNSPath"`
+	// ChildIP is the address of the container in its network namespace
+	// the ports are forwarded to
+	ChildIP string `json:"childIP"`
+	// PeerAddress is how the address of the peers of forwarded
+	// connections is exposed to the container
+	PeerAddress string `json:"peerAddress,omitempty"`
+}
+
+// ValidPeerAddress returns whether mode is a valid way to expose the address
+// of peers. The empty string is equivalent to PeerAddressNone.
+func ValidPeerAddress(mode string) bool {
+	switch mode {
+	case "", PeerAddressNone, PeerAddressProxyProtocol, PeerAddressSocket:
+		return true
+	}
+	return false
+}
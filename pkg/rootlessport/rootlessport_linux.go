@@ -0,0 +1,450 @@
+// +build linux
+
+package rootlessport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containers/storage/pkg/reexec"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// reexecKey is the name the helper process is started with
+	reexecKey = "podman-rootlessport"
+	// The helper exits when the pipe on exitFd is closed, and reports on
+	// readyFd that it listens on all ports, or the error that stopped it
+	exitFd       = 3
+	readyFd      = 4
+	readyMessage = "ready"
+	// udpSessionTimeout is the time after which the session of a UDP peer
+	// that sent nor received datagrams is closed
+	udpSessionTimeout = 60 * time.Second
+	// maxDatagramSize is the size of the largest UDP datagram
+	maxDatagramSize = 65535
+)
+
+func init() {
+	reexec.Register(reexecKey, helperMain)
+}
+
+// Start starts the helper process forwarding the ports of config, and waits
+// until it listens on all of them. The helper exits when the write end of
+// exitPipe is closed.
+func Start(config *Config, exitPipe *os.File) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal rootlessport configuration")
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return errors.Wrapf(err, "failed to open pipe")
+	}
+	defer readyR.Close()
+
+	cmd := reexec.Command(reexecKey)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.ExtraFiles = []*os.File{exitPipe, readyW}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+	err = cmd.Start()
+	readyW.Close()
+	if err != nil {
+		return errors.Wrapf(err, "failed to start rootlessport process")
+	}
+
+	// The helper closes the pipe once it is ready, or when it exits
+	msg, err := ioutil.ReadAll(readyR)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read from rootlessport ready pipe")
+	}
+	if string(msg) != readyMessage {
+		if err := cmd.Wait(); err != nil {
+			logrus.Debugf("rootlessport exited: %v", err)
+		}
+		if len(msg) == 0 {
+			return errors.New("rootlessport exited before forwarding ports")
+		}
+		return errors.Errorf("rootlessport failed: %s", msg)
+	}
+	return cmd.Process.Release()
+}
+
+func helperMain() {
+	ready := os.NewFile(readyFd, "ready")
+	if err := runHelper(ready); err != nil {
+		// Errors are reported to the parent until the helper is ready
+		ready.Write([]byte(err.Error()))
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func runHelper(ready *os.File) error {
+	var config Config
+	if err := json.NewDecoder(os.Stdin).Decode(&config); err != nil {
+		return errors.Wrapf(err, "error decoding rootlessport configuration")
+	}
+	if !ValidPeerAddress(config.PeerAddress) {
+		return errors.Errorf("invalid peer address mode %q", config.PeerAddress)
+	}
+	childIP := net.ParseIP(config.ChildIP)
+	if childIP == nil {
+		return errors.Errorf("invalid container address %q", config.ChildIP)
+	}
+
+	// Keep the namespace open, so it is not reused while ports are
+	// forwarded to it
+	netNS, err := ns.GetNS(config.NetNSPath)
+	if err != nil {
+		return errors.Wrapf(err, "error opening network namespace %s", config.NetNSPath)
+	}
+	defer netNS.Close()
+
+	f := &forwarder{
+		netNS:       netNS,
+		childIP:     childIP,
+		peerAddress: config.PeerAddress,
+		routes:      make(map[string]int),
+	}
+	if f.peerAddress == PeerAddressSocket {
+		if err := netNS.Do(func(ns.NetNS) error {
+			lo, err := netlink.LinkByName("lo")
+			if err != nil {
+				return err
+			}
+			f.loIndex = lo.Attrs().Index
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "error retrieving loopback interface of %s", config.NetNSPath)
+		}
+	}
+	defer f.close()
+	if err := f.listen(config.Mappings); err != nil {
+		return err
+	}
+
+	if _, err := ready.Write([]byte(readyMessage)); err != nil {
+		return err
+	}
+	ready.Close()
+	f.serve()
+
+	// Forward ports until the container exits and the exit pipe is
+	// closed
+	exit := os.NewFile(exitFd, "exit")
+	_, err = io.Copy(ioutil.Discard, exit)
+	return err
+}
+
+// forwarder forwards connections and datagrams from the host to the network
+// namespace of a container
+type forwarder struct {
+	netNS       ns.NetNS
+	childIP     net.IP
+	peerAddress string
+	loIndex     int
+
+	tcpListeners []tcpListener
+	udpListeners []udpListener
+
+	// routes counts the connections of the peers routed in the namespace
+	routes     map[string]int
+	routesLock sync.Mutex
+}
+
+type tcpListener struct {
+	net.Listener
+	childPort int
+}
+
+type udpListener struct {
+	net.PacketConn
+	childPort int
+}
+
+// listen opens the host ports of mappings
+func (f *forwarder) listen(mappings []ocicni.PortMapping) error {
+	for _, m := range mappings {
+		hostAddr := net.JoinHostPort(m.HostIP, strconv.Itoa(int(m.HostPort)))
+		switch strings.ToLower(m.Protocol) {
+		case "", "tcp":
+			l, err := net.Listen("tcp", hostAddr)
+			if err != nil {
+				return errors.Wrapf(err, "cannot listen on %s/tcp", hostAddr)
+			}
+			f.tcpListeners = append(f.tcpListeners, tcpListener{l, int(m.ContainerPort)})
+		case "udp":
+			c, err := net.ListenPacket("udp", hostAddr)
+			if err != nil {
+				return errors.Wrapf(err, "cannot listen on %s/udp", hostAddr)
+			}
+			f.udpListeners = append(f.udpListeners, udpListener{c, int(m.ContainerPort)})
+		default:
+			return errors.Errorf("cannot forward %s/%s, protocol %s is not supported", hostAddr, m.Protocol, m.Protocol)
+		}
+	}
+	return nil
+}
+
+// serve forwards the connections and datagrams received on all host ports
+func (f *forwarder) serve() {
+	for _, l := range f.tcpListeners {
+		go f.serveTCP(l)
+	}
+	for _, l := range f.udpListeners {
+		go f.serveUDP(l)
+	}
+}
+
+// close stops listening on the host ports
+func (f *forwarder) close() {
+	for _, l := range f.tcpListeners {
+		l.Close()
+	}
+	for _, l := range f.udpListeners {
+		l.Close()
+	}
+}
+
+// dial connects to port in the network namespace of the container. In socket
+// mode, the connection originates from the address of the peer if it is an
+// IPv4 address. The returned function must be called once the connection is
+// closed.
+func (f *forwarder) dial(network string, peer net.IP, peerPort, port int) (net.Conn, func(), error) {
+	var (
+		conn    net.Conn
+		release = func() {}
+	)
+	target := net.JoinHostPort(f.childIP.String(), strconv.Itoa(port))
+	err := f.netNS.Do(func(ns.NetNS) error {
+		var (
+			dialer net.Dialer
+			err    error
+		)
+		if f.peerAddress != PeerAddressSocket || peer.To4() == nil {
+			conn, err = dialer.Dial(network, target)
+			return err
+		}
+
+		if release, err = f.routePeer(peer); err != nil {
+			return err
+		}
+		dialer.LocalAddr = localAddr(network, peer, peerPort)
+		if conn, err = dialer.Dial(network, target); err != nil {
+			// The port of the peer may be in use in the namespace
+			dialer.LocalAddr = localAddr(network, peer, 0)
+			conn, err = dialer.Dial(network, target)
+		}
+		if err != nil {
+			release()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot connect to %s/%s in %s", target, network, f.netNS.Path())
+	}
+	return conn, release, nil
+}
+
+// routePeer routes the IPv4 address of a peer locally in the network
+// namespace of the container, so connections can originate from it. It must
+// be called in the namespace. The returned function removes the route once
+// the last connection of the peer is closed.
+func (f *forwarder) routePeer(peer net.IP) (func(), error) {
+	if peer.IsLoopback() {
+		return func() {}, nil
+	}
+
+	key := peer.String()
+	f.routesLock.Lock()
+	defer f.routesLock.Unlock()
+	if f.routes[key] == 0 {
+		if err := netlink.RouteAdd(f.peerRoute(peer)); err != nil && !os.IsExist(err) {
+			return nil, errors.Wrapf(err, "error routing peer %s", peer)
+		}
+	}
+	f.routes[key]++
+
+	return func() {
+		f.routesLock.Lock()
+		defer f.routesLock.Unlock()
+		f.routes[key]--
+		if f.routes[key] > 0 {
+			return
+		}
+		delete(f.routes, key)
+		if err := f.netNS.Do(func(ns.NetNS) error {
+			return netlink.RouteDel(f.peerRoute(peer))
+		}); err != nil {
+			logrus.Debugf("Error removing route of peer %s: %v", peer, err)
+		}
+	}, nil
+}
+
+// peerRoute returns the local route of the address of a peer
+func (f *forwarder) peerRoute(peer net.IP) *netlink.Route {
+	return &netlink.Route{
+		LinkIndex: f.loIndex,
+		Dst:       &net.IPNet{IP: peer.To4(), Mask: net.CIDRMask(32, 32)},
+		Scope:     netlink.SCOPE_HOST,
+		Type:      unix.RTN_LOCAL,
+		Table:     unix.RT_TABLE_LOCAL,
+	}
+}
+
+func localAddr(network string, ip net.IP, port int) net.Addr {
+	if network == "udp" {
+		return &net.UDPAddr{IP: ip, Port: port}
+	}
+	return &net.TCPAddr{IP: ip, Port: port}
+}
+
+// isTemporary returns whether err is a temporary error of a listener
+func isTemporary(err error) bool {
+	ne, ok := err.(net.Error)
+	return ok && ne.Temporary()
+}
+
+// serveTCP forwards the connections accepted on a host port
+func (f *forwarder) serveTCP(l tcpListener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if isTemporary(err) {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return
+		}
+		go f.forwardTCP(conn.(*net.TCPConn), l.childPort)
+	}
+}
+
+// forwardTCP splices a connection accepted on a host port with a connection
+// to the container
+func (f *forwarder) forwardTCP(conn *net.TCPConn, port int) {
+	defer conn.Close()
+
+	peer := conn.RemoteAddr().(*net.TCPAddr)
+	child, release, err := f.dial("tcp", peer.IP, peer.Port, port)
+	if err != nil {
+		logrus.Debugf("Error forwarding connection of %s: %v", peer, err)
+		return
+	}
+	defer release()
+	defer child.Close()
+
+	if f.peerAddress == PeerAddressProxyProtocol {
+		local := conn.LocalAddr().(*net.TCPAddr)
+		if _, err := child.Write(proxyHeader(peer.IP, local.IP, peer.Port, local.Port, false)); err != nil {
+			logrus.Debugf("Error sending PROXY header of %s: %v", peer, err)
+			return
+		}
+	}
+
+	childTCP := child.(*net.TCPConn)
+	done := make(chan struct{})
+	go func() {
+		splice(childTCP, conn)
+		close(done)
+	}()
+	splice(conn, childTCP)
+	<-done
+}
+
+// splice copies src to dst until src is closed, then closes the write side of
+// dst. Copies between TCP connections use splice(2).
+func splice(dst, src *net.TCPConn) {
+	if _, err := io.Copy(dst, src); err != nil {
+		logrus.Debugf("Error forwarding %s to %s: %v", src.RemoteAddr(), dst.RemoteAddr(), err)
+	}
+	dst.CloseWrite()
+}
+
+// serveUDP forwards the datagrams received on a host port through a session
+// per peer
+func (f *forwarder) serveUDP(l udpListener) {
+	var (
+		sessions     = make(map[string]net.Conn)
+		sessionsLock sync.Mutex
+		buf          = make([]byte, maxDatagramSize)
+	)
+	for {
+		n, addr, err := l.ReadFrom(buf)
+		if err != nil {
+			if isTemporary(err) {
+				continue
+			}
+			return
+		}
+		peer := addr.(*net.UDPAddr)
+		key := peer.String()
+
+		sessionsLock.Lock()
+		child, ok := sessions[key]
+		if !ok {
+			var release func()
+			child, release, err = f.dial("udp", peer.IP, peer.Port, l.childPort)
+			if err != nil {
+				sessionsLock.Unlock()
+				logrus.Debugf("Error forwarding datagram of %s: %v", peer, err)
+				continue
+			}
+			sessions[key] = child
+			go func() {
+				f.replyUDP(l, peer, child)
+				sessionsLock.Lock()
+				delete(sessions, key)
+				sessionsLock.Unlock()
+				child.Close()
+				release()
+			}()
+		}
+		sessionsLock.Unlock()
+
+		data := buf[:n]
+		if f.peerAddress == PeerAddressProxyProtocol {
+			local := l.LocalAddr().(*net.UDPAddr)
+			data = append(proxyHeader(peer.IP, local.IP, peer.Port, local.Port, true), data...)
+		}
+		// Datagrams of the peer keep its session open
+		child.SetReadDeadline(time.Now().Add(udpSessionTimeout))
+		if _, err := child.Write(data); err != nil {
+			logrus.Debugf("Error forwarding datagram of %s: %v", peer, err)
+		}
+	}
+}
+
+// replyUDP forwards the datagrams the container sends on the session of a peer
+// back to the peer, until the session is idle for udpSessionTimeout
+func (f *forwarder) replyUDP(l udpListener, peer net.Addr, child net.Conn) {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		child.SetReadDeadline(time.Now().Add(udpSessionTimeout))
+		n, err := child.Read(buf)
+		if err != nil {
+			return
+		}
+		if _, err := l.WriteTo(buf[:n], peer); err != nil {
+			logrus.Debugf("Error forwarding datagram to %s: %v", peer, err)
+		}
+	}
+}
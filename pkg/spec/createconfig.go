@@ -9,6 +9,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/containers/image/manifest"
 	"github.com/containers/libpod/libpod"
 	"github.com/containers/libpod/pkg/namespaces"
 	"github.com/containers/libpod/pkg/rootless"
@@ -85,6 +86,7 @@ type CreateConfig struct {
 	Env                map[string]string //env
 	ExposedPorts       map[nat.Port]struct{}
 	GroupAdd           []string // group-add
+	HealthCheck        *manifest.Schema2HealthConfig
 	HostAdd            []string //add-host
 	Hostname           string   //hostname
 	Image              string
@@ -94,8 +96,8 @@ type CreateConfig struct {
 	ImageVolumeType    string                 // how to handle the image volume, either bind, tmpfs, or ignore
 	Interactive        bool                   //interactive
 	IpcMode            namespaces.IpcMode     //ipc
-	IP6Address         string                 //ipv6
-	IPAddress          string                 //ip
+	IP6Address         []string               //ip6
+	IPAddress          []string               //ip
 	Labels             map[string]string      //label
 	LinkLocalIP        []string               // link-local-ip
 	LogDriver          string                 // log-driver
@@ -108,12 +110,15 @@ type CreateConfig struct {
 	PidMode            namespaces.PidMode     //pid
 	Pod                string                 //pod
 	PortBindings       nat.PortMap
+	PortForwarder      string   //port-forwarder
+	PortPeerAddress    string   //port-peer-address
 	Privileged         bool     //privileged
 	Publish            []string //publish
 	PublishAll         bool     //publish-all
 	Quiet              bool     //quiet
 	ReadOnlyRootfs     bool     //read-only
 	Resources          CreateResourceConfig
+	RestartPolicy      string            //restart
 	Rm                 bool              //rm
 	StopSignal         syscall.Signal    // stop-signal
 	StopTimeout        uint              // stop-timeout
@@ -334,6 +339,9 @@ func (c *CreateConfig) createExitCommand() []string {
 	if config.StorageConfig.GraphDriverName != "" {
 		command = append(command, []string{"--storage-driver", config.StorageConfig.GraphDriverName}...)
 	}
+	if config.VolumePluginDir != "" {
+		command = append(command, []string{"--volume-plugin-dir", config.VolumePluginDir}...)
+	}
 	if c.Syslog {
 		command = append(command, "--syslog")
 	}
@@ -471,18 +479,41 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime, pod *l
 	if len(c.HostAdd) > 0 {
 		options = append(options, libpod.WithHosts(c.HostAdd))
 	}
+	if c.LogDriver != "" {
+		options = append(options, libpod.WithLogDriver(c.LogDriver))
+	}
 	logPath := getLoggingPath(c.LogDriverOpt)
 	if logPath != "" {
 		options = append(options, libpod.WithLogPath(logPath))
 	}
-	if c.IPAddress != "" {
-		ip := net.ParseIP(c.IPAddress)
-		if ip == nil {
-			return nil, errors.Wrapf(libpod.ErrInvalidArg, "cannot parse %s as IP address", c.IPAddress)
-		} else if ip.To4() == nil {
-			return nil, errors.Wrapf(libpod.ErrInvalidArg, "%s is not an IPv4 address", c.IPAddress)
+	// Static IPs are requested by network, in the order of the networks
+	// they are given for
+	staticIPs := make(map[string][]net.IP)
+	var staticNetworks []string
+	for i, addresses := range [][]string{c.IPAddress, c.IP6Address} {
+		for _, address := range addresses {
+			network, ip, err := parseStaticIP(address, i == 1)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := staticIPs[network]; !ok {
+				staticNetworks = append(staticNetworks, network)
+			}
+			staticIPs[network] = append(staticIPs[network], ip)
 		}
-		options = append(options, libpod.WithStaticIP(ip))
+	}
+	for _, network := range staticNetworks {
+		options = append(options, libpod.WithStaticIPs(network, staticIPs[network]))
+	}
+	if c.MacAddress != "" {
+		mac, err := net.ParseMAC(c.MacAddress)
+		if err != nil {
+			return nil, errors.Wrapf(libpod.ErrInvalidArg, "cannot parse %s as MAC address", c.MacAddress)
+		}
+		options = append(options, libpod.WithStaticMAC(mac))
+	}
+	if c.PortForwarder != "" || c.PortPeerAddress != "" {
+		options = append(options, libpod.WithPortForwarder(c.PortForwarder, c.PortPeerAddress))
 	}
 
 	options = append(options, libpod.WithPrivileged(c.Privileged))
@@ -515,9 +546,34 @@ func (c *CreateConfig) GetContainerCreateOptions(runtime *libpod.Runtime, pod *l
 	if c.CgroupParent != "" {
 		options = append(options, libpod.WithCgroupParent(c.CgroupParent))
 	}
+
+	if c.RestartPolicy != "" {
+		split := strings.Split(c.RestartPolicy, ":")
+		if len(split) > 1 {
+			if split[0] != libpod.RestartPolicyOnFailure {
+				return nil, errors.Wrapf(libpod.ErrInvalidArg, "restart policy %q does not accept a maximum retry count", split[0])
+			}
+			numTries, err := strconv.Atoi(split[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s is not a valid number of retries for restart policy", split[1])
+			}
+			if numTries < 0 {
+				return nil, errors.Wrapf(libpod.ErrInvalidArg, "restart policy requires a positive number of retries")
+			}
+			options = append(options, libpod.WithRestartRetries(uint(numTries)))
+		}
+		options = append(options, libpod.WithRestartPolicy(split[0]))
+	}
+
+	if c.HealthCheck != nil {
+		options = append(options, libpod.WithHealthCheck(c.HealthCheck))
+	}
+
 	// For a rootless container always cleanup the storage/network as they
 	// run in a different namespace thus not reusable when we restart.
-	if c.Detach || rootless.IsRootless() {
+	// Containers with a restart policy rely on the exit command to be
+	// restarted when they exit.
+	if c.Detach || rootless.IsRootless() || c.RestartPolicy != "" {
 		options = append(options, libpod.WithExitCommand(c.createExitCommand()))
 	}
 
@@ -556,6 +612,28 @@ func NatToOCIPortBindings(ports nat.PortMap) ([]ocicni.PortMapping, error) {
 	return portBindings, nil
 }
 
+// parseStaticIP parses a static IP given as [network:]address. The network is
+// empty if the address is requested from the first network of the container.
+func parseStaticIP(value string, ipv6 bool) (string, net.IP, error) {
+	network, address := "", value
+	// IPv6 addresses hold colons too
+	if net.ParseIP(value) == nil {
+		if idx := strings.Index(value, ":"); idx != -1 {
+			network, address = value[:idx], value[idx+1:]
+		}
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", nil, errors.Wrapf(libpod.ErrInvalidArg, "cannot parse %s as IP address", address)
+	}
+	if ipv6 && ip.To4() != nil {
+		return "", nil, errors.Wrapf(libpod.ErrInvalidArg, "%s is not an IPv6 address", address)
+	} else if !ipv6 && ip.To4() == nil {
+		return "", nil, errors.Wrapf(libpod.ErrInvalidArg, "%s is not an IPv4 address", address)
+	}
+	return network, ip, nil
+}
+
 // AddPrivilegedDevices iterates through host devices and adds all
 // host devices to the spec
 func (c *CreateConfig) AddPrivilegedDevices(g *generate.Generator) error {
@@ -37,3 +37,32 @@ func TestCreateConfig_GetTmpfsMounts(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(data, tmpfsMount[0]))
 
 }
+
+func TestParseStaticIP(t *testing.T) {
+	network, ip, err := parseStaticIP("10.88.64.128", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "", network)
+	assert.Equal(t, "10.88.64.128", ip.String())
+
+	network, ip, err = parseStaticIP("net1:10.89.0.5", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "net1", network)
+	assert.Equal(t, "10.89.0.5", ip.String())
+
+	network, ip, err = parseStaticIP("fd00::5", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "", network)
+	assert.Equal(t, "fd00::5", ip.String())
+
+	network, ip, err = parseStaticIP("net1:fd00::5", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "net1", network)
+	assert.Equal(t, "fd00::5", ip.String())
+
+	_, _, err = parseStaticIP("fd00::5", false)
+	assert.Error(t, err)
+	_, _, err = parseStaticIP("net1:10.89.0.5", true)
+	assert.Error(t, err)
+	_, _, err = parseStaticIP("net1:114232346", false)
+	assert.Error(t, err)
+}
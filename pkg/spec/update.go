@@ -0,0 +1,70 @@
+package createconfig
+
+import (
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// UpdateResourceConfig holds the resource limits of an existing container
+// that can be changed. Limits left at zero are not changed.
+type UpdateResourceConfig struct {
+	BlkioWeight uint16  // blkio-weight
+	CPUs        float64 // cpus
+	CPUShares   uint64  // cpu-shares
+	Memory      int64   // memory
+	MemorySwap  int64   // memory-swap
+	PidsLimit   int64   // pids-limit
+}
+
+// ToOCIResources validates the limits and converts them to the resources of
+// an OCI spec. Only the limits that are set are included.
+func (u *UpdateResourceConfig) ToOCIResources() (*spec.LinuxResources, error) {
+	if u.BlkioWeight != 0 && (u.BlkioWeight < 10 || u.BlkioWeight > 1000) {
+		return nil, errors.Errorf("invalid blkio-weight %d: must be between 10 and 1000", u.BlkioWeight)
+	}
+	if u.CPUs < 0 {
+		return nil, errors.Errorf("invalid cpus %.3f: must not be negative", u.CPUs)
+	}
+	if u.Memory < 0 {
+		return nil, errors.Errorf("invalid memory %d: must not be negative", u.Memory)
+	}
+	if u.Memory > 0 && u.MemorySwap > 0 && u.MemorySwap < u.Memory {
+		return nil, errors.Errorf("memory-swap must be greater than or equal to memory")
+	}
+
+	resources := &spec.LinuxResources{}
+	if u.BlkioWeight != 0 {
+		weight := u.BlkioWeight
+		resources.BlockIO = &spec.LinuxBlockIO{Weight: &weight}
+	}
+	if u.CPUs != 0 || u.CPUShares != 0 {
+		resources.CPU = &spec.LinuxCPU{}
+		if u.CPUs != 0 {
+			period := uint64(cpuPeriod)
+			quota := int64(u.CPUs * cpuPeriod)
+			resources.CPU.Period = &period
+			resources.CPU.Quota = &quota
+		}
+		if u.CPUShares != 0 {
+			shares := u.CPUShares
+			resources.CPU.Shares = &shares
+		}
+	}
+	if u.Memory != 0 || u.MemorySwap != 0 {
+		resources.Memory = &spec.LinuxMemory{}
+		if u.Memory != 0 {
+			limit := u.Memory
+			resources.Memory.Limit = &limit
+		}
+		// The swap limit is defaulted by the container, which knows
+		// whether its current swap limit was set explicitly
+		if u.MemorySwap != 0 {
+			swap := u.MemorySwap
+			resources.Memory.Swap = &swap
+		}
+	}
+	if u.PidsLimit != 0 {
+		resources.Pids = &spec.LinuxPids{Limit: u.PidsLimit}
+	}
+	return resources, nil
+}
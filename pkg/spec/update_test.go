@@ -0,0 +1,55 @@
+package createconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateResourceConfig_ToOCIResources(t *testing.T) {
+	update := UpdateResourceConfig{
+		CPUs:      1.5,
+		Memory:    512 * 1024 * 1024,
+		PidsLimit: 100,
+	}
+	resources, err := update.ToOCIResources()
+	require.NoError(t, err)
+
+	require.NotNil(t, resources.CPU)
+	assert.Equal(t, uint64(100000), *resources.CPU.Period)
+	assert.Equal(t, int64(150000), *resources.CPU.Quota)
+	assert.Nil(t, resources.CPU.Shares)
+
+	require.NotNil(t, resources.Memory)
+	assert.Equal(t, int64(512*1024*1024), *resources.Memory.Limit)
+	assert.Nil(t, resources.Memory.Swap)
+
+	require.NotNil(t, resources.Pids)
+	assert.Equal(t, int64(100), resources.Pids.Limit)
+
+	assert.Nil(t, resources.BlockIO)
+}
+
+func TestUpdateResourceConfig_ToOCIResourcesSwapOnly(t *testing.T) {
+	update := UpdateResourceConfig{MemorySwap: -1}
+	resources, err := update.ToOCIResources()
+	require.NoError(t, err)
+
+	require.NotNil(t, resources.Memory)
+	assert.Nil(t, resources.Memory.Limit)
+	assert.Equal(t, int64(-1), *resources.Memory.Swap)
+	assert.Nil(t, resources.CPU)
+}
+
+func TestUpdateResourceConfig_ToOCIResourcesInvalid(t *testing.T) {
+	for _, update := range []UpdateResourceConfig{
+		{BlkioWeight: 5},
+		{BlkioWeight: 2000},
+		{CPUs: -1},
+		{Memory: 1024 * 1024, MemorySwap: 1024},
+	} {
+		_, err := update.ToOCIResources()
+		assert.Error(t, err)
+	}
+}
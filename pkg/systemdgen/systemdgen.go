@@ -0,0 +1,119 @@
+package systemdgen
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// RestartPolicies are the restart policies supported by systemd services.
+var RestartPolicies = []string{"no", "on-success", "on-failure", "on-abnormal", "on-watchdog", "on-abort", "always"}
+
+// ServiceInfo holds the information needed to generate a systemd unit file
+// that manages a single container.
+type ServiceInfo struct {
+	// ServiceName is the name of the unit without the .service suffix.
+	ServiceName string
+	// Container is the name or ID of the container the unit manages.
+	Container string
+	// Executable is the path to the podman binary called by the unit.
+	Executable string
+	// PIDFile is the path to the PID file of conmon for the container.
+	PIDFile string
+	// RestartPolicy is the systemd restart policy of the unit.
+	RestartPolicy string
+	// StopTimeout is the timeout in seconds passed to podman stop.
+	StopTimeout uint
+	// PodmanVersion is the version of podman that generated the unit.
+	PodmanVersion string
+	// Requires are the units that must be started before this unit. A
+	// failure or stop of one of them stops this unit as well.
+	Requires []string
+	// Wants are the units that are started along with this unit, after
+	// it has been started.
+	Wants []string
+}
+
+// UnitFileName returns the name of the file the unit of the service is
+// written to.
+func (s *ServiceInfo) UnitFileName() string {
+	return s.ServiceName + ".service"
+}
+
+const unitTemplate = `# {{.ServiceName}}.service
+# autogenerated by Podman {{.PodmanVersion}}
+
+[Unit]
+Description=Podman {{.ServiceName}}.service
+Documentation=man:podman-generate-systemd(1)
+{{- if .Requires}}
+Requires={{join .Requires " "}}
+After={{join .Requires " "}}
+{{- end}}
+{{- if .Wants}}
+Wants={{join .Wants " "}}
+Before={{join .Wants " "}}
+{{- end}}
+
+[Service]
+Restart={{.RestartPolicy}}
+ExecStart={{.Executable}} start {{.Container}}
+ExecStop={{.Executable}} stop -t {{.StopTimeout}} {{.Container}}
+ExecStopPost={{.Executable}} container cleanup {{.Container}}
+KillMode=none
+Type=forking
+PIDFile={{.PIDFile}}
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// ValidateRestartPolicy checks that the given restart policy is supported by
+// systemd.
+func ValidateRestartPolicy(policy string) error {
+	for _, p := range RestartPolicies {
+		if p == policy {
+			return nil
+		}
+	}
+	return errors.Errorf("%s is not a valid restart policy, must be one of %s", policy, strings.Join(RestartPolicies, ", "))
+}
+
+// CreateSystemdUnitAsString generates the systemd unit file of the service
+// and returns it as a string.
+func CreateSystemdUnitAsString(info *ServiceInfo) (string, error) {
+	if info.ServiceName == "" || info.Container == "" {
+		return "", errors.New("a service name and container are required to generate a systemd unit")
+	}
+	if info.PIDFile == "" {
+		return "", errors.Errorf("container %s has no conmon PID file", info.Container)
+	}
+	if err := ValidateRestartPolicy(info.RestartPolicy); err != nil {
+		return "", err
+	}
+
+	requires := make([]string, 0, len(info.Requires))
+	for _, r := range info.Requires {
+		requires = append(requires, r+".service")
+	}
+	wants := make([]string, 0, len(info.Wants))
+	for _, w := range info.Wants {
+		wants = append(wants, w+".service")
+	}
+	data := *info
+	data.Requires = requires
+	data.Wants = wants
+
+	funcs := template.FuncMap{"join": strings.Join}
+	tmpl, err := template.New("systemd_unit").Funcs(funcs).Parse(unitTemplate)
+	if err != nil {
+		return "", errors.Wrapf(err, "error parsing systemd unit template")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrapf(err, "error generating systemd unit for %s", info.ServiceName)
+	}
+	return buf.String(), nil
+}
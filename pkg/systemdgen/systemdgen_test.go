@@ -0,0 +1,96 @@
+package systemdgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRestartPolicy(t *testing.T) {
+	for _, policy := range RestartPolicies {
+		assert.NoError(t, ValidateRestartPolicy(policy))
+	}
+	assert.Error(t, ValidateRestartPolicy(""))
+	assert.Error(t, ValidateRestartPolicy("sometimes"))
+}
+
+func TestCreateSystemdUnitAsString(t *testing.T) {
+	expected := `# container-foobar.service
+# autogenerated by Podman 1.0.0
+
+[Unit]
+Description=Podman container-foobar.service
+Documentation=man:podman-generate-systemd(1)
+
+[Service]
+Restart=always
+ExecStart=/usr/bin/podman start foobar
+ExecStop=/usr/bin/podman stop -t 10 foobar
+ExecStopPost=/usr/bin/podman container cleanup foobar
+KillMode=none
+Type=forking
+PIDFile=/var/run/containers/storage/overlay-containers/foobar/userdata/conmon.pid
+
+[Install]
+WantedBy=multi-user.target
+`
+	info := &ServiceInfo{
+		ServiceName:   "container-foobar",
+		Container:     "foobar",
+		Executable:    "/usr/bin/podman",
+		PIDFile:       "/var/run/containers/storage/overlay-containers/foobar/userdata/conmon.pid",
+		RestartPolicy: "always",
+		StopTimeout:   10,
+		PodmanVersion: "1.0.0",
+	}
+	unit, err := CreateSystemdUnitAsString(info)
+	require.NoError(t, err)
+	assert.Equal(t, expected, unit)
+	assert.Equal(t, "container-foobar.service", info.UnitFileName())
+}
+
+func TestCreateSystemdUnitAsStringDependencies(t *testing.T) {
+	info := &ServiceInfo{
+		ServiceName:   "pod-foo",
+		Container:     "foo-infra",
+		Executable:    "/usr/bin/podman",
+		PIDFile:       "/run/foo-infra/conmon.pid",
+		RestartPolicy: "on-failure",
+		Wants:         []string{"container-a", "container-b"},
+	}
+	unit, err := CreateSystemdUnitAsString(info)
+	require.NoError(t, err)
+	assert.Contains(t, unit, "\nWants=container-a.service container-b.service\n")
+	assert.Contains(t, unit, "\nBefore=container-a.service container-b.service\n")
+	assert.NotContains(t, unit, "Requires=")
+
+	info = &ServiceInfo{
+		ServiceName:   "container-a",
+		Container:     "a",
+		Executable:    "/usr/bin/podman",
+		PIDFile:       "/run/a/conmon.pid",
+		RestartPolicy: "on-failure",
+		Requires:      []string{"pod-foo"},
+	}
+	unit, err = CreateSystemdUnitAsString(info)
+	require.NoError(t, err)
+	assert.Contains(t, unit, "\nRequires=pod-foo.service\nAfter=pod-foo.service\n")
+	assert.NotContains(t, unit, "Wants=")
+}
+
+func TestCreateSystemdUnitAsStringInvalid(t *testing.T) {
+	info := &ServiceInfo{
+		ServiceName:   "container-foobar",
+		Container:     "foobar",
+		Executable:    "/usr/bin/podman",
+		RestartPolicy: "always",
+	}
+	_, err := CreateSystemdUnitAsString(info)
+	assert.Error(t, err)
+
+	info.PIDFile = "/run/foobar/conmon.pid"
+	info.RestartPolicy = "never"
+	_, err = CreateSystemdUnitAsString(info)
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package varlinkapi
+
+import (
+	"io"
+
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/libpod"
+	"github.com/containers/libpod/pkg/varlinkapi/virtwriter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ResizeContainerTty resizes the terminal of a running container
+func (i *LibpodAPI) ResizeContainerTty(call iopodman.VarlinkCall, name string, width, height int64) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	size := remotecommand.TerminalSize{
+		Width:  uint16(width),
+		Height: uint16(height),
+	}
+	if err := ctr.AttachResize(size); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyResizeContainerTty()
+}
+
+// AttachToContainer attaches the client to the standard streams of a container
+// over an upgraded connection, see the virtwriter package for the protocol
+func (i *LibpodAPI) AttachToContainer(call iopodman.VarlinkCall, name, detachKeys string, stdin, start bool) error {
+	if !call.WantsUpgrade() {
+		return call.ReplyErrorOccurred("client must use upgraded connection to attach")
+	}
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	state, err := ctr.State()
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	if !start && state != libpod.ContainerStateRunning {
+		return call.ReplyErrorOccurred("you can only attach to running containers")
+	}
+
+	if err := call.ReplyAttachToContainer(); err != nil {
+		return err
+	}
+
+	return serveAttach(call, stdin, func(streams *libpod.AttachStreams, resize chan remotecommand.TerminalSize) error {
+		return attachToContainer(ctr, streams, detachKeys, resize, start)
+	})
+}
+
+// serveAttach runs attach with streams multiplexed over the upgraded
+// connection of call, see the virtwriter package for the protocol. It must be
+// called once the call was replied to.
+func serveAttach(call iopodman.VarlinkCall, stdin bool, attach func(*libpod.AttachStreams, chan remotecommand.TerminalSize) error) error {
+	mux := virtwriter.NewMux(call.Call.Writer)
+	writers := make(map[virtwriter.SocketDest]io.Writer)
+	streams := &libpod.AttachStreams{
+		OutputStream: nopCloser{mux.Writer(virtwriter.ToStdout)},
+		ErrorStream:  nopCloser{mux.Writer(virtwriter.ToStderr)},
+		AttachOutput: true,
+		AttachError:  true,
+	}
+	var inputReader *io.PipeReader
+	if stdin {
+		var inputWriter *io.PipeWriter
+		inputReader, inputWriter = io.Pipe()
+		writers[virtwriter.ToStdin] = inputWriter
+		streams.InputStream = inputReader
+		streams.AttachInput = true
+	}
+
+	resize := make(chan remotecommand.TerminalSize)
+	demuxDone := make(chan error, 1)
+	go func() {
+		err := virtwriter.Demux(call.Call.Reader, writers, resize)
+		if w, ok := writers[virtwriter.ToStdin]; ok {
+			w.(io.Closer).Close()
+		}
+		close(resize)
+		demuxDone <- err
+	}()
+
+	attachErr := attach(streams, resize)
+	// Input and resize events sent after the attach session ended are dropped
+	if inputReader != nil {
+		inputReader.Close()
+	}
+	go func() {
+		for range resize {
+		}
+	}()
+	if err := mux.Quit(attachErr); err != nil {
+		return err
+	}
+	// The client closes the connection once it received the quit frame. An
+	// error closes the connection from our side, as it is no longer usable
+	// for varlink calls.
+	return <-demuxDone
+}
+
+// attachToContainer attaches to ctr, starting it first if start is set. As no
+// exit command is set for containers started attached, they are cleaned up
+// once they exit.
+func attachToContainer(ctr *libpod.Container, streams *libpod.AttachStreams, detachKeys string, resize chan remotecommand.TerminalSize, start bool) error {
+	if !start {
+		return ctr.Attach(streams, detachKeys, resize)
+	}
+
+	attachChan, err := ctr.StartAndAttach(getContext(), streams, detachKeys, resize)
+	if err != nil {
+		return err
+	}
+	if err := <-attachChan; err != nil {
+		return errors.Wrapf(err, "error attaching to container %s", ctr.ID())
+	}
+	if _, err := ctr.Wait(); err != nil {
+		return errors.Wrapf(err, "error waiting for container %s", ctr.ID())
+	}
+	if err := ctr.Cleanup(getContext()); err != nil {
+		if errors.Cause(err) != libpod.ErrNoSuchCtr && errors.Cause(err) != libpod.ErrCtrRemoved {
+			logrus.Errorf("unable to cleanup container %s: %v", ctr.ID(), err)
+		}
+	}
+	return nil
+}
+
+// nopCloser turns an io.Writer into an io.WriteCloser whose Close does nothing
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -120,6 +122,9 @@ func (i *LibpodAPI) GetContainerLogs(call iopodman.VarlinkCall, name string) err
 	if err != nil {
 		return call.ReplyContainerNotFound(name)
 	}
+	if ctr.LogDriver() != libpod.KubernetesLogging {
+		return call.ReplyErrorOccurred(fmt.Sprintf("container %s uses the %s log driver, only %s logs can be read remotely", ctr.ID(), ctr.LogDriver(), libpod.KubernetesLogging))
+	}
 	logPath := ctr.LogPath()
 
 	containerState, err := ctr.State()
@@ -216,6 +221,65 @@ func (i *LibpodAPI) ExportContainer(call iopodman.VarlinkCall, name, outPath str
 
 }
 
+// CopyToContainer copies the contents of a tar archive into a container
+func (i *LibpodAPI) CopyToContainer(call iopodman.VarlinkCall, name, tarball, source, path string, pause bool) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	tarFile, err := os.Open(tarball)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	defer os.Remove(tarball)
+	defer tarFile.Close()
+
+	if source == "" {
+		if err := ctr.CopyFromArchive(tarFile, path, pause); err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+		return call.ReplyCopyToContainer(ctr.ID())
+	}
+
+	// Extract the source into a temporary directory, and copy it from
+	// there, so that it can be copied to a new name like local copies
+	tempDir, err := ioutil.TempDir("", "varlink_copy")
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	defer os.RemoveAll(tempDir)
+	if err := archive.Untar(tarFile, tempDir, &archive.TarOptions{NoLchown: true}); err != nil {
+		return call.ReplyErrorOccurred(errors.Wrapf(err, "error extracting archive of %q", source).Error())
+	}
+	srcPath := filepath.Join(tempDir, filepath.Base(filepath.Clean(source)))
+	if strings.HasSuffix(source, "/.") {
+		srcPath += "/."
+	}
+	if err := ctr.CopyFromHost(srcPath, path, pause); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyCopyToContainer(ctr.ID())
+}
+
+// CopyFromContainer creates a tar archive of a path in a container
+func (i *LibpodAPI) CopyFromContainer(call iopodman.VarlinkCall, name, path string, pause bool) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	outputFile, err := ioutil.TempFile("", "varlink_copy")
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	defer outputFile.Close()
+
+	if err := ctr.CopyToArchive(path, outputFile, pause); err != nil {
+		os.Remove(outputFile.Name())
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyCopyFromContainer(outputFile.Name())
+}
+
 // GetContainerStats ...
 func (i *LibpodAPI) GetContainerStats(call iopodman.VarlinkCall, name string) error {
 	ctr, err := i.Runtime.LookupContainer(name)
@@ -247,11 +311,6 @@ func (i *LibpodAPI) GetContainerStats(call iopodman.VarlinkCall, name string) er
 	return call.ReplyGetContainerStats(cs)
 }
 
-// ResizeContainerTty ...
-func (i *LibpodAPI) ResizeContainerTty(call iopodman.VarlinkCall) error {
-	return call.ReplyMethodNotImplemented("ResizeContainerTty")
-}
-
 // StartContainer ...
 func (i *LibpodAPI) StartContainer(call iopodman.VarlinkCall, name string) error {
 	ctr, err := i.Runtime.LookupContainer(name)
@@ -325,13 +384,50 @@ func (i *LibpodAPI) KillContainer(call iopodman.VarlinkCall, name string, signal
 }
 
 // UpdateContainer ...
-func (i *LibpodAPI) UpdateContainer(call iopodman.VarlinkCall) error {
-	return call.ReplyMethodNotImplemented("UpdateContainer")
+func (i *LibpodAPI) UpdateContainer(call iopodman.VarlinkCall, name string, resources iopodman.UpdateResources) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	// Check the ranges before the limits are converted, as out of range
+	// values would wrap around. A weight of 0 leaves it unchanged.
+	if resources.Blkio_weight != 0 && (resources.Blkio_weight < 10 || resources.Blkio_weight > 1000) {
+		return call.ReplyErrorOccurred(fmt.Sprintf("invalid blkio-weight %d: must be between 10 and 1000", resources.Blkio_weight))
+	}
+	if resources.Cpu_shares < 0 {
+		return call.ReplyErrorOccurred(fmt.Sprintf("invalid cpu-shares %d: must not be negative", resources.Cpu_shares))
+	}
+	update := cc.UpdateResourceConfig{
+		BlkioWeight: uint16(resources.Blkio_weight),
+		CPUs:        resources.Cpus,
+		CPUShares:   uint64(resources.Cpu_shares),
+		Memory:      resources.Memory,
+		MemorySwap:  resources.Memory_swap,
+		PidsLimit:   resources.Pids_limit,
+	}
+	if update == (cc.UpdateResourceConfig{}) {
+		return call.ReplyErrorOccurred("must provide at least one resource limit to update")
+	}
+	ociResources, err := update.ToOCIResources()
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	if err := ctr.Update(ociResources); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyUpdateContainer(ctr.ID())
 }
 
 // RenameContainer ...
-func (i *LibpodAPI) RenameContainer(call iopodman.VarlinkCall) error {
-	return call.ReplyMethodNotImplemented("RenameContainer")
+func (i *LibpodAPI) RenameContainer(call iopodman.VarlinkCall, name, newName string) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	if err := i.Runtime.RenameContainer(ctr, newName); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyRenameContainer(ctr.ID())
 }
 
 // PauseContainer ...
@@ -358,12 +454,6 @@ func (i *LibpodAPI) UnpauseContainer(call iopodman.VarlinkCall, name string) err
 	return call.ReplyUnpauseContainer(ctr.ID())
 }
 
-// AttachToContainer ...
-// TODO: DO we also want a different one for websocket?
-func (i *LibpodAPI) AttachToContainer(call iopodman.VarlinkCall) error {
-	return call.ReplyMethodNotImplemented("AttachToContainer")
-}
-
 // WaitContainer ...
 func (i *LibpodAPI) WaitContainer(call iopodman.VarlinkCall, name string) error {
 	ctr, err := i.Runtime.LookupContainer(name)
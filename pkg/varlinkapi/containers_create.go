@@ -108,6 +108,21 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		user = data.Config.User
 	}
 
+	// ENVIRONMENT VARIABLES
+	// User input environment variables take priority over image ones
+	env := make(map[string]string)
+	for _, e := range data.Config.Env {
+		split := strings.SplitN(e, "=", 2)
+		if len(split) > 1 {
+			env[split[0]] = split[1]
+		} else {
+			env[split[0]] = ""
+		}
+	}
+	for key, value := range create.Env {
+		env[key] = value
+	}
+
 	// EXPOSED PORTS
 	portBindings, err := cc.ExposedPorts(create.Exposed_ports, create.Publish, create.Publish_all, data.Config.ExposedPorts)
 	if err != nil {
@@ -146,7 +161,7 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		DNSSearch:         create.Dns_search,
 		DNSServers:        create.Dns_servers,
 		Entrypoint:        create.Entrypoint,
-		Env:               create.Env,
+		Env:               env,
 		GroupAdd:          create.Group_add,
 		Hostname:          create.Hostname,
 		HostAdd:           create.Host_add,
@@ -154,9 +169,12 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		Image:             imageName,
 		ImageID:           imageID,
 		Interactive:       create.Interactive,
+		IPAddress:         create.Ip_addresses,
+		IP6Address:        create.Ip6_addresses,
 		Labels:            create.Labels,
 		LogDriver:         create.Log_driver,
 		LogDriverOpt:      create.Log_driver_opt,
+		MacAddress:        create.Mac_address,
 		Name:              create.Name,
 		Network:           networkMode,
 		IpcMode:           namespaces.IpcMode(create.Ipc_mode),
@@ -168,6 +186,8 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 		Publish:           create.Publish,
 		PublishAll:        create.Publish_all,
 		PortBindings:      portBindings,
+		PortForwarder:     create.Port_forwarder,
+		PortPeerAddress:   create.Port_peer_address,
 		Quiet:             create.Quiet,
 		ReadOnlyRootfs:    create.Readonly_rootfs,
 		Resources: cc.CreateResourceConfig{
@@ -196,16 +216,17 @@ func varlinkCreateToCreateConfig(ctx context.Context, create iopodman.Create, ru
 			PidsLimit:         create.Resources.Pids_limit,
 			Ulimit:            create.Resources.Ulimit,
 		},
-		Rm:          create.Rm,
-		StopSignal:  stopSignal,
-		StopTimeout: uint(create.Stop_timeout),
-		Sysctl:      create.Sys_ctl,
-		Tmpfs:       create.Tmpfs,
-		Tty:         create.Tty,
-		User:        user,
-		UsernsMode:  namespaces.UsernsMode(create.Userns_mode),
-		Volumes:     create.Volumes,
-		WorkDir:     workDir,
+		RestartPolicy: create.Restart_policy,
+		Rm:            create.Rm,
+		StopSignal:    stopSignal,
+		StopTimeout:   uint(create.Stop_timeout),
+		Sysctl:        create.Sys_ctl,
+		Tmpfs:         create.Tmpfs,
+		Tty:           create.Tty,
+		User:          user,
+		UsernsMode:    namespaces.UsernsMode(create.Userns_mode),
+		Volumes:       create.Volumes,
+		WorkDir:       workDir,
 	}
 
 	return config, nil
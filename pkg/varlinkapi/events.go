@@ -0,0 +1,52 @@
+package varlinkapi
+
+import (
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/libpod/events"
+)
+
+// GetEvents is a remote endpoint to get events from the event log
+func (i *LibpodAPI) GetEvents(call iopodman.VarlinkCall, filter []string, since string, until string) error {
+	var stream bool
+	if call.WantsMore() {
+		stream = true
+		call.Continues = true
+	}
+	eventChannel := make(chan *events.Event)
+	errChannel := make(chan error)
+	go func() {
+		readOpts := events.ReadOptions{
+			EventChannel: eventChannel,
+			Filters:      filter,
+			Since:        since,
+			Stream:       stream,
+			Until:        until,
+		}
+		errChannel <- i.Runtime.Events(readOpts)
+	}()
+
+	eventList := []iopodman.Event{}
+	for event := range eventChannel {
+		e := iopodman.Event{
+			Id:         event.ID,
+			Name:       event.Name,
+			Status:     event.Status.String(),
+			Time:       event.Time.Format(time.RFC3339Nano),
+			Type:       event.Type.String(),
+			Attributes: event.Attributes,
+		}
+		if stream {
+			// Send every event as soon as it arrives
+			call.ReplyGetEvents([]iopodman.Event{e})
+			continue
+		}
+		eventList = append(eventList, e)
+	}
+	if err := <-errChannel; err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	call.Continues = false
+	return call.ReplyGetEvents(eventList)
+}
@@ -0,0 +1,140 @@
+package varlinkapi
+
+import (
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/libpod"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// CreateExec creates an exec session in a running container
+func (i *LibpodAPI) CreateExec(call iopodman.VarlinkCall, name string, config iopodman.ExecConfig) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	execConfig := &libpod.ExecConfig{
+		Command:    config.Command,
+		Env:        config.Env,
+		Terminal:   config.Tty,
+		Privileged: config.Privileged,
+		User:       config.User,
+		WorkDir:    config.Workdir,
+	}
+	sessionID, err := ctr.ExecCreate(execConfig)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyCreateExec(sessionID)
+}
+
+// StartExec starts a created exec session. Unless detach is set, the client is
+// attached to the command over an upgraded connection, see the virtwriter
+// package for the protocol.
+func (i *LibpodAPI) StartExec(call iopodman.VarlinkCall, name, sessionID string, detach, stdin bool) error {
+	if !detach && !call.WantsUpgrade() {
+		return call.ReplyErrorOccurred("client must use upgraded connection to attach")
+	}
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+
+	if detach {
+		if err := ctr.ExecStartDetached(sessionID); err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+		return call.ReplyStartExec()
+	}
+
+	session, err := ctr.ExecSession(sessionID)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	if session.State != libpod.ExecStateCreated {
+		return call.ReplyErrorOccurred(errors.Wrapf(libpod.ErrExecSessionStateInvalid, "can only start created exec sessions, exec session %s is %s", sessionID, session.State.String()).Error())
+	}
+
+	if err := call.ReplyStartExec(); err != nil {
+		return err
+	}
+	return serveAttach(call, stdin, func(streams *libpod.AttachStreams, resize chan remotecommand.TerminalSize) error {
+		return ctr.ExecStart(sessionID, streams, resize)
+	})
+}
+
+// ResizeExecTty resizes the terminal of a running exec session
+func (i *LibpodAPI) ResizeExecTty(call iopodman.VarlinkCall, name, sessionID string, width, height int64) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	size := remotecommand.TerminalSize{
+		Width:  uint16(width),
+		Height: uint16(height),
+	}
+	if err := ctr.ExecResize(sessionID, size); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyResizeExecTty()
+}
+
+// InspectExec returns information on an exec session of a container
+func (i *LibpodAPI) InspectExec(call iopodman.VarlinkCall, name, sessionID string) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	session, err := ctr.ExecSession(sessionID)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyInspectExec(makeExecSession(session))
+}
+
+// ListExecSessions returns the exec sessions of a container
+func (i *LibpodAPI) ListExecSessions(call iopodman.VarlinkCall, name string) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	sessionIDs, err := ctr.ExecSessions()
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	sessions := make([]iopodman.ExecSession, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		session, err := ctr.ExecSession(sessionID)
+		if err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+		sessions = append(sessions, makeExecSession(session))
+	}
+	return call.ReplyListExecSessions(sessions)
+}
+
+// RemoveExec removes an exec session that is not running from a container
+func (i *LibpodAPI) RemoveExec(call iopodman.VarlinkCall, name, sessionID string) error {
+	ctr, err := i.Runtime.LookupContainer(name)
+	if err != nil {
+		return call.ReplyContainerNotFound(name)
+	}
+	if err := ctr.ExecRemove(sessionID); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyRemoveExec()
+}
+
+func makeExecSession(session *libpod.ExecSession) iopodman.ExecSession {
+	execSession := iopodman.ExecSession{
+		Id:       session.ID,
+		Command:  session.Command,
+		State:    session.State.String(),
+		Pid:      int64(session.PID),
+		ExitCode: int64(session.ExitCode),
+	}
+	if session.Config != nil {
+		execSession.Tty = session.Config.Terminal
+	}
+	return execSession
+}
@@ -0,0 +1,122 @@
+package varlinkapi
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/pkg/network"
+	"github.com/pkg/errors"
+)
+
+// ListNetworks returns the CNI networks configured on the host
+func (i *LibpodAPI) ListNetworks(call iopodman.VarlinkCall) error {
+	networks, err := i.Runtime.Networks()
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	listNetworks := make([]iopodman.Network, 0, len(networks))
+	for _, n := range networks {
+		listNetworks = append(listNetworks, iopodman.Network{
+			Name:       n.Name,
+			CniVersion: n.CNIVersion,
+			Plugins:    n.Plugins,
+			Subnets:    n.Subnets,
+			Labels:     n.Labels,
+			Path:       n.Path,
+		})
+	}
+	return call.ReplyListNetworks(listNetworks)
+}
+
+// CreateNetwork creates a CNI network
+func (i *LibpodAPI) CreateNetwork(call iopodman.VarlinkCall, create iopodman.NetworkCreate) error {
+	options := network.CreateOptions{
+		Name:     create.Name,
+		Driver:   create.Driver,
+		Internal: create.Internal,
+		Labels:   create.Labels,
+		Options:  create.Options,
+	}
+	var err error
+	if create.Subnet != "" {
+		if options.Subnet, err = network.ParseSubnet(create.Subnet); err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+	}
+	if create.IpRange != "" {
+		if options.IPRange, err = network.ParseSubnet(create.IpRange); err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+	}
+	if create.Gateway != "" {
+		if options.Gateway = net.ParseIP(create.Gateway); options.Gateway == nil {
+			return call.ReplyErrorOccurred(fmt.Sprintf("invalid gateway %q", create.Gateway))
+		}
+	}
+
+	created, err := i.Runtime.CreateNetwork(options)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyCreateNetwork(created.Name)
+}
+
+// InspectNetwork returns the configuration list of a CNI network
+func (i *LibpodAPI) InspectNetwork(call iopodman.VarlinkCall, name string) error {
+	n, err := i.Runtime.GetNetwork(name)
+	if err != nil {
+		if errors.Cause(err) == network.ErrNetworkNotFound {
+			return call.ReplyNetworkNotFound(name)
+		}
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyInspectNetwork(string(n.Config))
+}
+
+// RemoveNetwork removes a CNI network
+func (i *LibpodAPI) RemoveNetwork(call iopodman.VarlinkCall, name string, force bool) error {
+	if err := i.Runtime.RemoveNetwork(getContext(), name, force); err != nil {
+		if errors.Cause(err) == network.ErrNetworkNotFound {
+			return call.ReplyNetworkNotFound(name)
+		}
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyRemoveNetwork(name)
+}
+
+// ConnectNetwork connects a container to a CNI network
+func (i *LibpodAPI) ConnectNetwork(call iopodman.VarlinkCall, name, container, ip string) error {
+	ctr, err := i.Runtime.LookupContainer(container)
+	if err != nil {
+		return call.ReplyContainerNotFound(container)
+	}
+	var staticIP net.IP
+	if ip != "" {
+		if staticIP = net.ParseIP(ip); staticIP == nil {
+			return call.ReplyErrorOccurred(fmt.Sprintf("invalid IP address %q", ip))
+		}
+	}
+	if err := ctr.ConnectNetwork(name, staticIP); err != nil {
+		if errors.Cause(err) == network.ErrNetworkNotFound {
+			return call.ReplyNetworkNotFound(name)
+		}
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyConnectNetwork(ctr.ID())
+}
+
+// DisconnectNetwork disconnects a container from a CNI network
+func (i *LibpodAPI) DisconnectNetwork(call iopodman.VarlinkCall, name, container string) error {
+	ctr, err := i.Runtime.LookupContainer(container)
+	if err != nil {
+		return call.ReplyContainerNotFound(container)
+	}
+	if err := ctr.DisconnectNetwork(name); err != nil {
+		if errors.Cause(err) == network.ErrNetworkNotFound {
+			return call.ReplyNetworkNotFound(name)
+		}
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyDisconnectNetwork(ctr.ID())
+}
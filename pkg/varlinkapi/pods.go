@@ -222,6 +222,41 @@ func (i *LibpodAPI) UnpausePod(call iopodman.VarlinkCall, name string) error {
 	return call.ReplyUnpausePod(pod.ID())
 }
 
+// PodCheckpoint ...
+func (i *LibpodAPI) PodCheckpoint(call iopodman.VarlinkCall, name string, keep, leaveRunning, tcpEstablished bool) error {
+	pod, err := i.Runtime.LookupPod(name)
+	if err != nil {
+		return call.ReplyPodNotFound(name)
+	}
+	options := libpod.ContainerCheckpointOptions{
+		Keep:           keep,
+		KeepRunning:    leaveRunning,
+		TCPEstablished: tcpEstablished,
+	}
+	ctrErrs, err := pod.Checkpoint(getContext(), options)
+	if ctrErrs != nil || err != nil {
+		return handlePodCall(call, pod, ctrErrs, err)
+	}
+	return call.ReplyPodCheckpoint(pod.ID())
+}
+
+// PodRestore ...
+func (i *LibpodAPI) PodRestore(call iopodman.VarlinkCall, name string, keep, tcpEstablished bool) error {
+	pod, err := i.Runtime.LookupPod(name)
+	if err != nil {
+		return call.ReplyPodNotFound(name)
+	}
+	options := libpod.ContainerCheckpointOptions{
+		Keep:           keep,
+		TCPEstablished: tcpEstablished,
+	}
+	ctrErrs, err := pod.Restore(getContext(), options)
+	if ctrErrs != nil || err != nil {
+		return handlePodCall(call, pod, ctrErrs, err)
+	}
+	return call.ReplyPodRestore(pod.ID())
+}
+
 // RemovePod ...
 func (i *LibpodAPI) RemovePod(call iopodman.VarlinkCall, name string, force bool) error {
 	ctx := getContext()
@@ -3,7 +3,9 @@ package varlinkapi
 import (
 	goruntime "runtime"
 	"strings"
+	"time"
 
+	"github.com/containers/libpod/cmd/podman/shared"
 	"github.com/containers/libpod/cmd/podman/varlink"
 	"github.com/containers/libpod/libpod"
 )
@@ -105,3 +107,58 @@ func (i *LibpodAPI) GetInfo(call iopodman.VarlinkCall) error {
 	podmanInfo.Insecure_registries = insecureRegistries
 	return call.ReplyGetInfo(podmanInfo)
 }
+
+// GetDiskUsage returns the disk usage of the images, containers and local
+// volumes
+func (i *LibpodAPI) GetDiskUsage(call iopodman.VarlinkCall) error {
+	du, err := shared.GetDiskUsage(getContext(), i.Runtime)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+
+	diskUsage := iopodman.DiskUsage{
+		Images:     []iopodman.ImageDiskUsage{},
+		Containers: []iopodman.ContainerDiskUsage{},
+		Volumes:    []iopodman.VolumeDiskUsage{},
+	}
+	for _, img := range du.Images {
+		diskUsage.Images = append(diskUsage.Images, iopodman.ImageDiskUsage{
+			Id:         img.ID,
+			Names:      img.Names,
+			Created:    img.Created.Format(time.RFC3339),
+			Size:       img.Size,
+			SharedSize: img.SharedSize,
+			UniqueSize: img.UniqueSize,
+			Containers: int64(img.Containers),
+		})
+	}
+	for _, ctr := range du.Containers {
+		diskUsage.Containers = append(diskUsage.Containers, iopodman.ContainerDiskUsage{
+			Id:           ctr.ID,
+			Name:         ctr.Name,
+			Image:        ctr.Image,
+			Command:      ctr.Command,
+			LocalVolumes: int64(ctr.LocalVolumes),
+			Size:         ctr.Size,
+			Created:      ctr.Created.Format(time.RFC3339),
+			State:        ctr.State,
+		})
+	}
+	for _, vol := range du.Volumes {
+		diskUsage.Volumes = append(diskUsage.Volumes, iopodman.VolumeDiskUsage{
+			Name:  vol.Name,
+			Links: int64(vol.Links),
+			Size:  vol.Size,
+		})
+	}
+	for _, s := range du.Summary {
+		diskUsage.Summary = append(diskUsage.Summary, iopodman.DiskUsageSummary{
+			Type:        s.Type,
+			Total:       int64(s.Total),
+			Active:      int64(s.Active),
+			Size:        s.Size,
+			Reclaimable: s.Reclaimable,
+		})
+	}
+	return call.ReplyGetDiskUsage(diskUsage)
+}
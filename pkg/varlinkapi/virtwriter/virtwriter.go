@@ -0,0 +1,164 @@
+// Package virtwriter multiplexes the standard streams of a container and the
+// resize events of its terminal over a single upgraded varlink connection.
+//
+// Every frame starts with an 8 byte header. The first byte is the SocketDest
+// of the frame, bytes 4 to 7 are the length of the payload as a big endian
+// unsigned integer. The remaining header bytes are reserved and set to 0.
+package virtwriter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// SocketDest is the destination of a frame
+type SocketDest byte
+
+const (
+	// ToStdin frames carry data for the standard input of the container.
+	// An empty ToStdin frame closes the standard input.
+	ToStdin SocketDest = iota
+	// ToStdout frames carry data from the standard output of the container
+	ToStdout
+	// ToStderr frames carry data from the standard error of the container
+	ToStderr
+	// TerminalResize frames carry the new width and height of the terminal
+	// as two big endian 16 bit unsigned integers
+	TerminalResize
+	// Quit frames end the stream. Their payload is an error message, and
+	// is empty if the stream ended successfully.
+	Quit
+)
+
+const headerLength = 8
+
+// Mux writes frames to a connection. It is safe for concurrent use.
+type Mux struct {
+	lock   sync.Mutex
+	writer *bufio.Writer
+}
+
+// NewMux returns a Mux writing to the given writer
+func NewMux(w *bufio.Writer) *Mux {
+	return &Mux{writer: w}
+}
+
+// WriteFrame writes a single frame with the given destination and payload and
+// flushes it to the connection
+func (m *Mux) WriteFrame(dest SocketDest, payload []byte) error {
+	header := make([]byte, headerLength)
+	header[0] = byte(dest)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, err := m.writer.Write(header); err != nil {
+		return err
+	}
+	if _, err := m.writer.Write(payload); err != nil {
+		return err
+	}
+	return m.writer.Flush()
+}
+
+// Resize writes a TerminalResize frame for the given size
+func (m *Mux) Resize(size remotecommand.TerminalSize) error {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload[0:], size.Width)
+	binary.BigEndian.PutUint16(payload[2:], size.Height)
+	return m.WriteFrame(TerminalResize, payload)
+}
+
+// Quit writes a Quit frame carrying the given error, which may be nil
+func (m *Mux) Quit(err error) error {
+	var payload []byte
+	if err != nil {
+		payload = []byte(err.Error())
+	}
+	return m.WriteFrame(Quit, payload)
+}
+
+// Writer returns an io.Writer sending everything written to it in frames with
+// the given destination
+func (m *Mux) Writer(dest SocketDest) io.Writer {
+	return &destWriter{mux: m, dest: dest}
+}
+
+type destWriter struct {
+	mux  *Mux
+	dest SocketDest
+}
+
+func (w *destWriter) Write(p []byte) (int, error) {
+	// An empty frame has a meaning of its own
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := w.mux.WriteFrame(w.dest, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Demux reads frames from r until it reads a Quit frame or reaches the end of
+// the stream. The payload of each frame is written to the writer given for its
+// destination, or dropped if there is none. Empty frames close the writer of
+// their destination if it is an io.Closer. Resize events are sent to resize
+// if it is not nil.
+// The error carried by the Quit frame is returned, or nil if the stream ended
+// without one.
+func Demux(r io.Reader, writers map[SocketDest]io.Writer, resize chan<- remotecommand.TerminalSize) error {
+	header := make([]byte, headerLength)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrapf(err, "error reading frame header")
+		}
+		dest := SocketDest(header[0])
+		payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return errors.Wrapf(err, "error reading frame payload")
+		}
+
+		switch dest {
+		case Quit:
+			if len(payload) > 0 {
+				return errors.New(string(payload))
+			}
+			return nil
+		case TerminalResize:
+			if len(payload) != 4 {
+				return errors.Errorf("invalid terminal resize frame of length %d", len(payload))
+			}
+			if resize != nil {
+				resize <- remotecommand.TerminalSize{
+					Width:  binary.BigEndian.Uint16(payload[0:]),
+					Height: binary.BigEndian.Uint16(payload[2:]),
+				}
+			}
+		default:
+			w, ok := writers[dest]
+			if !ok {
+				continue
+			}
+			if len(payload) == 0 {
+				if closer, ok := w.(io.Closer); ok {
+					if err := closer.Close(); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if _, err := w.Write(payload); err != nil {
+				return err
+			}
+		}
+	}
+}
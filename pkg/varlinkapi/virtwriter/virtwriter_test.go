@@ -0,0 +1,77 @@
+package virtwriter
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestMuxDemux(t *testing.T) {
+	var conn bytes.Buffer
+	mux := NewMux(bufio.NewWriter(&conn))
+
+	io.WriteString(mux.Writer(ToStdout), "hello ")
+	io.WriteString(mux.Writer(ToStderr), "oops")
+	assert.NoError(t, mux.Resize(remotecommand.TerminalSize{Width: 80, Height: 24}))
+	io.WriteString(mux.Writer(ToStdout), "world")
+	io.WriteString(mux.Writer(ToStdin), "input")
+	assert.NoError(t, mux.WriteFrame(ToStdin, nil))
+	assert.NoError(t, mux.Quit(nil))
+	io.WriteString(mux.Writer(ToStdout), "after quit")
+
+	var stdout, stderr bytes.Buffer
+	stdin := new(closeBuffer)
+	resize := make(chan remotecommand.TerminalSize, 1)
+	err := Demux(&conn, map[SocketDest]io.Writer{
+		ToStdout: &stdout,
+		ToStderr: &stderr,
+		ToStdin:  stdin,
+	}, resize)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", stdout.String())
+	assert.Equal(t, "oops", stderr.String())
+	assert.Equal(t, "input", stdin.String())
+	assert.True(t, stdin.closed)
+	assert.Equal(t, remotecommand.TerminalSize{Width: 80, Height: 24}, <-resize)
+}
+
+func TestDemuxQuitError(t *testing.T) {
+	var conn bytes.Buffer
+	mux := NewMux(bufio.NewWriter(&conn))
+	io.WriteString(mux.Writer(ToStdout), "dropped")
+	assert.NoError(t, mux.Quit(errors.New("container exited")))
+
+	err := Demux(&conn, nil, nil)
+	assert.EqualError(t, err, "container exited")
+}
+
+func TestDemuxEndOfStream(t *testing.T) {
+	var conn bytes.Buffer
+	mux := NewMux(bufio.NewWriter(&conn))
+	io.WriteString(mux.Writer(ToStdout), "data")
+
+	var stdout bytes.Buffer
+	assert.NoError(t, Demux(&conn, map[SocketDest]io.Writer{ToStdout: &stdout}, nil))
+	assert.Equal(t, "data", stdout.String())
+
+	// A truncated frame is an error
+	conn.Reset()
+	io.WriteString(mux.Writer(ToStdout), "truncated")
+	conn.Truncate(conn.Len() - 1)
+	assert.Error(t, Demux(&conn, map[SocketDest]io.Writer{ToStdout: &stdout}, nil))
+}
@@ -0,0 +1,84 @@
+package varlinkapi
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/containers/libpod/cmd/podman/varlink"
+	"github.com/containers/libpod/libpod"
+)
+
+// ListVolumes returns the volumes matching the given filters
+func (i *LibpodAPI) ListVolumes(call iopodman.VarlinkCall, filters []string) error {
+	var filterFuncs []libpod.VolumeFilter
+	for _, filter := range filters {
+		filterFunc, err := libpod.GenerateVolumeFilter(filter)
+		if err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+		filterFuncs = append(filterFuncs, filterFunc)
+	}
+
+	volumes, err := i.Runtime.Volumes(filterFuncs...)
+	if err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	listVolumes := make([]iopodman.Volume, 0, len(volumes))
+	for _, vol := range volumes {
+		mountCount, err := vol.MountCount()
+		if err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+		var created string
+		if !vol.CreatedTime().IsZero() {
+			created = vol.CreatedTime().Format(time.RFC3339)
+		}
+		listVolumes = append(listVolumes, iopodman.Volume{
+			Name:       vol.Name(),
+			Labels:     vol.Labels(),
+			MountPoint: vol.MountPoint(),
+			Driver:     vol.Driver(),
+			Options:    vol.Options(),
+			Scope:      vol.Scope(),
+			MountCount: int64(mountCount),
+			CreatedAt:  created,
+		})
+	}
+	return call.ReplyListVolumes(listVolumes)
+}
+
+// ExportVolume creates a tar archive of the contents of a volume
+func (i *LibpodAPI) ExportVolume(call iopodman.VarlinkCall, name, path string) error {
+	vol, err := i.Runtime.GetVolume(name)
+	if err != nil {
+		return call.ReplyVolumeNotFound(name)
+	}
+	if path == "" {
+		outputFile, err := ioutil.TempFile("", "varlink_volume")
+		if err != nil {
+			return call.ReplyErrorOccurred(err.Error())
+		}
+		outputFile.Close()
+		path = outputFile.Name()
+	}
+	if err := vol.Export(path); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyExportVolume(path)
+}
+
+// ImportVolume extracts a tar archive into a volume
+func (i *LibpodAPI) ImportVolume(call iopodman.VarlinkCall, name, tarball string, delete bool) error {
+	vol, err := i.Runtime.GetVolume(name)
+	if err != nil {
+		return call.ReplyVolumeNotFound(name)
+	}
+	if delete {
+		defer os.Remove(tarball)
+	}
+	if err := vol.Import(tarball); err != nil {
+		return call.ReplyErrorOccurred(err.Error())
+	}
+	return call.ReplyImportVolume(vol.Name())
+}
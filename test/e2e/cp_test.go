@@ -0,0 +1,164 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Podman cp", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman cp file into and out of a container", func() {
+		srcPath := filepath.Join(podmanTest.TempDir, "cp-src.txt")
+		destPath := filepath.Join(podmanTest.TempDir, "cp-dest.txt")
+		err := ioutil.WriteFile(srcPath, []byte("copy me"), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"create", "--name", "cpctr", ALPINE, "cat", "/tmp/cp-test.txt"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"cp", srcPath, "cpctr:/tmp/cp-test.txt"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"start", "-a", "cpctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("copy me"))
+
+		session = podmanTest.Podman([]string{"cp", "cpctr:/tmp/cp-test.txt", destPath})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		content, err := ioutil.ReadFile(destPath)
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("copy me"))
+	})
+
+	It("podman cp directory into an existing directory", func() {
+		srcDir := filepath.Join(podmanTest.TempDir, "cp-dir")
+		err := os.Mkdir(srcDir, 0755)
+		Expect(err).To(BeNil())
+		err = ioutil.WriteFile(filepath.Join(srcDir, "file.txt"), []byte("in a dir"), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"create", "--name", "cpdir", ALPINE, "cat", "/tmp/cp-dir/file.txt"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"cp", srcDir, "cpdir:/tmp"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"start", "-a", "cpdir"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("in a dir"))
+	})
+
+	It("podman cp file owned by the container user", func() {
+		srcPath := filepath.Join(podmanTest.TempDir, "cp-owner.txt")
+		err := ioutil.WriteFile(srcPath, []byte("owned"), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"create", "--name", "cpuser", "--user", "2:2", ALPINE, "stat", "-c", "%u:%g", "/tmp/cp-owner.txt"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"cp", srcPath, "cpuser:/tmp"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"start", "-a", "cpuser"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("2:2"))
+	})
+
+	It("podman cp tar archive from stdin", func() {
+		srcPath := filepath.Join(podmanTest.TempDir, "cp-stdin.txt")
+		err := ioutil.WriteFile(srcPath, []byte("from stdin"), 0644)
+		Expect(err).To(BeNil())
+		tarball := filepath.Join(podmanTest.TempDir, "cp-stdin.tar")
+		err = exec.Command("tar", "-cf", tarball, "-C", podmanTest.TempDir, "cp-stdin.txt").Run()
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"create", "--name", "cpstdin", ALPINE, "cat", "/tmp/cp-stdin.txt"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		tarFile, err := os.Open(tarball)
+		Expect(err).To(BeNil())
+		defer tarFile.Close()
+		cmd := exec.Command(podmanTest.PodmanBinary, podmanTest.makeOptions([]string{"cp", "-", "cpstdin:/tmp"})...)
+		cmd.Stdin = tarFile
+		err = cmd.Run()
+		Expect(err).To(BeNil())
+
+		session = podmanTest.Podman([]string{"start", "-a", "cpstdin"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("from stdin"))
+	})
+
+	It("podman cp into a volume of a running container with --pause", func() {
+		volDir := filepath.Join(podmanTest.TempDir, "cp-vol")
+		err := os.Mkdir(volDir, 0755)
+		Expect(err).To(BeNil())
+		srcPath := filepath.Join(podmanTest.TempDir, "cp-vol.txt")
+		err = ioutil.WriteFile(srcPath, []byte("in a volume"), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"run", "-d", "--name", "cpvol", "-v", fmt.Sprintf("%s:/data", volDir), ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"cp", "--pause", srcPath, "cpvol:/data/"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		content, err := ioutil.ReadFile(filepath.Join(volDir, "cp-vol.txt"))
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("in a volume"))
+
+		session = podmanTest.Podman([]string{"inspect", "--format", "{{.State.Status}}", "cpvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("running"))
+	})
+
+	It("podman cp without a container", func() {
+		session := podmanTest.Podman([]string{"cp", "/etc/hosts", "/tmp/hosts"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+})
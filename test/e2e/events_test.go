@@ -0,0 +1,117 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Podman events", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman events with a container filter", func() {
+		session := podmanTest.Podman([]string{"run", "--name", "eventsctr", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--filter", "container=eventsctr"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(result.LineInOutputContains("container create")).To(BeTrue())
+		Expect(result.LineInOutputContains("container start")).To(BeTrue())
+		Expect(result.LineInOutputContains("container died")).To(BeTrue())
+	})
+
+	It("podman events with an event filter", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "eventsfilter", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--filter", "container=eventsfilter", "--filter", "event=create"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(len(result.OutputToStringArray())).To(Equal(1))
+	})
+
+	It("podman events with a volume filter", func() {
+		session := podmanTest.Podman([]string{"volume", "create", "eventsvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--filter", "volume=eventsvol"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(result.LineInOutputContains("volume create")).To(BeTrue())
+	})
+
+	It("podman events with an until time in the past", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "eventsuntil", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--until", "1h", "--filter", "container=eventsuntil"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(len(result.OutputToStringArray())).To(Equal(0))
+	})
+
+	It("podman events with a bad filter", func() {
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--filter", "foo=bar"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman events --format json", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "eventsjson", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		cid := session.OutputToString()
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--format", "json", "--filter", "container=eventsjson"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		output := result.OutputToStringArray()
+		Expect(len(output)).To(Equal(1))
+		event := make(map[string]interface{})
+		Expect(json.Unmarshal([]byte(output[0]), &event)).To(BeNil())
+		Expect(event["ID"]).To(Equal(cid))
+		Expect(event["Status"]).To(Equal("create"))
+	})
+
+	It("podman events --format with a Go template", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "eventstemplate", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--format", "{{.Name}} {{.Status}}", "--filter", "container=eventstemplate"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(result.OutputToString()).To(Equal("eventstemplate create"))
+	})
+})
@@ -1,5 +1,3 @@
-// +build !remoteclient
-
 package integration
 
 import (
@@ -103,6 +101,28 @@ var _ = Describe("Podman exec", func() {
 		Expect(session.ExitCode()).To(Equal(100))
 	})
 
+	It("podman exec with terminal", func() {
+		setup := podmanTest.RunTopContainer("test1")
+		setup.WaitWithDefaultTimeout()
+		Expect(setup.ExitCode()).To(Equal(0))
+
+		session := podmanTest.Podman([]string{"exec", "-t", "test1", "sh", "-c", "tty; exit 3"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(3))
+		match, _ := session.GrepString("/dev/pts/")
+		Expect(match).Should(BeTrue())
+	})
+
+	It("podman exec into stopped container", func() {
+		setup := podmanTest.Podman([]string{"run", "--name", "test1", ALPINE, "ls"})
+		setup.WaitWithDefaultTimeout()
+		Expect(setup.ExitCode()).To(Equal(0))
+
+		session := podmanTest.Podman([]string{"exec", "test1", "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(125))
+	})
+
 	It("podman exec simple command with user", func() {
 		setup := podmanTest.RunTopContainer("test1")
 		setup.WaitWithDefaultTimeout()
@@ -4,12 +4,15 @@ package integration
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	. "github.com/containers/libpod/test/utils"
 	"github.com/ghodss/yaml"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/api/core/v1"
 )
 
 var _ = Describe("Podman generate kube", func() {
@@ -105,4 +108,84 @@ var _ = Describe("Podman generate kube", func() {
 		_, err := yaml.Marshal(kube.OutputToString())
 		Expect(err).To(BeNil())
 	})
+
+	It("podman generate kube with volumes, limits and restart policy", func() {
+		hostDir := filepath.Join(tempdir, "hostdir")
+		err := os.Mkdir(hostDir, 0755)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"run", "-dt", "--name", "kubectr", "--memory", "64m", "--cpus", "0.5", "--restart", "on-failure", "--label", "com.example/owner=my team", "-v", hostDir + ":/host:ro", "-v", "kubevol:/data", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		kube := podmanTest.Podman([]string{"generate", "kube", "kubectr"})
+		kube.WaitWithDefaultTimeout()
+		Expect(kube.ExitCode()).To(Equal(0))
+
+		pod := new(v1.Pod)
+		err = yaml.Unmarshal(kube.Out.Contents(), pod)
+		Expect(err).To(BeNil())
+		Expect(pod.Spec.RestartPolicy).To(Equal(v1.RestartPolicyOnFailure))
+		Expect(pod.Labels).To(HaveKeyWithValue("com.example/owner", "my-team"))
+
+		Expect(len(pod.Spec.Containers)).To(Equal(1))
+		ctr := pod.Spec.Containers[0]
+		Expect(ctr.Resources.Limits.Memory().String()).To(Equal("64Mi"))
+		Expect(ctr.Resources.Limits.Cpu().String()).To(Equal("500m"))
+
+		volumes := make(map[string]v1.Volume)
+		for _, volume := range pod.Spec.Volumes {
+			volumes[volume.Name] = volume
+		}
+		Expect(len(ctr.VolumeMounts)).To(Equal(2))
+		for _, mount := range ctr.VolumeMounts {
+			volume, ok := volumes[mount.Name]
+			Expect(ok).To(BeTrue())
+			switch mount.MountPath {
+			case "/host":
+				Expect(mount.ReadOnly).To(BeTrue())
+				Expect(volume.HostPath).ToNot(BeNil())
+				Expect(volume.HostPath.Path).To(Equal(hostDir))
+			case "/data":
+				Expect(volume.PersistentVolumeClaim).ToNot(BeNil())
+				Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal("kubevol"))
+			default:
+				Fail("unexpected volume mount " + mount.MountPath)
+			}
+		}
+	})
+
+	It("podman generate kube with output directory", func() {
+		_, rc, _ := podmanTest.CreatePod("toppod")
+		Expect(rc).To(Equal(0))
+
+		session := podmanTest.Podman([]string{"run", "-dt", "--pod", "toppod", "--name", "topcontainer", "-v", "kube_vol:/data", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		outputDir := filepath.Join(tempdir, "kube")
+		kube := podmanTest.Podman([]string{"generate", "kube", "-s", "--output-dir", outputDir, "toppod"})
+		kube.WaitWithDefaultTimeout()
+		Expect(kube.ExitCode()).To(Equal(0))
+		Expect(len(kube.OutputToStringArray())).To(Equal(3))
+
+		content, err := ioutil.ReadFile(filepath.Join(outputDir, "kube-vol-pvc.yaml"))
+		Expect(err).To(BeNil())
+		claim := new(v1.PersistentVolumeClaim)
+		err = yaml.Unmarshal(content, claim)
+		Expect(err).To(BeNil())
+		Expect(claim.Kind).To(Equal("PersistentVolumeClaim"))
+		Expect(claim.Name).To(Equal("kube-vol"))
+
+		content, err = ioutil.ReadFile(filepath.Join(outputDir, "toppod-pod.yaml"))
+		Expect(err).To(BeNil())
+		pod := new(v1.Pod)
+		err = yaml.Unmarshal(content, pod)
+		Expect(err).To(BeNil())
+		Expect(len(pod.Spec.Volumes)).To(Equal(1))
+		Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("kube-vol"))
+
+		_, err = os.Stat(filepath.Join(outputDir, "toppod-service.yaml"))
+		Expect(err).To(BeNil())
+	})
 })
@@ -0,0 +1,123 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Podman generate systemd", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman generate systemd on bogus object", func() {
+		session := podmanTest.Podman([]string{"generate", "systemd", "foobar"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman generate systemd with a bad restart policy", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "foobar", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"generate", "systemd", "--restart-policy", "bogus", "foobar"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman generate systemd on container", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "nginx", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"generate", "systemd", "--name", "--timeout", "42", "--restart-policy", "always", "nginx"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.LineInOutputContains("# container-nginx.service")).To(BeTrue())
+		Expect(session.LineInOutputContains("Restart=always")).To(BeTrue())
+		Expect(session.LineInOutputContains("stop -t 42 nginx")).To(BeTrue())
+		Expect(session.LineInOutputContains("conmon.pid")).To(BeTrue())
+	})
+
+	It("podman generate systemd on pod", func() {
+		session := podmanTest.Podman([]string{"pod", "create", "--name", "webapp"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"create", "--pod", "webapp", "--name", "webapp-web", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"generate", "systemd", "--name", "webapp"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.LineInOutputContains("# pod-webapp.service")).To(BeTrue())
+		Expect(session.LineInOutputContains("Wants=container-webapp-web.service")).To(BeTrue())
+		Expect(session.LineInOutputContains("# container-webapp-web.service")).To(BeTrue())
+		Expect(session.LineInOutputContains("Requires=pod-webapp.service")).To(BeTrue())
+		Expect(session.LineInOutputContains("After=pod-webapp.service")).To(BeTrue())
+	})
+
+	It("podman generate systemd on container in a pod", func() {
+		session := podmanTest.Podman([]string{"pod", "create", "--name", "webapp"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"create", "--pod", "webapp", "--name", "webapp-web", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"generate", "systemd", "webapp-web"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman generate systemd --files", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "filesctr", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		cwd, err := os.Getwd()
+		Expect(err).To(BeNil())
+		err = os.Chdir(podmanTest.TempDir)
+		Expect(err).To(BeNil())
+		defer os.Chdir(cwd)
+
+		session = podmanTest.Podman([]string{"generate", "systemd", "--name", "--files", "filesctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		unitFile := filepath.Join(podmanTest.TempDir, "container-filesctr.service")
+		Expect(session.OutputToString()).To(Equal(unitFile))
+		content, err := ioutil.ReadFile(unitFile)
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(ContainSubstring("ExecStart="))
+	})
+})
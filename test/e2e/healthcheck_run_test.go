@@ -0,0 +1,141 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Podman healthcheck run", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman healthcheck run bogus container", func() {
+		session := podmanTest.Podman([]string{"healthcheck", "run", "foobar"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman healthcheck run on container without healthcheck", func() {
+		session := podmanTest.Podman([]string{"run", "-dt", "--name", "hc", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		hc := podmanTest.Podman([]string{"healthcheck", "run", "hc"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc.ExitCode()).To(Equal(125))
+	})
+
+	It("podman healthcheck run on stopped container", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "hc", "--health-cmd", "ls", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		hc := podmanTest.Podman([]string{"healthcheck", "run", "hc"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc.ExitCode()).To(Equal(125))
+	})
+
+	It("podman healthcheck run healthy container", func() {
+		session := podmanTest.Podman([]string{"run", "-dt", "--name", "hc", "--health-cmd", "ls /", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.State.Healthcheck.Status}}", "hc"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.OutputToString()).To(Equal("starting"))
+
+		hc := podmanTest.Podman([]string{"healthcheck", "run", "hc"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc.ExitCode()).To(Equal(0))
+		Expect(hc.OutputToString()).To(Equal("healthy"))
+
+		inspect = podmanTest.Podman([]string{"inspect", "--format", "{{.State.Healthcheck.Status}} {{len .State.Healthcheck.Log}}", "hc"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.OutputToString()).To(Equal("healthy 1"))
+
+		ps := podmanTest.Podman([]string{"ps", "-q", "--filter", "health=healthy"})
+		ps.WaitWithDefaultTimeout()
+		Expect(ps.ExitCode()).To(Equal(0))
+		Expect(len(ps.OutputToStringArray())).To(Equal(1))
+
+		ps = podmanTest.Podman([]string{"ps", "--format", "{{.Status}}"})
+		ps.WaitWithDefaultTimeout()
+		Expect(ps.OutputToString()).To(ContainSubstring("(healthy)"))
+	})
+
+	It("podman healthcheck run unhealthy container after retries", func() {
+		session := podmanTest.Podman([]string{"run", "-dt", "--name", "hc", "--health-cmd", "ls /foo", "--health-retries", "2", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		hc := podmanTest.Podman([]string{"healthcheck", "run", "hc"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc.ExitCode()).To(Equal(1))
+
+		inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.State.Healthcheck.Status}} {{.State.Healthcheck.FailingStreak}}", "hc"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.OutputToString()).To(Equal("starting 1"))
+
+		hc = podmanTest.Podman([]string{"healthcheck", "run", "hc"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc.ExitCode()).To(Equal(1))
+
+		inspect = podmanTest.Podman([]string{"inspect", "--format", "{{.State.Healthcheck.Status}} {{.State.Healthcheck.FailingStreak}}", "hc"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.OutputToString()).To(Equal("unhealthy 2"))
+
+		ps := podmanTest.Podman([]string{"ps", "-q", "--filter", "health=unhealthy"})
+		ps.WaitWithDefaultTimeout()
+		Expect(len(ps.OutputToStringArray())).To(Equal(1))
+	})
+
+	It("podman healthcheck run kills a hanging healthcheck after its timeout", func() {
+		session := podmanTest.Podman([]string{"run", "-dt", "--name", "hc", "--health-cmd", "sleep 600", "--health-timeout", "2s", "--health-retries", "1", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		hc := podmanTest.Podman([]string{"healthcheck", "run", "hc"})
+		hc.Wait(30)
+		Expect(hc.ExitCode()).To(Equal(1))
+
+		inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.State.Healthcheck.Status}} {{(index .State.Healthcheck.Log 0).Output}}", "hc"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.OutputToString()).To(Equal("unhealthy healthcheck command exceeded timeout of 2s"))
+
+		top := podmanTest.Podman([]string{"top", "hc"})
+		top.WaitWithDefaultTimeout()
+		Expect(top.ExitCode()).To(Equal(0))
+		Expect(top.OutputToString()).To(Not(ContainSubstring("sleep")))
+	})
+
+	It("podman run --no-healthcheck conflicts with --health-cmd", func() {
+		session := podmanTest.Podman([]string{"run", "--no-healthcheck", "--health-cmd", "ls", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+})
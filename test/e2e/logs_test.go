@@ -106,4 +106,42 @@ var _ = Describe("Podman logs", func() {
 		Expect(results.ExitCode()).To(Equal(0))
 		Expect(len(results.OutputToStringArray())).To(Equal(3))
 	})
+
+	It("podman logs with log-driver none", func() {
+		logc := podmanTest.Podman([]string{"run", "--log-driver", "none", ALPINE, "sh", "-c", "echo podman"})
+		logc.WaitWithDefaultTimeout()
+		Expect(logc.ExitCode()).To(Equal(0))
+
+		results := podmanTest.Podman([]string{"logs", "-l"})
+		results.WaitWithDefaultTimeout()
+		Expect(results.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman create with an invalid log-driver", func() {
+		session := podmanTest.Podman([]string{"create", "--log-driver", "foobar", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman inspect shows the log-driver", func() {
+		session := podmanTest.Podman([]string{"create", "--log-driver", "journald", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		cid := session.OutputToString()
+
+		inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.HostConfig.LogConfig.Type}}", cid})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("journald"))
+
+		session = podmanTest.Podman([]string{"create", "--log-driver", "json-file", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		cid = session.OutputToString()
+
+		inspect = podmanTest.Podman([]string{"inspect", "--format", "{{.HostConfig.LogConfig.Type}}", cid})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("k8s-file"))
+	})
 })
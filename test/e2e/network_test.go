@@ -0,0 +1,275 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Podman network", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		// Networks are configured outside of the test directories
+		for _, name := range []string{"podmantestnet1", "podmantestnet2"} {
+			session := podmanTest.Podman([]string{"network", "rm", "--force", name})
+			session.WaitWithDefaultTimeout()
+		}
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman network create, ls, inspect and rm", func() {
+		session := podmanTest.Podman([]string{"network", "create", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("podmantestnet1"))
+		_, err := os.Stat(filepath.Join(podmanTest.CNIConfigDir, "podmantestnet1.conflist"))
+		Expect(err).To(BeNil())
+
+		session = podmanTest.Podman([]string{"network", "ls", "-q"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(ContainElement("podmantestnet1"))
+
+		session = podmanTest.Podman([]string{"network", "ls", "--format", "json"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.IsJSONOutputValid()).To(BeTrue())
+
+		session = podmanTest.Podman([]string{"network", "inspect", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.IsJSONOutputValid()).To(BeTrue())
+
+		session = podmanTest.Podman([]string{"network", "inspect", "--format", "{{.name}}", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("podmantestnet1"))
+
+		session = podmanTest.Podman([]string{"network", "create", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"network", "rm", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"network", "ls", "-q"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(Not(ContainElement("podmantestnet1")))
+
+		session = podmanTest.Podman([]string{"network", "inspect", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman network create with subnet and labels", func() {
+		session := podmanTest.Podman([]string{"network", "create", "--subnet", "10.252.0.0/24", "--ip-range", "10.252.0.128/25", "--label", "env=test", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"network", "ls", "--format", "{{.Name}} {{.Subnets}} {{.Labels}}"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(ContainElement("podmantestnet1 10.252.0.0/24 env=test"))
+
+		session = podmanTest.Podman([]string{"network", "create", "--subnet", "10.252.0.0/16", "podmantestnet2"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"network", "create", "--subnet", "10.253.0.0/24", "--gateway", "10.254.0.1", "podmantestnet2"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"network", "create", "--driver", "overlay", "podmantestnet2"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"run", "--rm", "--network", "podmantestnet1", ALPINE, "ip", "addr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(ContainSubstring("inet 10.252.0.128/24"))
+	})
+
+	It("podman network rm of a network in use", func() {
+		session := podmanTest.Podman([]string{"network", "create", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"create", "--name", "netctr", "--network", "podmantestnet1", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"network", "rm", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+		Expect(podmanTest.NumberOfContainers()).To(Equal(1))
+
+		session = podmanTest.Podman([]string{"network", "rm", "--force", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(podmanTest.NumberOfContainers()).To(Equal(0))
+	})
+
+	It("podman network connect and disconnect a running container", func() {
+		session := podmanTest.Podman([]string{"network", "create", "--subnet", "10.252.1.0/24", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.RunTopContainer("netctr")
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"network", "connect", "--ip", "10.252.1.100", "podmantestnet1", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"exec", "netctr", "ip", "addr", "show", "eth1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(ContainSubstring("inet 10.252.1.100/24"))
+
+		session = podmanTest.Podman([]string{"inspect", "--format", "{{.NetworkSettings.Networks.podmantestnet1.IPAddress}} {{.NetworkSettings.Networks.podmantestnet1.InterfaceName}}", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("10.252.1.100 eth1"))
+
+		// The requested IP is kept when the container is restarted
+		session = podmanTest.Podman([]string{"restart", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"exec", "netctr", "ip", "addr", "show", "eth1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(ContainSubstring("inet 10.252.1.100/24"))
+
+		session = podmanTest.Podman([]string{"network", "connect", "podmantestnet1", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"network", "rm", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"network", "disconnect", "podmantestnet1", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"exec", "netctr", "ip", "addr", "show", "eth1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"exec", "netctr", "ip", "addr", "show", "eth0"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"network", "rm", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+	})
+
+	It("podman network connect is kept when the container is restarted", func() {
+		session := podmanTest.Podman([]string{"network", "create", "--subnet", "10.252.2.0/24", "podmantestnet1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"create", "--name", "netctr", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"network", "connect", "--ip", "10.252.2.100", "podmantestnet1", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"network", "connect", "podmantestnet1", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"start", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"exec", "netctr", "ip", "addr", "show", "eth1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(ContainSubstring("inet 10.252.2."))
+
+		session = podmanTest.Podman([]string{"network", "disconnect", "podmantestnet1", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"inspect", "--format", "{{len .NetworkSettings.Networks}}", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("1"))
+
+		session = podmanTest.Podman([]string{"network", "disconnect", "podmantestnet1", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman network connect after disconnecting a network releases addresses on stop", func() {
+		for i, name := range []string{"podmantestnet1", "podmantestnet2"} {
+			session := podmanTest.Podman([]string{"network", "create", "--subnet", fmt.Sprintf("10.252.%d.0/24", i+3), name})
+			session.WaitWithDefaultTimeout()
+			Expect(session.ExitCode()).To(Equal(0))
+		}
+
+		session := podmanTest.RunTopContainer("netctr")
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		for _, args := range [][]string{
+			{"connect", "podmantestnet1", "netctr"},
+			{"connect", "podmantestnet2", "netctr"},
+			{"disconnect", "podmantestnet1", "netctr"},
+			{"connect", "podmantestnet1", "netctr"},
+		} {
+			session = podmanTest.Podman(append([]string{"network"}, args...))
+			session.WaitWithDefaultTimeout()
+			Expect(session.ExitCode()).To(Equal(0))
+		}
+
+		session = podmanTest.Podman([]string{"inspect", "--format", "{{.NetworkSettings.Networks.podmantestnet1.InterfaceName}} {{.NetworkSettings.Networks.podmantestnet2.InterfaceName}}", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToString()).To(Equal("eth1 eth2"))
+
+		session = podmanTest.Podman([]string{"stop", "netctr"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		// host-local keeps a file named after every allocated address
+		for i, name := range []string{"podmantestnet1", "podmantestnet2"} {
+			files, _ := ioutil.ReadDir(filepath.Join("/var/lib/cni/networks", name))
+			for _, f := range files {
+				Expect(f.Name()).To(Not(HavePrefix(fmt.Sprintf("10.252.%d.", i+3))))
+			}
+		}
+	})
+})
@@ -0,0 +1,466 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var playKubeVolumesYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: volumepod
+spec:
+  containers:
+  - name: volumectr
+    image: %s
+    command: ["top"]
+    volumeMounts:
+    - name: host
+      mountPath: /host
+      readOnly: true
+    - name: scratch
+      mountPath: /scratch
+    - name: claim
+      mountPath: /claim
+  volumes:
+  - name: host
+    hostPath:
+      path: %s
+      type: Directory
+  - name: scratch
+    emptyDir: {}
+  - name: claim
+    persistentVolumeClaim:
+      claimName: playclaim
+`
+
+var playKubeUndefinedVolumeYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: volumepod
+spec:
+  containers:
+  - name: volumectr
+    image: %s
+    command: ["top"]
+    volumeMounts:
+    - name: missing
+      mountPath: /missing
+`
+
+var playKubeConfigMapYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: configpod
+spec:
+  containers:
+  - name: configctr
+    image: %s
+    command: ["top"]
+    env:
+    - name: GREETING
+      valueFrom:
+        configMapKeyRef:
+          name: playconfig
+          key: greeting
+    envFrom:
+    - configMapRef:
+        name: playconfig
+      prefix: CFG_
+    volumeMounts:
+    - name: config
+      mountPath: /config
+  volumes:
+  - name: config
+    configMap:
+      name: playconfig
+      items:
+      - key: greeting
+        path: sub/greeting.txt
+`
+
+var playKubeConfigMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: playconfig
+data:
+  greeting: hello
+  color: blue
+`
+
+var playKubeMultiDocYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: deployconfig
+data:
+  greeting: hello
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: deploy
+  template:
+    metadata:
+      labels:
+        app: deploy
+    spec:
+      containers:
+      - name: ctr
+        image: %s
+        command: ["top"]
+        envFrom:
+        - configMapRef:
+            name: deployconfig
+        volumeMounts:
+        - name: scratch
+          mountPath: /scratch
+      volumes:
+      - name: scratch
+        emptyDir: {}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: deploysvc
+spec:
+  type: NodePort
+  selector:
+    app: deploy
+  ports:
+  - port: 80
+    nodePort: 30080
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: singlepod
+spec:
+  containers:
+  - name: singlectr
+    image: %s
+    command: ["top"]
+`
+
+var playKubeReplicasHostPortYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: portdeploy
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: portdeploy
+  template:
+    metadata:
+      labels:
+        app: portdeploy
+    spec:
+      containers:
+      - name: ctr
+        image: %s
+        command: ["top"]
+        ports:
+        - containerPort: 80
+          hostPort: 8080
+`
+
+var playKubeLaterPodFailsYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: firstpod
+spec:
+  containers:
+  - name: firstctr
+    image: %s
+    command: ["top"]
+    volumeMounts:
+    - name: firstclaim
+      mountPath: /claim
+  volumes:
+  - name: firstclaim
+    persistentVolumeClaim:
+      claimName: firstclaim
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: failingpod
+spec:
+  containers:
+  - name: failingctr
+    image: %s
+    command: ["top"]
+    volumeMounts:
+    - name: undefined
+      mountPath: /undefined
+`
+
+var _ = Describe("Podman play kube", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+
+	})
+
+	It("podman play kube with volumes", func() {
+		hostDir := filepath.Join(tempdir, "hostdir")
+		err := os.Mkdir(hostDir, 0755)
+		Expect(err).To(BeNil())
+		err = ioutil.WriteFile(filepath.Join(hostDir, "testfile"), []byte("fromhost"), 0644)
+		Expect(err).To(BeNil())
+
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err = ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeVolumesYAML, ALPINE, hostDir)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		exec := podmanTest.Podman([]string{"exec", "volumectr", "cat", "/host/testfile"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(Equal("fromhost"))
+
+		exec = podmanTest.Podman([]string{"exec", "volumectr", "touch", "/host/newfile"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Not(Equal(0)))
+
+		exec = podmanTest.Podman([]string{"exec", "volumectr", "touch", "/scratch/newfile", "/claim/newfile"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+
+		volumes := podmanTest.Podman([]string{"volume", "ls", "-q"})
+		volumes.WaitWithDefaultTimeout()
+		Expect(volumes.ExitCode()).To(Equal(0))
+		Expect(volumes.OutputToStringArray()).To(ContainElement("playclaim"))
+		Expect(len(volumes.OutputToStringArray())).To(Equal(2))
+	})
+
+	It("podman pod rm removes the emptyDir volumes of play kube", func() {
+		hostDir := filepath.Join(tempdir, "hostdir")
+		err := os.Mkdir(hostDir, 0755)
+		Expect(err).To(BeNil())
+
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err = ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeVolumesYAML, ALPINE, hostDir)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		rm := podmanTest.Podman([]string{"pod", "rm", "-f", "volumepod"})
+		rm.WaitWithDefaultTimeout()
+		Expect(rm.ExitCode()).To(Equal(0))
+
+		volumes := podmanTest.Podman([]string{"volume", "ls", "-q"})
+		volumes.WaitWithDefaultTimeout()
+		Expect(volumes.ExitCode()).To(Equal(0))
+		Expect(volumes.OutputToStringArray()).To(ConsistOf("playclaim"))
+	})
+
+	It("podman play kube with undefined volume", func() {
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err := ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeUndefinedVolumeYAML, ALPINE)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman play kube with configmap", func() {
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err := ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeConfigMapYAML, ALPINE)), 0644)
+		Expect(err).To(BeNil())
+		configMapFile := filepath.Join(tempdir, "configmap.yaml")
+		err = ioutil.WriteFile(configMapFile, []byte(playKubeConfigMap), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", "--configmap", configMapFile, kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		exec := podmanTest.Podman([]string{"exec", "configctr", "printenv", "GREETING"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(Equal("hello"))
+
+		exec = podmanTest.Podman([]string{"exec", "configctr", "printenv", "CFG_color"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(Equal("blue"))
+
+		exec = podmanTest.Podman([]string{"exec", "configctr", "cat", "/config/sub/greeting.txt"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(Equal("hello"))
+	})
+
+	It("podman play kube without required configmap", func() {
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err := ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeConfigMapYAML, ALPINE)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman play kube multiple documents and down", func() {
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err := ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeMultiDocYAML, ALPINE, ALPINE)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		pods := podmanTest.Podman([]string{"pod", "ps", "--format", "{{.Name}}"})
+		pods.WaitWithDefaultTimeout()
+		Expect(pods.ExitCode()).To(Equal(0))
+		Expect(pods.OutputToStringArray()).To(ConsistOf("deploy-pod-0", "deploy-pod-1", "singlepod"))
+
+		exec := podmanTest.Podman([]string{"exec", "deploy-pod-1-ctr", "printenv", "greeting"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(Equal("hello"))
+
+		volumes := podmanTest.Podman([]string{"volume", "ls", "-q"})
+		volumes.WaitWithDefaultTimeout()
+		Expect(volumes.ExitCode()).To(Equal(0))
+		Expect(len(volumes.OutputToStringArray())).To(Equal(2))
+
+		down := podmanTest.Podman([]string{"play", "kube", "--down", kubeFile})
+		down.WaitWithDefaultTimeout()
+		Expect(down.ExitCode()).To(Equal(0))
+
+		pods = podmanTest.Podman([]string{"pod", "ps", "-q"})
+		pods.WaitWithDefaultTimeout()
+		Expect(pods.ExitCode()).To(Equal(0))
+		Expect(len(pods.OutputToStringArray())).To(Equal(0))
+
+		volumes = podmanTest.Podman([]string{"volume", "ls", "-q"})
+		volumes.WaitWithDefaultTimeout()
+		Expect(volumes.ExitCode()).To(Equal(0))
+		Expect(len(volumes.OutputToStringArray())).To(Equal(0))
+	})
+
+	It("podman play kube down keeps volumes used by other containers", func() {
+		hostDir := filepath.Join(tempdir, "hostdir")
+		err := os.Mkdir(hostDir, 0755)
+		Expect(err).To(BeNil())
+
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err = ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeVolumesYAML, ALPINE, hostDir)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"create", "-v", "playclaim:/claim", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		down := podmanTest.Podman([]string{"play", "kube", "--down", kubeFile})
+		down.WaitWithDefaultTimeout()
+		Expect(down.ExitCode()).To(Equal(0))
+
+		volumes := podmanTest.Podman([]string{"volume", "ls", "-q"})
+		volumes.WaitWithDefaultTimeout()
+		Expect(volumes.ExitCode()).To(Equal(0))
+		Expect(volumes.OutputToStringArray()).To(ConsistOf("playclaim"))
+	})
+
+	It("podman play kube down keeps other pods", func() {
+		_, ec, _ := podmanTest.CreatePod("otherpod")
+		Expect(ec).To(Equal(0))
+
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err := ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeUndefinedVolumeYAML, ALPINE)), 0644)
+		Expect(err).To(BeNil())
+
+		down := podmanTest.Podman([]string{"play", "kube", "--down", kubeFile})
+		down.WaitWithDefaultTimeout()
+		Expect(down.ExitCode()).To(Equal(0))
+
+		pods := podmanTest.Podman([]string{"pod", "ps", "--format", "{{.Name}}"})
+		pods.WaitWithDefaultTimeout()
+		Expect(pods.ExitCode()).To(Equal(0))
+		Expect(pods.OutputToStringArray()).To(ConsistOf("otherpod"))
+	})
+
+	It("podman play kube rejects host ports with several replicas", func() {
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err := ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeReplicasHostPortYAML, ALPINE)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		pods := podmanTest.Podman([]string{"pod", "ps", "-q"})
+		pods.WaitWithDefaultTimeout()
+		Expect(pods.ExitCode()).To(Equal(0))
+		Expect(len(pods.OutputToStringArray())).To(Equal(0))
+	})
+
+	It("podman play kube removes created pods when a later pod fails", func() {
+		kubeFile := filepath.Join(tempdir, "kube.yaml")
+		err := ioutil.WriteFile(kubeFile, []byte(fmt.Sprintf(playKubeLaterPodFailsYAML, ALPINE, ALPINE)), 0644)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"play", "kube", kubeFile})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		pods := podmanTest.Podman([]string{"pod", "ps", "-q"})
+		pods.WaitWithDefaultTimeout()
+		Expect(pods.ExitCode()).To(Equal(0))
+		Expect(len(pods.OutputToStringArray())).To(Equal(0))
+
+		volumes := podmanTest.Podman([]string{"volume", "ls", "-q"})
+		volumes.WaitWithDefaultTimeout()
+		Expect(volumes.ExitCode()).To(Equal(0))
+		Expect(len(volumes.OutputToStringArray())).To(Equal(0))
+	})
+})
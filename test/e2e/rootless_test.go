@@ -5,12 +5,15 @@ package integration
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	. "github.com/containers/libpod/test/utils"
 	. "github.com/onsi/ginkgo"
@@ -30,6 +33,28 @@ func canExec() bool {
 	return errno != syscall.ENOTTY
 }
 
+// proxySignatureHex is the signature of version 2 PROXY protocol headers, in
+// the hexadecimal form od prints
+const proxySignatureHex = "0d0a0d0a000d0a515549540a"
+
+// sendToLocalPort sends message to port on the loopback address over network,
+// and returns the port it was sent from
+func sendToLocalPort(network string, port int, message string) (int, error) {
+	conn, err := net.DialTimeout(network, fmt.Sprintf("127.0.0.1:%d", port), 5*time.Second)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(message)); err != nil {
+		return 0, err
+	}
+	_, localPort, err := net.SplitHostPort(conn.LocalAddr().String())
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(localPort)
+}
+
 var _ = Describe("Podman rootless", func() {
 	var (
 		tempdir    string
@@ -163,6 +188,31 @@ var _ = Describe("Podman rootless", func() {
 		Expect(cmd.ExitCode()).To(Equal(0))
 	})
 
+	It("podman rootless volume create with mount options", func() {
+		xdgRuntimeDir, err := ioutil.TempDir("/run", "")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(xdgRuntimeDir)
+		err = filepath.Walk(xdgRuntimeDir, chownFunc)
+		Expect(err).To(BeNil())
+
+		home, err := CreateTempDirInTempDir()
+		Expect(err).To(BeNil())
+		err = filepath.Walk(home, chownFunc)
+		Expect(err).To(BeNil())
+
+		env := os.Environ()
+		env = append(env, fmt.Sprintf("XDG_RUNTIME_DIR=%s", xdgRuntimeDir))
+		env = append(env, fmt.Sprintf("HOME=%s", home))
+		env = append(env, "USER=foo")
+		cmd := podmanTest.PodmanAsUser([]string{"volume", "create", "-o", "type=tmpfs", "tmpfsvol"}, 1000, 1000, env)
+		cmd.WaitWithDefaultTimeout()
+		Expect(cmd.ExitCode()).To(Not(Equal(0)))
+
+		cmd = podmanTest.PodmanAsUser([]string{"volume", "create", "plainvol"}, 1000, 1000, env)
+		cmd.WaitWithDefaultTimeout()
+		Expect(cmd.ExitCode()).To(Equal(0))
+	})
+
 	runRootlessHelper := func(args []string) {
 		f := func(rootlessTest *PodmanTestIntegration, xdgRuntimeDir string, home string, mountPath string) {
 			runtime.LockOSThread()
@@ -295,4 +345,126 @@ var _ = Describe("Podman rootless", func() {
 	It("podman rootless rootfs --ipc host", func() {
 		runRootlessHelper([]string{"--ipc", "host"})
 	})
+
+	// runRootlessPortForwarder runs a rootless container publishing ports
+	// with the rootlessport forwarder, and calls check while it runs
+	runRootlessPortForwarder := func(options []string, command []string, check func(rootlessTest *PodmanTestIntegration, env []string)) {
+		f := func(rootlessTest *PodmanTestIntegration, xdgRuntimeDir string, home string, mountPath string) {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			env := os.Environ()
+			env = append(env, fmt.Sprintf("XDG_RUNTIME_DIR=%s", xdgRuntimeDir))
+			env = append(env, fmt.Sprintf("HOME=%s", home))
+			env = append(env, "PODMAN_ALLOW_SINGLE_ID_MAPPING_IN_USERNS=1")
+			env = append(env, "USER=foo")
+
+			allArgs := append([]string{"run", "-d", "--port-forwarder", "rootlessport"}, options...)
+			allArgs = append(allArgs, "--security-opt", "seccomp=unconfined", "--rootfs", mountPath)
+			allArgs = append(allArgs, command...)
+			cmd := rootlessTest.PodmanAsUser(allArgs, 1000, 1000, env)
+			cmd.WaitWithDefaultTimeout()
+			Expect(cmd.ExitCode()).To(Equal(0))
+
+			check(rootlessTest, env)
+
+			cmd = rootlessTest.PodmanAsUser([]string{"rm", "-l", "-f"}, 1000, 1000, env)
+			cmd.WaitWithDefaultTimeout()
+			Expect(cmd.ExitCode()).To(Equal(0))
+		}
+		runInRootlessContext(f)
+	}
+
+	rootlessLogs := func(rootlessTest *PodmanTestIntegration, env []string) string {
+		cmd := rootlessTest.PodmanAsUser([]string{"logs", "-l"}, 1000, 1000, env)
+		cmd.WaitWithDefaultTimeout()
+		Expect(cmd.ExitCode()).To(Equal(0))
+		return cmd.OutputToString()
+	}
+
+	It("podman rootless rootlessport forwards TCP", func() {
+		runRootlessPortForwarder([]string{"-p", "127.0.0.1:18080:8080"}, []string{"nc", "-l", "-p", "8080"}, func(rootlessTest *PodmanTestIntegration, env []string) {
+			// The message is lost if it is sent before nc listens
+			Eventually(func() string {
+				sendToLocalPort("tcp", 18080, "tcpmessage")
+				return rootlessLogs(rootlessTest, env)
+			}, 20).Should(ContainSubstring("tcpmessage"))
+		})
+	})
+
+	It("podman rootless rootlessport forwards UDP", func() {
+		runRootlessPortForwarder([]string{"-p", "127.0.0.1:18081:8080/udp"}, []string{"nc", "-u", "-l", "-p", "8080"}, func(rootlessTest *PodmanTestIntegration, env []string) {
+			Eventually(func() string {
+				sendToLocalPort("udp", 18081, "udpmessage")
+				return rootlessLogs(rootlessTest, env)
+			}, 20).Should(ContainSubstring("udpmessage"))
+		})
+	})
+
+	It("podman rootless rootlessport forwards port ranges", func() {
+		runRootlessPortForwarder([]string{"-p", "127.0.0.1:18100-18102:8000-8002"}, []string{"sh", "-c", "nc -l -p 8000 & nc -l -p 8001 & nc -l -p 8002; wait"}, func(rootlessTest *PodmanTestIntegration, env []string) {
+			for i := 0; i < 3; i++ {
+				message := fmt.Sprintf("rangemessage%d", 8000+i)
+				Eventually(func() string {
+					sendToLocalPort("tcp", 18100+i, message)
+					return rootlessLogs(rootlessTest, env)
+				}, 20).Should(ContainSubstring(message))
+			}
+		})
+	})
+
+	It("podman rootless rootlessport with --port-peer-address proxy-protocol", func() {
+		command := []string{"sh", "-c", "nc -l -p 8080 | od -An -tx1 -v | tr -d ' \\n'"}
+		runRootlessPortForwarder([]string{"-p", "127.0.0.1:18082:8080", "--port-peer-address", "proxy-protocol"}, command, func(rootlessTest *PodmanTestIntegration, env []string) {
+			var sentPorts []int
+			Eventually(func() string {
+				if port, err := sendToLocalPort("tcp", 18082, "proxymessage"); err == nil {
+					sentPorts = append(sentPorts, port)
+				}
+				return rootlessLogs(rootlessTest, env)
+			}, 20).Should(ContainSubstring(fmt.Sprintf("%x", "proxymessage")))
+
+			// The header describes a TCP connection over IPv4 from the
+			// port the message was sent from on 127.0.0.1
+			output := rootlessLogs(rootlessTest, env)
+			start := strings.Index(output, proxySignatureHex)
+			Expect(start).To(Not(Equal(-1)))
+			header := output[start+len(proxySignatureHex):]
+			Expect(len(header)).To(BeNumerically(">=", 32))
+			Expect(header[:8]).To(Equal("2111000c"))
+			Expect(header[8:16]).To(Equal("7f000001"))
+			peerPort, err := strconv.ParseInt(header[24:28], 16, 32)
+			Expect(err).To(BeNil())
+			Expect(sentPorts).To(ContainElement(int(peerPort)))
+			Expect(header[32:]).To(HavePrefix(fmt.Sprintf("%x", "proxymessage")))
+		})
+	})
+
+	It("podman rootless rootlessport with --port-peer-address socket", func() {
+		if !canExec() {
+			Skip("ioctl(NS_GET_PARENT) not supported.")
+		}
+		runRootlessPortForwarder([]string{"-p", "127.0.0.1:18083:8080", "--port-peer-address", "socket"}, []string{"nc", "-l", "-p", "8080"}, func(rootlessTest *PodmanTestIntegration, env []string) {
+			netstat := func(options string) string {
+				cmd := rootlessTest.PodmanAsUser([]string{"exec", "-l", "netstat", options}, 1000, 1000, env)
+				cmd.WaitWithDefaultTimeout()
+				return cmd.OutputToString()
+			}
+			// nc accepts a single connection, so connect once it listens
+			Eventually(func() string {
+				return netstat("-ltn")
+			}, 20).Should(ContainSubstring(":8080 "))
+
+			conn, err := net.DialTimeout("tcp", "127.0.0.1:18083", 5*time.Second)
+			Expect(err).To(BeNil())
+			defer conn.Close()
+			_, peerPort, err := net.SplitHostPort(conn.LocalAddr().String())
+			Expect(err).To(BeNil())
+
+			// The connection in the container originates from the
+			// address and port of the client
+			Eventually(func() string {
+				return netstat("-tn")
+			}, 20).Should(ContainSubstring(fmt.Sprintf("127.0.0.1:%s ", peerPort)))
+		})
+	})
 })
@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Podman run and start attached", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+
+	})
+
+	It("podman run attached returns output and exit code", func() {
+		session := podmanTest.Podman([]string{"run", ALPINE, "sh", "-c", "echo hello; echo world >&2; exit 3"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(3))
+		Expect(session.OutputToString()).To(Equal("hello"))
+		Expect(session.ErrorToString()).To(ContainSubstring("world"))
+	})
+
+	It("podman run --rm removes the container", func() {
+		session := podmanTest.Podman([]string{"run", "--rm", "--name", "test1", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		inspect := podmanTest.Podman([]string{"inspect", "test1"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman start -a returns output", func() {
+		session := podmanTest.Podman([]string{"run", "--name", "test1", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		start := podmanTest.Podman([]string{"start", "-a", "test1"})
+		start.WaitWithDefaultTimeout()
+		Expect(start.ExitCode()).To(Equal(0))
+		Expect(start.OutputToString()).To(ContainSubstring("etc"))
+	})
+
+	It("podman attach to bogus container", func() {
+		session := podmanTest.Podman([]string{"attach", "foobar"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(125))
+	})
+})
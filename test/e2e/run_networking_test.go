@@ -102,6 +102,30 @@ var _ = Describe("Podman run networking", func() {
 		Expect(containerConfig[0].NetworkSettings.Ports[0].HostPort).ToNot(Equal("80"))
 	})
 
+	It("podman run port peer address requires the rootlessport forwarder", func() {
+		session := podmanTest.Podman([]string{"create", "--network", "slirp4netns", "-p", "8080:80", "--port-peer-address", "proxy-protocol", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"create", "--network", "slirp4netns", "-p", "8080:80", "--port-forwarder", "rootlessport", "--port-peer-address", "transparent", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"create", "--network", "slirp4netns", "-p", "8080:80", "--port-forwarder", "rootlessport", "--port-peer-address", "proxy-protocol", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+	})
+
+	It("podman run port forwarder requires slirp4netns", func() {
+		session := podmanTest.Podman([]string{"create", "-p", "8080:80", "--port-forwarder", "rootlessport", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"create", "-p", "8080:80", "--port-peer-address", "none", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
 	It("podman run hostname test", func() {
 		session := podmanTest.Podman([]string{"run", "--rm", ALPINE, "printenv", "HOSTNAME"})
 		session.WaitWithDefaultTimeout()
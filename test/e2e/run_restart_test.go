@@ -5,6 +5,7 @@ package integration
 import (
 	"fmt"
 	"os"
+	"time"
 
 	. "github.com/containers/libpod/test/utils"
 	. "github.com/onsi/ginkgo"
@@ -57,4 +58,48 @@ var _ = Describe("Podman run restart containers", func() {
 		session2.WaitWithDefaultTimeout()
 		Expect(session2.ExitCode()).To(Equal(0))
 	})
+
+	It("Podman run with restart policy on-failure restarts failing container", func() {
+		session := podmanTest.Podman([]string{"run", "-d", "--name", "test1", "--restart", "on-failure:2", ALPINE, "false"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		// The container is restarted by the cleanup process, so poll
+		// until the retries are exhausted
+		restartCount := ""
+		for i := 0; i < 30; i++ {
+			inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.RestartCount}} {{.State.Status}}", "test1"})
+			inspect.WaitWithDefaultTimeout()
+			Expect(inspect.ExitCode()).To(Equal(0))
+			restartCount = inspect.OutputToString()
+			if restartCount == "2 exited" {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+		Expect(restartCount).To(Equal("2 exited"))
+	})
+
+	It("Podman run with restart policy on-failure does not restart successful container", func() {
+		session := podmanTest.Podman([]string{"run", "--name", "test1", "--restart", "on-failure", ALPINE, "true"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.RestartCount}} {{.HostConfig.RestartPolicy.Name}}", "test1"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("0 on-failure"))
+	})
+
+	It("Podman run with invalid restart policy fails", func() {
+		session := podmanTest.Podman([]string{"run", "--restart", "sometimes", ALPINE, "true"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("Podman run with --rm and --restart fails", func() {
+		session := podmanTest.Podman([]string{"run", "--rm", "--restart", "always", ALPINE, "true"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
 })
@@ -4,7 +4,9 @@ package integration
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	. "github.com/containers/libpod/test/utils"
 	. "github.com/onsi/ginkgo"
@@ -69,4 +71,84 @@ var _ = Describe("Podman run with --ip flag", func() {
 		result.WaitWithDefaultTimeout()
 		Expect(result.ExitCode()).ToNot(Equal(0))
 	})
+
+	It("Podman run --ip for a network the container does not join", func() {
+		result := podmanTest.Podman([]string{"run", "-ti", "--ip", "nonexistent:10.88.64.128", ALPINE, "ls"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).ToNot(Equal(0))
+	})
+
+	It("Podman run --ip6 with v4 address", func() {
+		result := podmanTest.Podman([]string{"run", "-ti", "--ip6", "10.88.64.128", ALPINE, "ls"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).ToNot(Equal(0))
+	})
+
+	It("Podman run --mac-address with garbage address", func() {
+		result := podmanTest.Podman([]string{"run", "-ti", "--mac-address", "92:d0:c6:0a:29", ALPINE, "ls"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).ToNot(Equal(0))
+	})
+
+	It("Podman run with specified MAC address and static IP", func() {
+		result := podmanTest.Podman([]string{"run", "-ti", "--mac-address", "92:d0:c6:0a:29:33", "--ip", "10.88.64.128", ALPINE, "ip", "addr"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(result.OutputToString()).To(ContainSubstring("92:d0:c6:0a:29:33"))
+		Expect(result.OutputToString()).To(ContainSubstring("10.88.64.128/16"))
+	})
+
+	It("Podman inspect shows the static addresses of a container", func() {
+		result := podmanTest.Podman([]string{"run", "-d", "--mac-address", "92:d0:c6:0a:29:33", "--ip", "10.88.64.128", ALPINE, "top"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+
+		inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.Config.MacAddress}} {{(index .NetworkSettings.Networks \"podman\").IPAMConfig.IPv4Address}}", "-l"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("92:d0:c6:0a:29:33 10.88.64.128"))
+	})
+
+	It("Podman run with specified MAC address brings up loopback", func() {
+		result := podmanTest.Podman([]string{"run", "-ti", "--mac-address", "92:d0:c6:0a:29:33", ALPINE, "ip", "link", "show", "lo"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(result.OutputToString()).To(ContainSubstring("UP"))
+
+		result = podmanTest.Podman([]string{"run", "-ti", "--mac-address", "92:d0:c6:0a:29:33", ALPINE, "ping", "-c", "1", "127.0.0.1"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+	})
+
+	It("Podman run with static IPv4 and IPv6 addresses on one network", func() {
+		conf := `{
+  "cniVersion": "0.3.1",
+  "name": "podmandualstack",
+  "plugins": [
+    {
+      "type": "bridge",
+      "bridge": "cni-dualstack",
+      "isGateway": true,
+      "capabilities": {"ips": true, "mac": true},
+      "ipam": {
+        "type": "host-local",
+        "ranges": [
+          [{"subnet": "10.89.64.0/24"}],
+          [{"subnet": "fd00:89:64::/64"}]
+        ]
+      }
+    }
+  ]
+}`
+		confFile := filepath.Join(podmanTest.CNIConfigDir, "podmandualstack.conflist")
+		Expect(ioutil.WriteFile(confFile, []byte(conf), 0644)).To(BeNil())
+		defer os.Remove(confFile)
+		defer os.RemoveAll("/var/lib/cni/networks/podmandualstack")
+
+		result := podmanTest.Podman([]string{"run", "-ti", "--network", "podmandualstack", "--ip", "10.89.64.10", "--ip6", "fd00:89:64::10", ALPINE, "ip", "addr"})
+		result.WaitWithDefaultTimeout()
+		Expect(result.ExitCode()).To(Equal(0))
+		Expect(result.OutputToString()).To(ContainSubstring("10.89.64.10/24"))
+		Expect(result.OutputToString()).To(ContainSubstring("fd00:89:64::10/64"))
+	})
 })
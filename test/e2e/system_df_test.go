@@ -0,0 +1,98 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("podman system df", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman system df", func() {
+		session := podmanTest.RunTopContainer("")
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"volume", "create", "dfvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"system", "df", "--format", "{{.Type}}:{{.Total}}:{{.Active}}"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		output := session.OutputToStringArray()
+		Expect(len(output)).To(Equal(3))
+		Expect(output[1]).To(Equal("Containers:1:1"))
+		Expect(output[2]).To(Equal("Local Volumes:1:0"))
+
+		images := podmanTest.Podman([]string{"images", "-q"})
+		images.WaitWithDefaultTimeout()
+		Expect(images.ExitCode()).To(Equal(0))
+		Expect(output[0]).To(Equal(fmt.Sprintf("Images:%d:1", len(images.OutputToStringArray()))))
+	})
+
+	It("podman system df json", func() {
+		session := podmanTest.Podman([]string{"system", "df", "--format", "json"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.IsJSONOutputValid()).To(BeTrue())
+	})
+
+	It("podman system df -v", func() {
+		session := podmanTest.Podman([]string{"run", "-v", "dfvol:/data", "--name", "dfctr", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"system", "df", "-v"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		output := session.OutputToString()
+		Expect(output).To(ContainSubstring("Images space usage:"))
+		Expect(output).To(ContainSubstring("Containers space usage:"))
+		Expect(output).To(ContainSubstring("Local Volumes space usage:"))
+		Expect(session.LineInOutputContains("dfctr")).To(BeTrue())
+
+		var found bool
+		for _, line := range session.OutputToStringArray() {
+			fields := strings.Fields(line)
+			if len(fields) == 3 && fields[0] == "dfvol" {
+				Expect(fields[1]).To(Equal("1"))
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	It("podman system df with --verbose and --format", func() {
+		session := podmanTest.Podman([]string{"system", "df", "-v", "--format", "json"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+})
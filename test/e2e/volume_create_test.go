@@ -5,6 +5,7 @@ package integration
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	. "github.com/containers/libpod/test/utils"
 	. "github.com/onsi/ginkgo"
@@ -59,4 +60,99 @@ var _ = Describe("Podman volume create", func() {
 		Expect(match).To(BeTrue())
 		Expect(len(check.OutputToStringArray())).To(Equal(1))
 	})
+
+	It("podman create volume with invalid options", func() {
+		session := podmanTest.Podman([]string{"volume", "create", "-o", "size=64m", "myvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"volume", "create", "-o", "type=ext4", "myvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"volume", "create", "-o", "type=none", "-o", "device=/tmp", "myvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		check := podmanTest.Podman([]string{"volume", "ls", "-q"})
+		check.WaitWithDefaultTimeout()
+		Expect(len(check.OutputToStringArray())).To(Equal(0))
+	})
+
+	It("podman create tmpfs volume", func() {
+		session := podmanTest.Podman([]string{"volume", "create", "-o", "type=tmpfs", "-o", "o=size=2m,mode=0700", "myvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"run", "-d", "--name", "ctr1", "-v", "myvol:/data", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		session = podmanTest.Podman([]string{"run", "-d", "--name", "ctr2", "-v", "myvol:/data", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		inspect := podmanTest.Podman([]string{"volume", "inspect", "--format", "{{.MountCount}}", "myvol"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("2"))
+
+		exec := podmanTest.Podman([]string{"exec", "ctr1", "sh", "-c", "echo hello > /data/file; grep /data /proc/mounts"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(ContainSubstring("tmpfs"))
+		Expect(exec.OutputToString()).To(ContainSubstring("size=2048k"))
+
+		exec = podmanTest.Podman([]string{"exec", "ctr2", "cat", "/data/file"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(Equal("hello"))
+
+		session = podmanTest.Podman([]string{"stop", "ctr1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		inspect = podmanTest.Podman([]string{"volume", "inspect", "--format", "{{.MountCount}}", "myvol"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("1"))
+
+		session = podmanTest.Podman([]string{"rm", "-f", "ctr2"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		inspect = podmanTest.Podman([]string{"volume", "inspect", "--format", "{{.MountCount}}", "myvol"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("0"))
+
+		// The contents of the tmpfs are gone once it was unmounted
+		session = podmanTest.Podman([]string{"start", "ctr1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		exec = podmanTest.Podman([]string{"exec", "ctr1", "ls", "/data"})
+		exec.WaitWithDefaultTimeout()
+		Expect(exec.ExitCode()).To(Equal(0))
+		Expect(exec.OutputToString()).To(Equal(""))
+
+		session = podmanTest.Podman([]string{"rm", "-f", "ctr1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+	})
+
+	It("podman create bind volume", func() {
+		hostDir := filepath.Join(tempdir, "hostdir")
+		err := os.Mkdir(hostDir, 0755)
+		Expect(err).To(BeNil())
+
+		session := podmanTest.Podman([]string{"volume", "create", "-o", "type=none", "-o", "o=bind", "-o", "device=" + hostDir, "myvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"run", "--rm", "-v", "myvol:/data", ALPINE, "touch", "/data/file"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		_, err = os.Stat(filepath.Join(hostDir, "file"))
+		Expect(err).To(BeNil())
+	})
 })
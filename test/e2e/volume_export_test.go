@@ -0,0 +1,108 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("Podman volume export and import", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+	)
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+	})
+
+	AfterEach(func() {
+		podmanTest.CleanupVolume()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman volume export and import", func() {
+		session := podmanTest.Podman([]string{"volume", "create", "srcvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"run", "--rm", "-v", "srcvol:/data", ALPINE, "sh", "-c", "echo hello > /data/file; chown 1234:5678 /data/file; mkdir /data/dir; ln -s file /data/link; ln /data/file /data/dir/hardlink; chown 4321:8765 /data; chmod 1770 /data"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		inspect := podmanTest.Podman([]string{"volume", "inspect", "--format", "{{.MountPoint}}", "srcvol"})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect.ExitCode()).To(Equal(0))
+		// Not all filesystems support user attributes
+		xattrErr := unix.Lsetxattr(filepath.Join(inspect.OutputToString(), "file"), "user.podman", []byte("test"), 0)
+		if xattrErr == nil {
+			xattrErr = unix.Lsetxattr(inspect.OutputToString(), "user.podman", []byte("root"), 0)
+		}
+
+		tarball := filepath.Join(tempdir, "vol.tar")
+		session = podmanTest.Podman([]string{"volume", "export", "-o", tarball, "srcvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"volume", "create", "dstvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"volume", "import", "dstvol", tarball})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"run", "--rm", "-v", "dstvol:/data", ALPINE, "sh", "-c", "cat /data/file; stat -c %u:%g /data/file; readlink /data/link; stat -c %h /data/dir/hardlink; stat -c %u:%g:%a /data"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(Equal([]string{"hello", "1234:5678", "file", "2", "4321:8765:1770"}))
+
+		if xattrErr == nil {
+			inspect = podmanTest.Podman([]string{"volume", "inspect", "--format", "{{.MountPoint}}", "dstvol"})
+			inspect.WaitWithDefaultTimeout()
+			Expect(inspect.ExitCode()).To(Equal(0))
+			value := make([]byte, 64)
+			size, err := unix.Lgetxattr(filepath.Join(inspect.OutputToString(), "file"), "user.podman", value)
+			Expect(err).To(BeNil())
+			Expect(string(value[:size])).To(Equal("test"))
+			size, err = unix.Lgetxattr(inspect.OutputToString(), "user.podman", value)
+			Expect(err).To(BeNil())
+			Expect(string(value[:size])).To(Equal("root"))
+		}
+	})
+
+	It("podman volume import into missing volume", func() {
+		tarball := filepath.Join(tempdir, "vol.tar")
+		session := podmanTest.Podman([]string{"volume", "create", "srcvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		session = podmanTest.Podman([]string{"volume", "export", "-o", tarball, "srcvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"volume", "import", "missing", tarball})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+
+	It("podman volume export missing volume", func() {
+		session := podmanTest.Podman([]string{"volume", "export", "-o", filepath.Join(tempdir, "vol.tar"), "missing"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
+})
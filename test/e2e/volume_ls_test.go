@@ -83,4 +83,50 @@ var _ = Describe("Podman volume ls", func() {
 		Expect(len(session.OutputToStringArray())).To(Equal(2))
 		Expect(session.OutputToStringArray()[1]).To(ContainSubstring(volName))
 	})
+
+	It("podman ls volume with name, dangling and multiple filters", func() {
+		session := podmanTest.Podman([]string{"volume", "create", "--label", "foo=bar", "usedvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"volume", "create", "--label", "foo=baz", "unusedvol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"create", "-v", "usedvol:/data", ALPINE, "ls"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podmanTest.Podman([]string{"volume", "ls", "-q", "--filter", "name=used"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(ConsistOf("usedvol", "unusedvol"))
+
+		session = podmanTest.Podman([]string{"volume", "ls", "-q", "--filter", "dangling=true"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(ConsistOf("unusedvol"))
+
+		session = podmanTest.Podman([]string{"volume", "ls", "-q", "--filter", "label=foo=bar", "--filter", "driver=local"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(ConsistOf("usedvol"))
+
+		session = podmanTest.Podman([]string{"volume", "ls", "-q", "--filter", "label=foo,dangling=false"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(session.OutputToStringArray()).To(ConsistOf("usedvol"))
+
+		podmanTest.Cleanup()
+	})
+
+	It("podman ls volume with invalid filter", func() {
+		session := podmanTest.Podman([]string{"volume", "ls", "--filter", "color=blue"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		session = podmanTest.Podman([]string{"volume", "ls", "--filter", "dangling=maybe"})
+		session.WaitWithDefaultTimeout()
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+	})
 })
@@ -0,0 +1,196 @@
+// +build !remoteclient
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/containers/libpod/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// testVolumePlugin is a volume plugin serving directories in a temporary
+// directory over the volume plugin protocol
+type testVolumePlugin struct {
+	lock     sync.Mutex
+	dir      string
+	listener net.Listener
+	options  map[string]map[string]string
+	mounts   map[string]map[string]bool
+}
+
+type testVolumePluginRequest struct {
+	Name string
+	Opts map[string]string
+	ID   string
+}
+
+func startTestVolumePlugin(pluginDir, volumeDir, name string) (*testVolumePlugin, error) {
+	p := &testVolumePlugin{
+		dir:     volumeDir,
+		options: make(map[string]map[string]string),
+		mounts:  make(map[string]map[string]bool),
+	}
+	handlers := map[string]func(req *testVolumePluginRequest) (interface{}, error){
+		"/Plugin.Activate": func(req *testVolumePluginRequest) (interface{}, error) {
+			return map[string][]string{"Implements": {"VolumeDriver"}}, nil
+		},
+		"/VolumeDriver.Create": func(req *testVolumePluginRequest) (interface{}, error) {
+			p.options[req.Name] = req.Opts
+			p.mounts[req.Name] = make(map[string]bool)
+			return struct{}{}, os.Mkdir(filepath.Join(p.dir, req.Name), 0755)
+		},
+		"/VolumeDriver.Remove": func(req *testVolumePluginRequest) (interface{}, error) {
+			if len(p.mounts[req.Name]) > 0 {
+				return nil, fmt.Errorf("volume %s is mounted", req.Name)
+			}
+			return struct{}{}, os.RemoveAll(filepath.Join(p.dir, req.Name))
+		},
+		"/VolumeDriver.Mount": func(req *testVolumePluginRequest) (interface{}, error) {
+			p.mounts[req.Name][req.ID] = true
+			return map[string]string{"Mountpoint": filepath.Join(p.dir, req.Name)}, nil
+		},
+		"/VolumeDriver.Unmount": func(req *testVolumePluginRequest) (interface{}, error) {
+			delete(p.mounts[req.Name], req.ID)
+			return struct{}{}, nil
+		},
+	}
+
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		handler := handler
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			req := new(testVolumePluginRequest)
+			json.NewDecoder(r.Body).Decode(req)
+			response, err := handler(req)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				response = map[string]string{"Err": err.Error()}
+			}
+			json.NewEncoder(w).Encode(response)
+		})
+	}
+
+	listener, err := net.Listen("unix", filepath.Join(pluginDir, name+".sock"))
+	if err != nil {
+		return nil, err
+	}
+	p.listener = listener
+	go http.Serve(listener, mux)
+	return p, nil
+}
+
+func (p *testVolumePlugin) mountCount(name string) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.mounts[name])
+}
+
+var _ = Describe("Podman volume plugins", func() {
+	var (
+		tempdir    string
+		err        error
+		podmanTest *PodmanTestIntegration
+		pluginDir  string
+		volumeDir  string
+		plugin     *testVolumePlugin
+	)
+
+	podman := func(args ...string) *PodmanSessionIntegration {
+		session := podmanTest.Podman(append([]string{"--volume-plugin-dir", pluginDir}, args...))
+		session.WaitWithDefaultTimeout()
+		return session
+	}
+
+	BeforeEach(func() {
+		tempdir, err = CreateTempDirInTempDir()
+		if err != nil {
+			os.Exit(1)
+		}
+		podmanTest = PodmanTestCreate(tempdir)
+		podmanTest.RestoreAllArtifacts()
+
+		pluginDir = filepath.Join(tempdir, "plugins")
+		volumeDir = filepath.Join(tempdir, "plugin-volumes")
+		Expect(os.Mkdir(pluginDir, 0755)).To(BeNil())
+		Expect(os.Mkdir(volumeDir, 0755)).To(BeNil())
+		plugin, err = startTestVolumePlugin(pluginDir, volumeDir, "testplugin")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		// Remove everything using the plugin while it is still running
+		podman("rm", "-fa")
+		podman("volume", "rm", "-fa")
+		plugin.listener.Close()
+		podmanTest.Cleanup()
+		f := CurrentGinkgoTestDescription()
+		timedResult := fmt.Sprintf("Test: %s completed in %f seconds", f.TestText, f.Duration.Seconds())
+		GinkgoWriter.Write([]byte(timedResult))
+	})
+
+	It("podman volume create with plugin driver", func() {
+		session := podman("volume", "create", "--driver", "testplugin", "-o", "size=1G", "pluginvol")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(plugin.options["pluginvol"]).To(HaveKeyWithValue("size", "1G"))
+
+		inspect := podman("volume", "inspect", "--format", "{{.Driver}}", "pluginvol")
+		Expect(inspect.ExitCode()).To(Equal(0))
+		Expect(inspect.OutputToString()).To(Equal("testplugin"))
+
+		session = podman("volume", "rm", "pluginvol")
+		Expect(session.ExitCode()).To(Equal(0))
+		_, err := os.Stat(filepath.Join(volumeDir, "pluginvol"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("podman volume create with missing plugin", func() {
+		session := podman("volume", "create", "--driver", "missing", "pluginvol")
+		Expect(session.ExitCode()).To(Not(Equal(0)))
+
+		volumes := podman("volume", "ls", "-q")
+		Expect(volumes.ExitCode()).To(Equal(0))
+		Expect(len(volumes.OutputToStringArray())).To(Equal(0))
+	})
+
+	It("podman run with plugin volume", func() {
+		session := podman("volume", "create", "--driver", "testplugin", "pluginvol")
+		Expect(session.ExitCode()).To(Equal(0))
+
+		session = podman("run", "-d", "--name", "pluginctr", "-v", "pluginvol:/data", ALPINE, "sh", "-c", "echo hello > /data/file; top")
+		Expect(session.ExitCode()).To(Equal(0))
+		Eventually(func() error {
+			_, err := os.Stat(filepath.Join(volumeDir, "pluginvol", "file"))
+			return err
+		}, 10).Should(BeNil())
+		Expect(plugin.mountCount("pluginvol")).To(Equal(1))
+
+		content, err := ioutil.ReadFile(filepath.Join(volumeDir, "pluginvol", "file"))
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("hello\n"))
+
+		session = podman("stop", "pluginctr")
+		Expect(session.ExitCode()).To(Equal(0))
+		Eventually(func() int {
+			return plugin.mountCount("pluginvol")
+		}, 10).Should(Equal(0))
+
+		session = podman("start", "pluginctr")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(plugin.mountCount("pluginvol")).To(Equal(1))
+
+		session = podman("rm", "-f", "pluginctr")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(plugin.mountCount("pluginvol")).To(Equal(0))
+	})
+})